@@ -0,0 +1,109 @@
+package actions
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/core/attestation"
+)
+
+var (
+	certificateOperatorSetFlag = &cli.StringFlag{
+		Name:  "operator-set",
+		Usage: "Operator set file of the reference block for the offline verification, defaults to the operators_<reference block>.json next to the certificate",
+	}
+	certificateRpcUrlFlag = &cli.StringFlag{
+		Name:  "rpc-url",
+		Usage: "Verify through an eth_call to the service manager at this RPC instead of offline",
+	}
+	certificateServiceManagerFlag = &cli.StringFlag{
+		Name:  "service-manager",
+		Usage: "Address of the AlignedLayerServiceManager, required with --rpc-url",
+	}
+	certificateSignatureOnlyFlag = &cli.BoolFlag{
+		Name:  "signature-only",
+		Usage: "With --rpc-url, only check the signature with trySignatureAndApkVerification, for certificates too old for checkSignatures",
+	}
+)
+
+// The certificates are read from the files written by the aggregator, and checked without a running aggregator:
+// `aligned-aggregator --config <file> certificate verify <certificate>` checks it offline against the operator set of its reference block,
+// `aligned-aggregator --config <file> certificate verify <certificate> --rpc-url <url> --service-manager <address>` checks it on-chain
+var CertificateCommand = &cli.Command{
+	Name:  "certificate",
+	Usage: "Check the attestation certificates of the tasks that reached quorum",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "verify",
+			Usage:     "Verify an attestation certificate, offline or through an eth_call",
+			ArgsUsage: "<certificate_file>",
+			Flags:     []cli.Flag{certificateOperatorSetFlag, certificateRpcUrlFlag, certificateServiceManagerFlag, certificateSignatureOnlyFlag},
+			Action:    verifyCertificateMain,
+		},
+	},
+}
+
+func verifyCertificateMain(ctx *cli.Context) error {
+	path := ctx.Args().First()
+	if path == "" {
+		return fmt.Errorf("certificate file is required")
+	}
+	certificate, err := attestation.ReadCertificate(path)
+	if err != nil {
+		return err
+	}
+
+	rpcUrl := ctx.String(certificateRpcUrlFlag.Name)
+	if rpcUrl == "" {
+		operatorSetPath := ctx.String(certificateOperatorSetFlag.Name)
+		if operatorSetPath == "" {
+			operatorSetPath = filepath.Join(filepath.Dir(path), attestation.OperatorSetFileName(certificate.ReferenceBlock))
+		}
+		operatorSet, err := attestation.ReadOperatorSet(operatorSetPath)
+		if err != nil {
+			return fmt.Errorf("error reading operator set: %w", err)
+		}
+		if err := attestation.VerifyOffline(certificate, operatorSet); err != nil {
+			return err
+		}
+		fmt.Printf("Certificate of batch %s is valid: signed by %d of the %d operators registered at block %d. Stakes are only checked with --rpc-url\n",
+			certificate.BatchIdentifierHash, len(certificate.Signers), len(operatorSet), certificate.ReferenceBlock)
+		return nil
+	}
+
+	serviceManagerAddress := ctx.String(certificateServiceManagerFlag.Name)
+	if !common.IsHexAddress(serviceManagerAddress) {
+		return fmt.Errorf("a valid --service-manager address is required with --rpc-url")
+	}
+	client, err := ethclient.Dial(rpcUrl)
+	if err != nil {
+		return fmt.Errorf("error connecting to %s: %w", rpcUrl, err)
+	}
+	defer client.Close()
+	serviceManager, err := servicemanager.NewContractAlignedLayerServiceManagerCaller(common.HexToAddress(serviceManagerAddress), client)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(certificateSignatureOnlyFlag.Name) {
+		if err := attestation.VerifySignatureOnChain(ctx.Context, serviceManager, certificate); err != nil {
+			return err
+		}
+		fmt.Printf("Certificate of batch %s has a valid signature. Stakes were not checked\n", certificate.BatchIdentifierHash)
+		return nil
+	}
+
+	verification, err := attestation.VerifyOnChain(ctx.Context, serviceManager, certificate)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Certificate of batch %s is valid\n", certificate.BatchIdentifierHash)
+	for i, quorumNumber := range certificate.QuorumNumbers {
+		fmt.Printf("Quorum %d: signed stake %s of %s\n", quorumNumber, verification.SignedStakeForQuorum[i], verification.TotalStakeForQuorum[i])
+	}
+	return nil
+}
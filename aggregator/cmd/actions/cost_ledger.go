@@ -0,0 +1,102 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/aggregator/pkg"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+var (
+	costLedgerFormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format, csv or json",
+		Value: "csv",
+	}
+	costLedgerFromFlag = &cli.StringFlag{
+		Name:  "from",
+		Usage: "Only export entries at or after this date (YYYY-MM-DD or RFC 3339)",
+	}
+	costLedgerToFlag = &cli.StringFlag{
+		Name:  "to",
+		Usage: "Only export entries before this date (YYYY-MM-DD or RFC 3339)",
+	}
+	costLedgerSenderFlag = &cli.StringFlag{
+		Name:  "sender",
+		Usage: "Only export entries of the batches of this sender address",
+	}
+)
+
+// The cost ledger is read from the file written by the aggregator, so it can be exported
+// without a running aggregator:
+// `aligned-aggregator --config <file> cost-ledger export --format csv --from 2024-01-01 --sender 0x...`
+var CostLedgerCommand = &cli.Command{
+	Name:  "cost-ledger",
+	Usage: "Query the cost of the respond to task transactions",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "export",
+			Usage:     "Export the cost ledger entries as CSV or JSON",
+			ArgsUsage: "[file] (defaults to cost_ledger_path)",
+			Flags:     []cli.Flag{costLedgerFormatFlag, costLedgerFromFlag, costLedgerToFlag, costLedgerSenderFlag},
+			Action:    exportCostLedgerMain,
+		},
+	},
+}
+
+func exportCostLedgerMain(ctx *cli.Context) error {
+	path := ctx.Args().First()
+	if path == "" {
+		var aggregatorConfigFromYaml config.AggregatorConfigFromYaml
+		err := utils.ReadYamlConfig(ctx.String(config.ConfigFileFlag.Name), &aggregatorConfigFromYaml)
+		if err != nil {
+			return fmt.Errorf("error reading aggregator config: %w", err)
+		}
+		if aggregatorConfigFromYaml.Aggregator.CostLedgerPath == "" {
+			return fmt.Errorf("no file given and cost_ledger_path is not set in the config file")
+		}
+		path = aggregatorConfigFromYaml.Aggregator.CostLedgerPath
+	}
+
+	filter := pkg.CostLedgerFilter{SenderAddress: ctx.String(costLedgerSenderFlag.Name)}
+	var err error
+	if from := ctx.String(costLedgerFromFlag.Name); from != "" {
+		if filter.From, err = pkg.ParseCostLedgerDate(from); err != nil {
+			return err
+		}
+	}
+	if to := ctx.String(costLedgerToFlag.Name); to != "" {
+		if filter.To, err = pkg.ParseCostLedgerDate(to); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	entries, err := pkg.ReadCostLedger(file)
+	_ = file.Close()
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+	entries = filter.Apply(entries)
+
+	switch ctx.String(costLedgerFormatFlag.Name) {
+	case "csv":
+		return pkg.WriteCostLedgerCsv(os.Stdout, entries)
+	case "json":
+		if entries == nil {
+			entries = []pkg.CostLedgerEntry{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	default:
+		return fmt.Errorf("invalid format %q, expected csv or json", ctx.String(costLedgerFormatFlag.Name))
+	}
+}
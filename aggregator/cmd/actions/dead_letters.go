@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// The dead letter commands talk to the admin API of a running aggregator,
+// so replays go through the same wallet and nonce as the rest of its transactions.
+// They read the admin API address and token from the aggregator config, replays take admin_api_write_token:
+// `aligned-aggregator --config <file> dead-letters list`
+var DeadLettersCommand = &cli.Command{
+	Name:  "dead-letters",
+	Usage: "Inspect and replay aggregated responses that failed to be sent",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "list",
+			Usage:  "List the stored dead letters",
+			Action: listDeadLettersMain,
+		},
+		{
+			Name:      "replay",
+			Usage:     "Replay the dead letter of the given batch identifier hash",
+			ArgsUsage: "<batch_identifier_hash>",
+			Action:    replayDeadLetterMain,
+		},
+	},
+}
+
+func listDeadLettersMain(ctx *cli.Context) error {
+	return adminApiRequest(ctx, http.MethodGet, "/dead-letters", false)
+}
+
+func replayDeadLetterMain(ctx *cli.Context) error {
+	batchIdentifierHash := ctx.Args().First()
+	if batchIdentifierHash == "" {
+		return fmt.Errorf("batch identifier hash is required")
+	}
+	return adminApiRequest(ctx, http.MethodPost, "/dead-letters/"+batchIdentifierHash+"/replay", true)
+}
+
+// adminApiRequest sends a request to the admin API configured in the aggregator config file
+// and prints the response body. Requests that change state are sent with the write token
+func adminApiRequest(ctx *cli.Context, method string, path string, write bool) error {
+	var aggregatorConfigFromYaml config.AggregatorConfigFromYaml
+	err := utils.ReadYamlConfig(ctx.String(config.ConfigFileFlag.Name), &aggregatorConfigFromYaml)
+	if err != nil {
+		return fmt.Errorf("error reading aggregator config: %w", err)
+	}
+	address := aggregatorConfigFromYaml.Aggregator.AdminApiIpPortAddress
+	if address == "" {
+		return fmt.Errorf("admin_api_ip_port_address is not set in the config file")
+	}
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+
+	request, err := http.NewRequest(method, address+path, nil)
+	if err != nil {
+		return err
+	}
+	token := aggregatorConfigFromYaml.Aggregator.AdminApiAuthToken
+	if write {
+		token = aggregatorConfigFromYaml.Aggregator.AdminApiWriteToken
+		if token == "" {
+			return fmt.Errorf("admin_api_write_token is not set in the config file")
+		}
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	client := http.Client{Timeout: 30 * time.Second}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error calling admin API: %w", err)
+	}
+	defer response.Body.Close()
+
+	_, err = io.Copy(os.Stdout, response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("admin API returned %s", response.Status)
+	}
+	return nil
+}
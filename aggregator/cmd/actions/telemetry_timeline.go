@@ -0,0 +1,75 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/aggregator/pkg"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+var batchMerkleRootFlag = &cli.StringFlag{
+	Name:  "batch",
+	Usage: "Only show the timeline of the batch with this merkle root",
+}
+
+// The timeline is rebuilt from the JSON lines written by the file or stdout telemetry sinks,
+// so no telemetry_api is needed to debug the aggregator locally:
+// `aligned-aggregator --config <file> telemetry-timeline [files...]`
+var TelemetryTimelineCommand = &cli.Command{
+	Name:      "telemetry-timeline",
+	Usage:     "Print the timeline of each batch from telemetry files",
+	ArgsUsage: "[files...] (defaults to telemetry_file_path and its rotated files)",
+	Flags:     []cli.Flag{batchMerkleRootFlag},
+	Action:    telemetryTimelineMain,
+}
+
+func telemetryTimelineMain(ctx *cli.Context) error {
+	files := ctx.Args().Slice()
+	if len(files) == 0 {
+		var aggregatorConfigFromYaml config.AggregatorConfigFromYaml
+		err := utils.ReadYamlConfig(ctx.String(config.ConfigFileFlag.Name), &aggregatorConfigFromYaml)
+		if err != nil {
+			return fmt.Errorf("error reading aggregator config: %w", err)
+		}
+		if aggregatorConfigFromYaml.Aggregator.TelemetryFilePath == "" {
+			return fmt.Errorf("no files given and telemetry_file_path is not set in the config file")
+		}
+		files = pkg.TelemetryFiles(aggregatorConfigFromYaml.Aggregator.TelemetryFilePath)
+	}
+
+	var events []pkg.TelemetryEvent
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		fileEvents, err := pkg.ReadTelemetryEvents(file)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", path, err)
+		}
+		events = append(events, fileEvents...)
+	}
+
+	batchMerkleRoot := strings.ToLower(ctx.String(batchMerkleRootFlag.Name))
+	if batchMerkleRoot != "" && !strings.HasPrefix(batchMerkleRoot, "0x") {
+		batchMerkleRoot = "0x" + batchMerkleRoot
+	}
+	for _, timeline := range pkg.BuildTaskTimelines(events) {
+		if batchMerkleRoot != "" && timeline.BatchMerkleRoot != batchMerkleRoot {
+			continue
+		}
+		fmt.Printf("Batch %s\n", timeline.BatchMerkleRoot)
+		start := timeline.Start()
+		for _, entry := range timeline.Entries {
+			fmt.Printf("  %s  +%-10v %s\n", entry.Time.Format("2006-01-02 15:04:05.000"), entry.Time.Sub(start).Round(time.Millisecond), entry.Description)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/aggregator/cmd/actions"
 	"github.com/yetanotherco/aligned_layer/aggregator/pkg"
 	"github.com/yetanotherco/aligned_layer/core/config"
 )
@@ -31,6 +32,12 @@ func main() {
 	app.Usage = "Aligned Layer Aggregator"
 	app.Description = "Service that aggregates signed responses from operator nodes."
 	app.Action = aggregatorMain
+	app.Commands = []*cli.Command{
+		actions.DeadLettersCommand,
+		actions.TelemetryTimelineCommand,
+		actions.CostLedgerCommand,
+		actions.CertificateCommand,
+	}
 
 	err := app.Run(os.Args)
 	if err != nil {
@@ -49,6 +56,9 @@ func aggregatorMain(ctx *cli.Context) error {
 		return err
 	}
 
+	// Re-initialize the tasks that were pending before the last shutdown
+	aggregator.RecoverPendingTasks()
+
 	// Supervisor revives garbage collector
 	go func() {
 		for {
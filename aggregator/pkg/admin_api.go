@@ -0,0 +1,573 @@
+package pkg
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+const (
+	AdminApiDefaultTasksLimit = 50
+	AdminApiMaxTasksLimit     = 1000
+	// Placeholder tokens of the sample config, known to anyone reading the repo
+	adminApiPlaceholderToken      = "<admin_api_auth_token>"
+	adminApiPlaceholderWriteToken = "<admin_api_write_token>"
+)
+
+type AdminTaskSummary struct {
+	BatchIdentifierHash string   `json:"batch_identifier_hash"`
+	BatchMerkleRoot     string   `json:"batch_merkle_root"`
+	SenderAddress       string   `json:"sender_address"`
+	TaskIndex           uint32   `json:"task_index"`
+	TaskCreatedBlock    uint32   `json:"task_created_block"`
+	Version             string   `json:"version"`
+	BatchDataPointer    string   `json:"batch_data_pointer,omitempty"`
+	Status              string   `json:"status"`
+	CreatedAt           string   `json:"created_at"`
+	TimeToQuorumSeconds *float64 `json:"time_to_quorum_seconds"`
+	SignersCount        int      `json:"signers_count"`
+	RespondToTaskTries  int      `json:"respond_to_task_tries"`
+	TxHash              string   `json:"tx_hash,omitempty"`
+	// Nil if the feasibility could not be checked
+	Feasible         *bool    `json:"feasible,omitempty"`
+	FeasibilityScore *float64 `json:"feasibility_score,omitempty"`
+	InfeasibleReason string   `json:"infeasible_reason,omitempty"`
+}
+
+type AdminQuorumStake struct {
+	QuorumNumber          uint8   `json:"quorum_number"`
+	ThresholdPercentage   uint8   `json:"threshold_percentage"`
+	TotalStake            string  `json:"total_stake"`
+	SignedStake           string  `json:"signed_stake"`
+	SignedStakePercentage float64 `json:"signed_stake_percentage"`
+}
+
+type AdminOperatorStake struct {
+	OperatorId string           `json:"operator_id"`
+	Stake      map[uint8]string `json:"stake"`
+	ReceivedAt string           `json:"received_at,omitempty"`
+}
+
+type AdminTaskDetail struct {
+	AdminTaskSummary
+	Quorums           []AdminQuorumStake     `json:"quorums"`
+	Signers           []AdminOperatorStake   `json:"signers"`
+	NonSigners        []AdminOperatorStake   `json:"non_signers"`
+	StakeError        string                 `json:"stake_error,omitempty"`
+	Attempts          []RespondToTaskAttempt `json:"respond_to_task_attempts"`
+	EffectiveGasPrice string                 `json:"effective_gas_price,omitempty"`
+	Error             string                 `json:"error,omitempty"`
+}
+
+type AdminDeadLetter struct {
+	BatchIdentifierHash string `json:"batch_identifier_hash"`
+	BatchMerkleRoot     string `json:"batch_merkle_root"`
+	SenderAddress       string `json:"sender_address"`
+	TaskCreatedBlock    uint32 `json:"task_created_block"`
+	Error               string `json:"error"`
+	FailedAt            string `json:"failed_at"`
+	Attempts            int    `json:"attempts"`
+	Permanent           bool   `json:"permanent"`
+	AggregatorBalance   string `json:"aggregator_balance,omitempty"`
+	BatcherBalance      string `json:"batcher_balance,omitempty"`
+	GasPrice            string `json:"gas_price,omitempty"`
+}
+
+type AdminDeferredResponse struct {
+	BatchIdentifierHash string `json:"batch_identifier_hash"`
+	BatchMerkleRoot     string `json:"batch_merkle_root"`
+	SenderAddress       string `json:"sender_address"`
+	FeeLimit            string `json:"fee_limit"`
+	Gas                 uint64 `json:"gas"`
+	MaxGasPrice         string `json:"max_gas_price"`
+	DeferredAt          string `json:"deferred_at"`
+	// Fee limit left after paying the transaction at the current gas price, negative if it is not enough
+	Headroom string `json:"headroom,omitempty"`
+	// Submitted on the next check regardless of the gas price
+	Expired bool `json:"expired"`
+}
+
+type AdminOperatorStats struct {
+	OperatorId            string   `json:"operator_id"`
+	TasksSigned           uint64   `json:"tasks_signed"`
+	TasksMissed           uint64   `json:"tasks_missed"`
+	InvalidSignatures     uint64   `json:"invalid_signatures"`
+	ResponseRate          float64  `json:"response_rate"`
+	AverageLatencySeconds *float64 `json:"average_latency_seconds"`
+	LastLatencySeconds    *float64 `json:"last_latency_seconds"`
+	LastSeen              string   `json:"last_seen,omitempty"`
+}
+
+type AdminWallet struct {
+	Address    string  `json:"address"`
+	BalanceWei string  `json:"balance_wei"`
+	BalanceEth float64 `json:"balance_eth"`
+}
+
+// ServeAdminApi starts the admin API, on a listener separate from the operators RPC server.
+// Operator performance is served under /operators, the same data is exported as labelled metrics.
+// Every request must carry the configured token as `Authorization: Bearer <token>`. Replaying dead letters, the only
+// route that changes state, takes the write token instead, and is only served if one is set
+func (agg *Aggregator) ServeAdminApi() error {
+	if err := validateAdminApiAuthToken(agg.AggregatorConfig.Aggregator.AdminApiAuthToken); err != nil {
+		return err
+	}
+	writeToken := agg.AggregatorConfig.Aggregator.AdminApiWriteToken
+	if writeToken != "" {
+		if err := validateAdminApiWriteToken(agg.AggregatorConfig.Aggregator.AdminApiAuthToken, writeToken); err != nil {
+			return err
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", agg.adminApiAuth(agg.handleAdminListTasks))
+	mux.HandleFunc("GET /tasks/{batchIdentifierHash}", agg.adminApiAuth(agg.handleAdminGetTask))
+	mux.HandleFunc("GET /tasks/{batchIdentifierHash}/certificate", agg.adminApiAuth(agg.handleAdminGetCertificate))
+	mux.HandleFunc("GET /wallets", agg.adminApiAuth(agg.handleAdminListWallets))
+	mux.HandleFunc("GET /operators", agg.adminApiAuth(agg.handleAdminListOperators))
+	mux.HandleFunc("GET /operators/{operatorId}", agg.adminApiAuth(agg.handleAdminGetOperator))
+	mux.HandleFunc("GET /dead-letters", agg.adminApiAuth(agg.handleAdminListDeadLetters))
+	mux.HandleFunc("GET /deferred-responses", agg.adminApiAuth(agg.handleAdminListDeferredResponses))
+	if writeToken != "" {
+		mux.HandleFunc("POST /dead-letters/{batchIdentifierHash}/replay", agg.adminApiWriteAuth(agg.handleAdminReplayDeadLetter))
+	}
+
+	server := http.Server{
+		Addr:           agg.AggregatorConfig.Aggregator.AdminApiIpPortAddress,
+		Handler:        mux,
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   30 * time.Second,
+		IdleTimeout:    120 * time.Second,
+		MaxHeaderBytes: 1 << 20, // This is 1MB
+	}
+
+	agg.logger.Info("Starting admin API server on address", "address",
+		agg.AggregatorConfig.Aggregator.AdminApiIpPortAddress)
+
+	return server.ListenAndServe()
+}
+
+func (agg *Aggregator) adminApiAuth(handler http.HandlerFunc) http.HandlerFunc {
+	return adminApiTokenAuth(agg.AggregatorConfig.Aggregator.AdminApiAuthToken, handler)
+}
+
+// adminApiWriteAuth protects the routes that change state, which the read token must not grant
+func (agg *Aggregator) adminApiWriteAuth(handler http.HandlerFunc) http.HandlerFunc {
+	return adminApiTokenAuth(agg.AggregatorConfig.Aggregator.AdminApiWriteToken, handler)
+}
+
+func adminApiTokenAuth(token string, handler http.HandlerFunc) http.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeAdminError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// GET /tasks?status=pending|recent&limit=N
+// pending tasks have not been responded yet, recent tasks were either responded, confirmed or failed
+func (agg *Aggregator) handleAdminListTasks(w http.ResponseWriter, r *http.Request) {
+	limit := AdminApiDefaultTasksLimit
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		parsedLimit, err := strconv.Atoi(limitParam)
+		if err != nil || parsedLimit <= 0 {
+			writeAdminError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = min(parsedLimit, AdminApiMaxTasksLimit)
+	}
+
+	var filter func(task *TaskInfo) bool
+	switch r.URL.Query().Get("status") {
+	case "":
+		filter = func(task *TaskInfo) bool { return true }
+	case "pending":
+		filter = func(task *TaskInfo) bool {
+			return task.Status == TaskStatusPending || task.Status == TaskStatusQuorumReached || task.Status == TaskStatusDeferred
+		}
+	case "infeasible":
+		filter = func(task *TaskInfo) bool {
+			return task.Feasibility != nil && !task.Feasibility.Feasible
+		}
+	case "recent":
+		filter = func(task *TaskInfo) bool {
+			return task.Status == TaskStatusResponded || task.Status == TaskStatusConfirmed || task.Status == TaskStatusFailed
+		}
+	default:
+		writeAdminError(w, http.StatusBadRequest, "invalid status, expected pending, infeasible or recent")
+		return
+	}
+
+	tasks := agg.taskInfos.list(filter)
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	summaries := make([]AdminTaskSummary, 0, len(tasks))
+	for i := range tasks {
+		summaries = append(summaries, newAdminTaskSummary(&tasks[i]))
+	}
+	writeAdminJson(w, summaries)
+}
+
+// GET /tasks/{batchIdentifierHash}
+func (agg *Aggregator) handleAdminGetTask(w http.ResponseWriter, r *http.Request) {
+	batchIdentifierHash, err := parseHash(r.PathValue("batchIdentifierHash"))
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, ok := agg.taskInfos.get(batchIdentifierHash)
+	if !ok {
+		writeAdminError(w, http.StatusNotFound, "task not found")
+		return
+	}
+
+	detail := AdminTaskDetail{
+		AdminTaskSummary:  newAdminTaskSummary(&task),
+		Attempts:          task.Attempts,
+		EffectiveGasPrice: task.EffectiveGasPrice,
+		Error:             task.Error,
+		Quorums:           []AdminQuorumStake{},
+		Signers:           []AdminOperatorStake{},
+		NonSigners:        []AdminOperatorStake{},
+	}
+
+	// Stakes are read at the task created block, which is the reference block of the signature check
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	operatorsState, err := agg.avsRegistryService.GetOperatorsAvsStateAtBlock(ctx, task.QuorumConfig.QuorumNums, eigentypes.BlockNum(task.TaskCreatedBlock))
+	if err != nil {
+		detail.StakeError = fmt.Sprintf("failed to get operators stake: %v", err)
+		writeAdminJson(w, detail)
+		return
+	}
+
+	totalStake := make(map[eigentypes.QuorumNum]*big.Int)
+	signedStake := make(map[eigentypes.QuorumNum]*big.Int)
+	for _, quorumNumber := range task.QuorumConfig.QuorumNums {
+		totalStake[quorumNumber] = big.NewInt(0)
+		signedStake[quorumNumber] = big.NewInt(0)
+	}
+
+	for operatorId, operatorState := range operatorsState {
+		operatorStake := AdminOperatorStake{
+			OperatorId: "0x" + hex.EncodeToString(operatorId[:]),
+			Stake:      make(map[uint8]string),
+		}
+		receivedAt, signed := task.Signers[operatorId]
+		for quorumNumber, stake := range operatorState.StakePerQuorum {
+			operatorStake.Stake[uint8(quorumNumber)] = stake.String()
+			if _, ok := totalStake[quorumNumber]; !ok {
+				continue
+			}
+			totalStake[quorumNumber].Add(totalStake[quorumNumber], stake)
+			if signed {
+				signedStake[quorumNumber].Add(signedStake[quorumNumber], stake)
+			}
+		}
+		if signed {
+			operatorStake.ReceivedAt = receivedAt.UTC().Format(time.RFC3339Nano)
+			detail.Signers = append(detail.Signers, operatorStake)
+		} else {
+			detail.NonSigners = append(detail.NonSigners, operatorStake)
+		}
+	}
+
+	for i, quorumNumber := range task.QuorumConfig.QuorumNums {
+		percentage := 0.0
+		if totalStake[quorumNumber].Sign() > 0 {
+			percentage, _ = new(big.Rat).SetFrac(
+				new(big.Int).Mul(signedStake[quorumNumber], big.NewInt(100)),
+				totalStake[quorumNumber],
+			).Float64()
+		}
+		detail.Quorums = append(detail.Quorums, AdminQuorumStake{
+			QuorumNumber:          uint8(quorumNumber),
+			ThresholdPercentage:   uint8(task.QuorumConfig.QuorumThresholdPercentages[i]),
+			TotalStake:            totalStake[quorumNumber].String(),
+			SignedStake:           signedStake[quorumNumber].String(),
+			SignedStakePercentage: percentage,
+		})
+	}
+
+	writeAdminJson(w, detail)
+}
+
+// GET /tasks/{batchIdentifierHash}/certificate
+// Serves the attestation certificate as written to the certificates directory, so it can be consumed before the
+// response is included on-chain
+func (agg *Aggregator) handleAdminGetCertificate(w http.ResponseWriter, r *http.Request) {
+	batchIdentifierHash, err := parseHash(r.PathValue("batchIdentifierHash"))
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if agg.certificates == nil {
+		writeAdminError(w, http.StatusNotFound, "attestation certificates are not enabled")
+		return
+	}
+
+	certificate, err := os.ReadFile(agg.certificates.Path(batchIdentifierHash))
+	if os.IsNotExist(err) {
+		writeAdminError(w, http.StatusNotFound, "certificate not found")
+		return
+	}
+	if err != nil {
+		writeAdminError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(certificate)
+}
+
+// GET /wallets
+// every wallet of the pool with its balance
+func (agg *Aggregator) handleAdminListWallets(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	wallets := make([]AdminWallet, 0, len(agg.avsWriter.Wallets.Wallets()))
+	for _, wallet := range agg.avsWriter.Wallets.Wallets() {
+		balance, err := agg.avsWriter.BalanceAtRetryable(ctx, wallet.Address, nil, retry.NetworkRetryParams())
+		if err != nil {
+			writeAdminError(w, http.StatusBadGateway, fmt.Sprintf("failed to get balance of %s: %v", wallet.Address.Hex(), err))
+			return
+		}
+		wallets = append(wallets, AdminWallet{
+			Address:    wallet.Address.Hex(),
+			BalanceWei: balance.String(),
+			BalanceEth: utils.WeiToEth(balance),
+		})
+	}
+	writeAdminJson(w, wallets)
+}
+
+// GET /operators
+func (agg *Aggregator) handleAdminListOperators(w http.ResponseWriter, r *http.Request) {
+	operators := agg.operatorLedger.list()
+	response := make([]AdminOperatorStats, 0, len(operators))
+	for i := range operators {
+		response = append(response, newAdminOperatorStats(&operators[i]))
+	}
+	writeAdminJson(w, response)
+}
+
+// GET /operators/{operatorId}
+func (agg *Aggregator) handleAdminGetOperator(w http.ResponseWriter, r *http.Request) {
+	operatorId, err := parseHash(r.PathValue("operatorId"))
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, "invalid operator id")
+		return
+	}
+	stats, ok := agg.operatorLedger.get(operatorId)
+	if !ok {
+		writeAdminError(w, http.StatusNotFound, "operator not found")
+		return
+	}
+	writeAdminJson(w, newAdminOperatorStats(&stats))
+}
+
+func newAdminOperatorStats(stats *OperatorStats) AdminOperatorStats {
+	response := AdminOperatorStats{
+		OperatorId:        "0x" + hex.EncodeToString(stats.OperatorId[:]),
+		TasksSigned:       stats.TasksSigned,
+		TasksMissed:       stats.TasksMissed,
+		InvalidSignatures: stats.InvalidSignatures,
+		ResponseRate:      stats.ResponseRate(),
+	}
+	if stats.TasksSigned > 0 {
+		averageLatency := stats.AverageLatency().Seconds()
+		lastLatency := stats.LastLatency.Seconds()
+		response.AverageLatencySeconds = &averageLatency
+		response.LastLatencySeconds = &lastLatency
+		response.LastSeen = stats.LastSeen.UTC().Format(time.RFC3339Nano)
+	}
+	return response
+}
+
+// GET /dead-letters
+func (agg *Aggregator) handleAdminListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	deadLetters := agg.deadLetterStore.ListDeadLetters()
+	sort.Slice(deadLetters, func(i, j int) bool {
+		return deadLetters[i].FailedAt.After(deadLetters[j].FailedAt)
+	})
+
+	response := make([]AdminDeadLetter, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		response = append(response, AdminDeadLetter{
+			BatchIdentifierHash: "0x" + hex.EncodeToString(deadLetter.BatchIdentifierHash[:]),
+			BatchMerkleRoot:     "0x" + hex.EncodeToString(deadLetter.BatchMerkleRoot[:]),
+			SenderAddress:       "0x" + hex.EncodeToString(deadLetter.SenderAddress[:]),
+			TaskCreatedBlock:    deadLetter.TaskCreatedBlock,
+			Error:               deadLetter.Error,
+			FailedAt:            deadLetter.FailedAt.UTC().Format(time.RFC3339Nano),
+			Attempts:            deadLetter.Attempts,
+			Permanent:           deadLetter.Permanent,
+			AggregatorBalance:   bigIntString(deadLetter.AggregatorBalance),
+			BatcherBalance:      bigIntString(deadLetter.BatcherBalance),
+			GasPrice:            bigIntString(deadLetter.GasPrice),
+		})
+	}
+	writeAdminJson(w, response)
+}
+
+// POST /dead-letters/{batchIdentifierHash}/replay
+// The replay may take several gas bumps, so it runs in the background and the request returns right away
+func (agg *Aggregator) handleAdminReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	batchIdentifierHash, err := parseHash(r.PathValue("batchIdentifierHash"))
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, ok := agg.deadLetterStore.GetDeadLetter(batchIdentifierHash); !ok {
+		writeAdminError(w, http.StatusNotFound, "dead letter not found")
+		return
+	}
+	if !agg.isLeader() {
+		writeAdminError(w, http.StatusConflict, "this aggregator instance is a standby, replay the dead letter on the leader")
+		return
+	}
+
+	agg.logger.Info("Dead letter replay requested through admin API",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+	go func() {
+		_ = agg.ReplayDeadLetter(batchIdentifierHash)
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "replaying"})
+}
+
+// GET /deferred-responses
+// Listed in submission order at the current gas price
+func (agg *Aggregator) handleAdminListDeferredResponses(w http.ResponseWriter, r *http.Request) {
+	response := make([]AdminDeferredResponse, 0)
+	if agg.deferrals == nil {
+		writeAdminJson(w, response)
+		return
+	}
+
+	gasPrice, err := agg.currentGasPrice()
+	if err != nil {
+		gasPrice = nil
+	}
+	now := time.Now()
+	for _, deferred := range agg.deferrals.ordered(gasPrice, now) {
+		var headroom *big.Int
+		if gasPrice != nil {
+			headroom = deferred.headroom(gasPrice)
+		}
+		response = append(response, AdminDeferredResponse{
+			BatchIdentifierHash: "0x" + hex.EncodeToString(deferred.response.BatchIdentifierHash[:]),
+			BatchMerkleRoot:     "0x" + hex.EncodeToString(deferred.response.BatchMerkleRoot[:]),
+			SenderAddress:       "0x" + hex.EncodeToString(deferred.response.SenderAddress[:]),
+			FeeLimit:            deferred.feeLimit.String(),
+			Gas:                 deferred.gas,
+			MaxGasPrice:         deferred.maxGasPrice.String(),
+			DeferredAt:          deferred.deferredAt.UTC().Format(time.RFC3339Nano),
+			Headroom:            bigIntString(headroom),
+			Expired:             agg.deferrals.expired(deferred, now),
+		})
+	}
+	writeAdminJson(w, response)
+}
+
+func bigIntString(value *big.Int) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+func newAdminTaskSummary(task *TaskInfo) AdminTaskSummary {
+	summary := AdminTaskSummary{
+		BatchIdentifierHash: "0x" + hex.EncodeToString(task.BatchIdentifierHash[:]),
+		BatchMerkleRoot:     "0x" + hex.EncodeToString(task.BatchMerkleRoot[:]),
+		SenderAddress:       "0x" + hex.EncodeToString(task.SenderAddress[:]),
+		TaskIndex:           task.TaskIndex,
+		TaskCreatedBlock:    task.TaskCreatedBlock,
+		Version:             task.Version,
+		BatchDataPointer:    task.BatchDataPointer,
+		Status:              task.Status,
+		CreatedAt:           task.CreatedAt.UTC().Format(time.RFC3339Nano),
+		SignersCount:        len(task.Signers),
+		RespondToTaskTries:  len(task.Attempts),
+		TxHash:              task.TxHash,
+	}
+	if !task.QuorumReachedAt.IsZero() {
+		timeToQuorum := task.QuorumReachedAt.Sub(task.CreatedAt).Seconds()
+		summary.TimeToQuorumSeconds = &timeToQuorum
+	}
+	if task.Feasibility != nil {
+		feasible, score := task.Feasibility.Feasible, task.Feasibility.Score
+		summary.Feasible = &feasible
+		summary.FeasibilityScore = &score
+		summary.InfeasibleReason = task.Feasibility.Reason
+	}
+	return summary
+}
+
+// validateAdminApiAuthToken rejects tokens that don't protect the admin API
+func validateAdminApiAuthToken(token string) error {
+	if token == "" {
+		return errors.New("admin api auth token is not set")
+	}
+	if token == adminApiPlaceholderToken {
+		return errors.New("admin api auth token is still the placeholder of the sample config")
+	}
+	return nil
+}
+
+// validateAdminApiWriteToken rejects a write token that the read token, or the sample config, would give away
+func validateAdminApiWriteToken(readToken string, writeToken string) error {
+	if writeToken == adminApiPlaceholderWriteToken {
+		return errors.New("admin api write token is still the placeholder of the sample config")
+	}
+	if writeToken == readToken {
+		return errors.New("admin api write token must be different from the auth token")
+	}
+	return nil
+}
+
+func parseHash(value string) ([32]byte, error) {
+	var hash [32]byte
+	decoded, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil || len(decoded) != len(hash) {
+		return hash, errors.New("invalid batch identifier hash")
+	}
+	copy(hash[:], decoded)
+	return hash, nil
+}
+
+func writeAdminJson(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeAdminError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+func newAdminApiTestAggregator(token string) *Aggregator {
+	aggregatorConfig := &config.AggregatorConfig{}
+	aggregatorConfig.Aggregator.AdminApiAuthToken = token
+	return &Aggregator{
+		AggregatorConfig: aggregatorConfig,
+		taskInfos:        newTaskInfoTracker(),
+	}
+}
+
+func TestAdminApiRejectsMissingToken(t *testing.T) {
+	agg := newAdminApiTestAggregator("secret")
+	handler := agg.adminApiAuth(agg.handleAdminListTasks)
+
+	for _, header := range []string{"", "Bearer wrong", "secret"} {
+		request := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		if header != "" {
+			request.Header.Set("Authorization", header)
+		}
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status %d for authorization %q, got %d", http.StatusUnauthorized, header, recorder.Code)
+		}
+	}
+}
+
+func TestAdminApiRejectsPlaceholderTokens(t *testing.T) {
+	for _, token := range []string{"", adminApiPlaceholderToken} {
+		if err := validateAdminApiAuthToken(token); err == nil {
+			t.Errorf("Expected token %q to be rejected", token)
+		}
+	}
+	if err := validateAdminApiAuthToken("secret"); err != nil {
+		t.Errorf("Expected a set token to be accepted, got %v", err)
+	}
+}
+
+func TestAdminApiWriteTokenIsSeparate(t *testing.T) {
+	agg := newAdminApiTestAggregator("secret")
+	agg.AggregatorConfig.Aggregator.AdminApiWriteToken = "write-secret"
+	replayed := false
+	handler := agg.adminApiWriteAuth(func(w http.ResponseWriter, r *http.Request) { replayed = true })
+
+	// The read token does not grant the routes that change state
+	for header, expectedCode := range map[string]int{"Bearer secret": http.StatusUnauthorized, "Bearer write-secret": http.StatusOK} {
+		replayed = false
+		request := httptest.NewRequest(http.MethodPost, "/dead-letters/0x01/replay", nil)
+		request.Header.Set("Authorization", header)
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		if recorder.Code != expectedCode || replayed != (expectedCode == http.StatusOK) {
+			t.Errorf("Expected status %d for authorization %q, got %d", expectedCode, header, recorder.Code)
+		}
+	}
+
+	for _, writeToken := range []string{"secret", adminApiPlaceholderWriteToken} {
+		if err := validateAdminApiWriteToken("secret", writeToken); err == nil {
+			t.Errorf("Expected write token %q to be rejected", writeToken)
+		}
+	}
+	if err := validateAdminApiWriteToken("secret", "write-secret"); err != nil {
+		t.Errorf("Expected a distinct write token to be accepted, got %v", err)
+	}
+}
+
+func TestAdminApiListTasksByStatus(t *testing.T) {
+	agg := newAdminApiTestAggregator("secret")
+	handler := agg.adminApiAuth(agg.handleAdminListTasks)
+
+	now := time.Now()
+	agg.taskInfos.taskAdded([32]byte{1}, NewBatchTask{TaskCreatedBlock: 10}, 0, QuorumConfig{}, now.Add(-2*time.Second))
+	agg.taskInfos.taskAdded([32]byte{2}, NewBatchTask{TaskCreatedBlock: 11}, 1, QuorumConfig{}, now.Add(-time.Second))
+	agg.taskInfos.taskAdded([32]byte{3}, NewBatchTask{TaskCreatedBlock: 12}, 2, QuorumConfig{}, now)
+	agg.taskInfos.quorumReached([32]byte{2})
+	agg.taskInfos.responded([32]byte{2}, "0xabc", "1")
+
+	cases := map[string][]uint32{
+		"/tasks":                        {2, 1, 0},
+		"/tasks?status=pending":         {2, 0},
+		"/tasks?status=recent":          {1},
+		"/tasks?status=pending&limit=1": {2},
+	}
+	for url, expectedIndexes := range cases {
+		request := httptest.NewRequest(http.MethodGet, url, nil)
+		request.Header.Set("Authorization", "Bearer secret")
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d", http.StatusOK, url, recorder.Code)
+		}
+		var tasks []AdminTaskSummary
+		if err := json.Unmarshal(recorder.Body.Bytes(), &tasks); err != nil {
+			t.Fatalf("Could not decode response for %s: %v", url, err)
+		}
+		if len(tasks) != len(expectedIndexes) {
+			t.Fatalf("Expected %d tasks for %s, got %d", len(expectedIndexes), url, len(tasks))
+		}
+		for i, task := range tasks {
+			if task.TaskIndex != expectedIndexes[i] {
+				t.Errorf("Expected task %d at position %d for %s, got %d", expectedIndexes[i], i, url, task.TaskIndex)
+			}
+		}
+	}
+}
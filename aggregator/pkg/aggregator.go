@@ -2,35 +2,36 @@ package pkg
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/yetanotherco/aligned_layer/metrics"
 
 	sdkclients "github.com/Layr-Labs/eigensdk-go/chainio/clients"
+	ecdsa2 "github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/Layr-Labs/eigensdk-go/services/avsregistry"
 	blsagg "github.com/Layr-Labs/eigensdk-go/services/bls_aggregation"
 	oppubkeysserv "github.com/Layr-Labs/eigensdk-go/services/operatorsinfo"
 	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	retry "github.com/yetanotherco/aligned_layer/core"
 	"github.com/yetanotherco/aligned_layer/core/chainio"
 	"github.com/yetanotherco/aligned_layer/core/config"
 	"github.com/yetanotherco/aligned_layer/core/types"
 	"github.com/yetanotherco/aligned_layer/core/utils"
 )
 
-// FIXME(marian): Read this from Aligned contract directly
-const QUORUM_NUMBER = byte(0)
-const QUORUM_THRESHOLD = byte(67)
-
 // Aggregator stores TaskResponse for a task here
 type TaskResponses = []types.SignedTaskResponse
 
@@ -42,23 +43,25 @@ type BatchData struct {
 
 type Aggregator struct {
 	AggregatorConfig      *config.AggregatorConfig
-	NewBatchChan          chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3
+	NewBatchChanV2        chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2
+	NewBatchChanV3        chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3
 	avsReader             *chainio.AvsReader
 	avsSubscriber         *chainio.AvsSubscriber
 	avsWriter             *chainio.AvsWriter
-	taskSubscriber        chan error
+	taskSubscriberV2      chan error
+	taskSubscriberV3      chan error
 	blsAggregationService blsagg.BlsAggregationService
+	avsRegistryService    avsregistry.AvsRegistryService
 
 	// BLS Signature Service returns an Index
 	// Since our ID is not an idx, we build this cache
-	// Note: In case of a reboot, this doesn't need to be loaded,
-	// and can start from zero
+	// Note: In case of a reboot, this is rebuilt from the taskStore,
+	// indexes can start from zero again
 	batchesIdentifierHashByIdx map[uint32][32]byte
 
 	// This is the counterpart,
 	// to use when we have the batch but not the index
-	// Note: In case of a reboot, this doesn't need to be loaded,
-	// and can start from zero
+	// Note: In case of a reboot, this is rebuilt from the taskStore
 	batchesIdxByIdentifierHash map[[32]byte]uint32
 
 	// Stores the taskCreatedBlock for each batch by batch index
@@ -75,17 +78,76 @@ type Aggregator struct {
 	// Note: In case of a reboot it can start from 0 again
 	nextBatchIndex uint32
 
+	// Creation block of the newest task, tasks are evicted once they are old enough relative to it
+	latestTaskCreatedBlock uint64
+
 	// Mutex to protect:
 	// - batchesIdentifierHashByIdx
 	// - batchesIdxByIdentifierHash
 	// - batchCreatedBlockByIdx
 	// - batchDataByIdentifierHash
 	// - nextBatchIndex
+	// - latestTaskCreatedBlock
 	// - batchStartTimeByIdx
 	taskMutex *sync.Mutex
 
-	// Mutex to protect ethereum wallet
-	walletMutex *sync.Mutex
+	// Slots of the aggregated responses being sent concurrently, each transaction has its own nonce
+	responseSlots *responseSlots
+
+	// Durable copy of the tracked tasks, used to recover pending tasks after a restart
+	taskStore TaskStore
+
+	// Quorums and thresholds for new tasks, periodically reloaded from chain
+	quorumConfig      QuorumConfig
+	quorumConfigMutex *sync.RWMutex
+
+	// Lifecycle data of each task, exposed through the admin API
+	taskInfos *taskInfoTracker
+
+	// Aggregated responses that failed to be sent, re-attempted until they are sent or the batch is responded
+	deadLetterStore       DeadLetterStore
+	deadLettersReplaying  map[[32]byte]struct{}
+	deadLetterReplayMutex *sync.Mutex
+
+	// Leader election between aggregator instances, nil when HA is disabled.
+	// Only the leader sends responses onchain, standbys keep aggregating and hold their responses
+	// so they can send the ones still missing if they are elected
+	leaderElector         *leaderElector
+	standbyResponses      map[[32]byte]AggregatedResponse
+	standbyResponsesMutex *sync.Mutex
+
+	// Registered operators at the blocks of recent tasks, used to validate signatures before aggregating them
+	operatorStates *operatorStateCache
+
+	// Rate limits and timeouts of the operators rpc server
+	rpcLimits *rpcLimits
+
+	// Performance and liveness of each operator, exposed through metrics and the admin API
+	operatorLedger *operatorLedger
+
+	// Operator responses received before their task, processed once AddNewTask adds it
+	pendingResponses *pendingResponseBuffer
+
+	// Sent responses waiting for confirmations, sent again if their transaction is reorged out
+	confirmations *confirmationTracker
+
+	// EIP-1559 fee estimation of the respond to task transactions, nil to send legacy transactions
+	dynamicFeeParams *utils.DynamicFeeParams
+
+	// Responses held until the gas price drops, nil if deferral is disabled
+	deferrals *deferralQueue
+
+	// Responses held until the gas spend policy allows them, nil unless the policy action is defer
+	gasPolicyDeferrals *gasPolicyDeferrals
+
+	// Records the cost of every respond to task attempt, nil if no cost ledger path is configured
+	costLedger *CostLedger
+
+	// Nil if attestation_certificates_dir is not set
+	certificates *CertificateStore
+
+	// What is done with the tasks flagged as infeasible when they are created
+	infeasibleTaskPolicy string
 
 	logger logging.Logger
 
@@ -98,7 +160,8 @@ type Aggregator struct {
 }
 
 func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error) {
-	newBatchChan := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3)
+	newBatchChanV2 := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2)
+	newBatchChanV3 := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3)
 
 	logger := aggregatorConfig.BaseConfig.Logger
 
@@ -107,7 +170,10 @@ func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error
 	aggregatorMetrics := metrics.NewMetrics(aggregatorConfig.Aggregator.MetricsIpPortAddress, reg, logger)
 
 	// Telemetry
-	aggregatorTelemetry := NewTelemetry(aggregatorConfig.Aggregator.TelemetryIpPortAddress, logger)
+	aggregatorTelemetry, err := NewTelemetry(&aggregatorConfig, aggregatorMetrics, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	avsReader, err := chainio.NewAvsReaderFromConfig(aggregatorConfig.BaseConfig)
 	if err != nil {
@@ -124,12 +190,162 @@ func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error
 		return nil, err
 	}
 
+	dynamicFeeParams, err := newDynamicFeeParams(&aggregatorConfig)
+	if err != nil {
+		logger.Error("Invalid gas fee config", "err", err)
+		return nil, err
+	}
+
+	var costLedger *CostLedger
+	if aggregatorConfig.Aggregator.CostLedgerPath != "" {
+		costLedger, err = NewCostLedger(aggregatorConfig.Aggregator.CostLedgerPath)
+		if err != nil {
+			logger.Error("Cannot open cost ledger", "err", err)
+			return nil, err
+		}
+		logger.Info("Recording respond to task costs", "path", aggregatorConfig.Aggregator.CostLedgerPath)
+	}
+
+	var certificates *CertificateStore
+	if aggregatorConfig.Aggregator.AttestationCertificatesDir != "" {
+		certificates, err = NewCertificateStore(aggregatorConfig.Aggregator.AttestationCertificatesDir)
+		if err != nil {
+			logger.Error("Cannot open attestation certificates directory", "err", err)
+			return nil, err
+		}
+		logger.Info("Writing attestation certificates", "dir", aggregatorConfig.Aggregator.AttestationCertificatesDir)
+	}
+
+	// Refuse to start rather than serving the admin API without a real token
+	if aggregatorConfig.Aggregator.AdminApiIpPortAddress != "" {
+		if err := validateAdminApiAuthToken(aggregatorConfig.Aggregator.AdminApiAuthToken); err != nil {
+			logger.Error("Invalid admin API config", "err", err)
+			return nil, err
+		}
+		if aggregatorConfig.Aggregator.AdminApiWriteToken != "" {
+			if err := validateAdminApiWriteToken(aggregatorConfig.Aggregator.AdminApiAuthToken, aggregatorConfig.Aggregator.AdminApiWriteToken); err != nil {
+				logger.Error("Invalid admin API config", "err", err)
+				return nil, err
+			}
+		}
+	}
+
+	if err := validateQuorumThreshold(&aggregatorConfig); err != nil {
+		logger.Error("Invalid quorum threshold", "err", err)
+		return nil, err
+	}
+
+	infeasiblePolicy, err := infeasibleTaskPolicy(&aggregatorConfig)
+	if err != nil {
+		logger.Error("Invalid infeasible task policy", "err", err)
+		return nil, err
+	}
+
+	// Responses are only deferred if enabled, for every task or only for the infeasible ones
+	var deferrals *deferralQueue
+	if aggregatorConfig.Aggregator.ResponseDeferralEnabled || infeasiblePolicy == InfeasibleTaskPolicyDefer {
+		deferrals, err = newDeferralQueue(aggregatorConfig.Aggregator.ResponseDeferralMaxWait, aggregatorConfig.Aggregator.ResponseDeferralStorePath)
+		if err != nil {
+			logger.Error("Failed to load deferred responses", "err", err)
+			return nil, err
+		}
+		if deferrals.len() > 0 {
+			logger.Info("Loaded deferred responses", "count", deferrals.len())
+			aggregatorMetrics.SetDeferredResponses(deferrals.len())
+		}
+	}
+
+	confirmations, err := newConfirmationTracker(aggregatorConfig.Aggregator.RespondToTaskConfirmations, aggregatorConfig.Aggregator.UnconfirmedResponseStorePath)
+	if err != nil {
+		logger.Error("Failed to load unconfirmed responses", "err", err)
+		return nil, err
+	}
+	if confirmations.len() > 0 {
+		logger.Info("Loaded unconfirmed responses", "count", confirmations.len())
+		aggregatorMetrics.SetUnconfirmedResponses(confirmations.len())
+	}
+
+	gasPolicy, err := newGasPolicy(&aggregatorConfig)
+	if err != nil {
+		logger.Error("Invalid gas policy config", "err", err)
+		return nil, err
+	}
+	avsWriter.GasPolicy = gasPolicy
+	var policyDeferrals *gasPolicyDeferrals
+	if gasPolicy != nil && aggregatorConfig.Aggregator.GasPolicyAction == chainio.GasPolicyActionDefer {
+		policyDeferrals, err = newGasPolicyDeferrals(aggregatorConfig.Aggregator.GasPolicyMaxWait, aggregatorConfig.Aggregator.GasPolicyDeferralStorePath)
+		if err != nil {
+			logger.Error("Failed to load the responses deferred by the gas spend policy", "err", err)
+			return nil, err
+		}
+		if policyDeferrals.len() > 0 {
+			logger.Info("Loaded responses deferred by the gas spend policy", "count", policyDeferrals.len())
+		}
+	}
+
+	// Responses are sent through the aggregator RPC only, unless extra endpoints are configured
+	if len(aggregatorConfig.Aggregator.BroadcastRpcUrls) > 0 || len(aggregatorConfig.Aggregator.PrivateRelayUrls) > 0 {
+		var relaySigningKey *ecdsa.PrivateKey
+		if aggregatorConfig.Aggregator.RelaySigningKeyStorePath != "" {
+			relaySigningKey, err = ecdsa2.ReadKey(aggregatorConfig.Aggregator.RelaySigningKeyStorePath, aggregatorConfig.Aggregator.RelaySigningKeyStorePassword)
+			if err != nil {
+				logger.Error("Failed to read the private relay signing key", "err", err)
+				return nil, err
+			}
+		}
+		broadcaster, err := avsWriter.NewTxBroadcaster(chainio.TxBroadcasterConfig{
+			RpcUrls:                aggregatorConfig.Aggregator.BroadcastRpcUrls,
+			PrivateRelayUrls:       aggregatorConfig.Aggregator.PrivateRelayUrls,
+			PrivateRelayMethod:     aggregatorConfig.Aggregator.PrivateRelayMethod,
+			PrivateRelayOnly:       aggregatorConfig.Aggregator.PrivateRelayOnly,
+			BundleTargetPeriod:     aggregatorConfig.Aggregator.TimeToWaitBeforeBump,
+			PrivateRelaySigningKey: relaySigningKey,
+			PrivateRelayAuthHeader: aggregatorConfig.Aggregator.PrivateRelayAuthHeader,
+		})
+		if err != nil {
+			logger.Error("Cannot create transaction broadcaster", "err", err)
+			return nil, err
+		}
+		avsWriter.Broadcaster = broadcaster
+	}
+
+	rpcLimits, err := newRpcLimits(&aggregatorConfig, aggregatorMetrics)
+	if err != nil {
+		logger.Error("Invalid rpc limits", "err", err)
+		return nil, err
+	}
+
 	batchesIdentifierHashByIdx := make(map[uint32][32]byte)
 	batchesIdxByIdentifierHash := make(map[[32]byte]uint32)
 	batchDataByIdentifierHash := make(map[[32]byte]BatchData)
 	batchCreatedBlockByIdx := make(map[uint32]uint64)
 	batchStartTimeByIdx := make(map[uint32]time.Time)
 
+	var taskStore TaskStore
+	if aggregatorConfig.Aggregator.TaskStorePath != "" {
+		taskStore, err = NewFileTaskStore(aggregatorConfig.Aggregator.TaskStorePath)
+		if err != nil {
+			logger.Error("Cannot create task store", "err", err)
+			return nil, err
+		}
+		logger.Info("Using task store", "path", aggregatorConfig.Aggregator.TaskStorePath)
+	} else {
+		logger.Warn("No task store path provided, pending tasks will be lost on restart")
+		taskStore = NewInMemoryTaskStore()
+	}
+
+	var deadLetterStore DeadLetterStore
+	if aggregatorConfig.Aggregator.DeadLetterStorePath != "" {
+		deadLetterStore, err = NewFileDeadLetterStore(aggregatorConfig.Aggregator.DeadLetterStorePath)
+		if err != nil {
+			logger.Error("Cannot create dead letter store", "err", err)
+			return nil, err
+		}
+	} else {
+		logger.Warn("No dead letter store path provided, failed responses will be lost on restart")
+		deadLetterStore = NewInMemoryDeadLetterStore()
+	}
+
 	chainioConfig := sdkclients.BuildAllConfig{
 		EthHttpUrl:                 aggregatorConfig.BaseConfig.EthRpcUrl,
 		EthWsUrl:                   aggregatorConfig.BaseConfig.EthWsUrl,
@@ -171,7 +387,8 @@ func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error
 		avsReader:        avsReader,
 		avsSubscriber:    avsSubscriber,
 		avsWriter:        avsWriter,
-		NewBatchChan:     newBatchChan,
+		NewBatchChanV2:   newBatchChanV2,
+		NewBatchChanV3:   newBatchChanV3,
 
 		batchesIdentifierHashByIdx: batchesIdentifierHashByIdx,
 		batchesIdxByIdentifierHash: batchesIdxByIdentifierHash,
@@ -180,15 +397,69 @@ func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error
 		batchStartTimeByIdx:        batchStartTimeByIdx,
 		nextBatchIndex:             nextBatchIndex,
 		taskMutex:                  &sync.Mutex{},
-		walletMutex:                &sync.Mutex{},
+		responseSlots:              newResponseSlots(maxInFlightResponses(&aggregatorConfig)),
+		taskStore:                  taskStore,
+		quorumConfigMutex:          &sync.RWMutex{},
+		taskInfos:                  newTaskInfoTracker(),
+		deadLetterStore:            deadLetterStore,
+		deadLettersReplaying:       make(map[[32]byte]struct{}),
+		deadLetterReplayMutex:      &sync.Mutex{},
+		standbyResponses:           make(map[[32]byte]AggregatedResponse),
+		standbyResponsesMutex:      &sync.Mutex{},
+		operatorStates:             newOperatorStateCache(),
+		rpcLimits:                  rpcLimits,
+		operatorLedger:             newOperatorLedger(aggregatorMetrics),
+		pendingResponses:           newPendingResponseBuffer(aggregatorConfig.Aggregator.PendingResponseTtlBlocks, aggregatorConfig.Aggregator.MaxPendingResponses, aggregatorConfig.Aggregator.MaxPendingResponsesPerOperator),
+		confirmations:              confirmations,
+		dynamicFeeParams:           dynamicFeeParams,
+		deferrals:                  deferrals,
+		gasPolicyDeferrals:         policyDeferrals,
+		costLedger:                 costLedger,
+		certificates:               certificates,
+		infeasibleTaskPolicy:       infeasiblePolicy,
 
 		blsAggregationService: blsAggregationService,
+		avsRegistryService:    avsRegistryService,
 		logger:                logger,
 		metricsReg:            reg,
 		metrics:               aggregatorMetrics,
 		telemetry:             aggregatorTelemetry,
 	}
 
+	quorumConfig, err := aggregator.fetchQuorumConfig()
+	if err != nil {
+		logger.Error("Cannot load quorum config", "err", err)
+		return nil, err
+	}
+	aggregator.quorumConfig = quorumConfig
+	aggregator.setWalletMinBalance(&aggregatorConfig)
+	// Responses sent from a wallet the service manager doesn't accept would revert, so it's refused at startup
+	walletsCtx, cancelWallets := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelWallets()
+	if err := aggregator.checkWallets(walletsCtx); err != nil {
+		logger.Error("Invalid aggregator wallets", "err", err)
+		return nil, err
+	}
+	logger.Info("Loaded quorum config",
+		"quorumNums", quorumConfig.QuorumNums,
+		"quorumThresholdPercentages", quorumConfig.QuorumThresholdPercentages)
+
+	if aggregatorConfig.Aggregator.HaEnabled {
+		leaderLock, err := newLeaderLock(aggregatorConfig)
+		if err != nil {
+			logger.Error("Cannot create leader lock", "err", err)
+			return nil, err
+		}
+		instanceId := aggregatorConfig.Aggregator.HaInstanceId
+		if instanceId == "" {
+			instanceId = defaultInstanceId()
+		}
+		aggregator.leaderElector = newLeaderElector(leaderLock, instanceId, aggregatorConfig.Aggregator.HaLeaseDuration, logger)
+		aggregator.leaderElector.onElected = aggregator.onElectedLeader
+		aggregator.leaderElector.onDemoted = aggregator.onDemotedLeader
+		logger.Info("HA enabled, this instance will only send responses while it is the leader", "instanceId", instanceId)
+	}
+
 	return &aggregator, nil
 }
 
@@ -202,6 +473,30 @@ func (agg *Aggregator) Start(ctx context.Context) error {
 		}
 	}()
 
+	if agg.leaderElector != nil {
+		go agg.leaderElector.Run(ctx)
+	} else {
+		agg.metrics.SetIsLeader(true)
+	}
+
+	go agg.FlushTaskStore(ctx)
+	go agg.RefreshQuorumConfig(ctx)
+	go agg.ProcessDeadLetters(ctx)
+	go agg.WatchConfirmations(ctx)
+	go agg.MonitorWalletBalances(ctx)
+	go agg.ProcessDeferredResponses(ctx)
+	go agg.ProcessGasPolicyDeferredResponses(ctx)
+	go agg.TrackLatestBlock(ctx)
+
+	if agg.AggregatorConfig.Aggregator.AdminApiIpPortAddress != "" {
+		go func() {
+			err := agg.ServeAdminApi()
+			if err != nil {
+				agg.logger.Fatal("Error serving admin API", "err", err)
+			}
+		}()
+	}
+
 	var metricsErrChan <-chan error
 	if agg.AggregatorConfig.Aggregator.EnableMetrics {
 		metricsErrChan = agg.metrics.Start(ctx, agg.metricsReg)
@@ -212,6 +507,11 @@ func (agg *Aggregator) Start(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := agg.telemetry.Shutdown(shutdownCtx); err != nil {
+				agg.logger.Warn("Failed to export pending traces", "err", err)
+			}
+			cancel()
 			return nil
 		case err := <-metricsErrChan:
 			agg.logger.Fatal("Metrics server failed", "err", err)
@@ -226,6 +526,16 @@ func (agg *Aggregator) Start(ctx context.Context) error {
 
 const MaxSentTxRetries = 5
 
+// DefaultMaxInFlightResponses is used when `max_in_flight_responses` is not set
+const DefaultMaxInFlightResponses = 4
+
+func maxInFlightResponses(aggregatorConfig *config.AggregatorConfig) int {
+	if aggregatorConfig.Aggregator.MaxInFlightResponses <= 0 {
+		return DefaultMaxInFlightResponses
+	}
+	return aggregatorConfig.Aggregator.MaxInFlightResponses
+}
+
 func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsAggregationServiceResponse) {
 	defer func() {
 		err := recover() //stops panics
@@ -236,7 +546,13 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 
 	agg.taskMutex.Lock()
 	agg.AggregatorConfig.BaseConfig.Logger.Info("- Locked Resources: Fetching task data")
-	batchIdentifierHash := agg.batchesIdentifierHashByIdx[blsAggServiceResp.TaskIndex]
+	batchIdentifierHash, ok := agg.batchesIdentifierHashByIdx[blsAggServiceResp.TaskIndex]
+	if !ok {
+		agg.taskMutex.Unlock()
+		agg.logger.Warn("Task was evicted before its aggregation finished, ignoring BLS aggregation response",
+			"taskIndex", blsAggServiceResp.TaskIndex)
+		return
+	}
 	batchData := agg.batchDataByIdentifierHash[batchIdentifierHash]
 	taskCreatedBlock := agg.batchCreatedBlockByIdx[blsAggServiceResp.TaskIndex]
 	taskCreatedAt := agg.batchStartTimeByIdx[blsAggServiceResp.TaskIndex]
@@ -247,6 +563,10 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 	defer agg.telemetry.FinishTrace(batchData.BatchMerkleRoot)
 
 	if blsAggServiceResp.Err != nil {
+		if taskInfo, ok := agg.taskInfos.get(batchIdentifierHash); ok {
+			agg.recordMissedOperators(uint32(taskCreatedBlock), taskInfo.QuorumConfig.QuorumNums, taskInfo.Signers)
+		}
+		agg.taskInfos.failed(batchIdentifierHash, blsAggServiceResp.Err)
 		agg.telemetry.LogTaskError(batchData.BatchMerkleRoot, blsAggServiceResp.Err)
 		agg.logger.Error("BlsAggregationServiceResponse contains an error", "err", blsAggServiceResp.Err, "batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]))
 		return
@@ -254,6 +574,7 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 	nonSignerPubkeys := []servicemanager.BN254G1Point{}
 	for _, nonSignerPubkey := range blsAggServiceResp.NonSignersPubkeysG1 {
 		nonSignerPubkeys = append(nonSignerPubkeys, utils.ConvertToBN254G1Point(nonSignerPubkey))
+		agg.operatorLedger.missed(eigentypes.OperatorIdFromG1Pubkey(nonSignerPubkey))
 	}
 	quorumApks := []servicemanager.BN254G1Point{}
 	for _, quorumApk := range blsAggServiceResp.QuorumApksG1 {
@@ -272,6 +593,7 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 	}
 
 	agg.telemetry.LogQuorumReached(batchData.BatchMerkleRoot)
+	agg.taskInfos.quorumReached(batchIdentifierHash)
 
 	// Only observe quorum reached if successful
 	agg.metrics.ObserveTaskQuorumReached(time.Since(taskCreatedAt))
@@ -279,6 +601,21 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 	agg.logger.Info("Threshold reached", "taskIndex", blsAggServiceResp.TaskIndex,
 		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
 
+	response := AggregatedResponse{
+		BatchIdentifierHash:         batchIdentifierHash,
+		BatchMerkleRoot:             batchData.BatchMerkleRoot,
+		SenderAddress:               batchData.SenderAddress,
+		TaskCreatedBlock:            uint32(taskCreatedBlock),
+		NonSignerStakesAndSignature: nonSignerStakesAndSignature,
+	}
+	// Written by every aggregator, so downstream systems don't depend on the leader
+	go agg.writeAttestationCertificate(response)
+
+	if !agg.isLeader() {
+		agg.holdStandbyResponse(response)
+		return
+	}
+
 	agg.logger.Info("Maybe waiting one block to send aggregated response onchain",
 		"taskIndex", blsAggServiceResp.TaskIndex,
 		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
@@ -289,9 +626,20 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 		agg.logger.Error("Error waiting for one block, sending anyway", "err", err)
 	}
 
-	agg.logger.Info("Sending aggregated response onchain", "taskIndex", blsAggServiceResp.TaskIndex,
-		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]), "merkleRoot", "0x"+hex.EncodeToString(batchData.BatchMerkleRoot[:]))
-	receipt, err := agg.sendAggregatedResponse(batchIdentifierHash, batchData.BatchMerkleRoot, batchData.SenderAddress, nonSignerStakesAndSignature)
+	if agg.deferResponse(response) {
+		return
+	}
+	agg.respondToTask(response)
+}
+
+// respondToTask sends the aggregated response onchain. If it fails, the response is stored as dead letter,
+// unless this instance lost the leadership, in which case it is held until it is elected again,
+// or the gas spend policy deferred it, in which case it is held until the policy allows it
+func (agg *Aggregator) respondToTask(response AggregatedResponse) {
+	batchIdentifierHash := response.BatchIdentifierHash
+	agg.logger.Info("Sending aggregated response onchain",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]), "merkleRoot", "0x"+hex.EncodeToString(response.BatchMerkleRoot[:]))
+	receipt, err := agg.sendAggregatedResponse(batchIdentifierHash, response.BatchMerkleRoot, response.SenderAddress, response.NonSignerStakesAndSignature)
 	if err == nil {
 		// In some cases, we may fail to retrieve the receipt for the transaction.
 		txHash := "Unknown"
@@ -300,29 +648,54 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 			txHash = receipt.TxHash.String()
 			effectiveGasPrice = receipt.EffectiveGasPrice.String()
 		}
-		agg.telemetry.TaskSentToEthereum(batchData.BatchMerkleRoot, txHash, effectiveGasPrice)
+		agg.telemetry.TaskSentToEthereum(response.BatchMerkleRoot, txHash, effectiveGasPrice)
+		agg.taskInfos.responded(batchIdentifierHash, txHash, effectiveGasPrice)
+		agg.waitForConfirmations(response, receipt)
 		agg.logger.Info("Aggregator successfully responded to task",
-			"taskIndex", blsAggServiceResp.TaskIndex,
 			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
 
 		return
 	}
 
-	agg.logger.Error("Aggregator failed to respond to task, this batch will be lost",
+	if errors.Is(err, errNotLeader) {
+		agg.holdStandbyResponse(response)
+		return
+	}
+	if errors.Is(err, chainio.ErrGasPolicyDeferred) && agg.gasPolicyDeferrals != nil {
+		agg.holdGasPolicyDeferredResponse(response, err)
+		return
+	}
+
+	agg.logger.Error("Aggregator failed to respond to task, storing it as dead letter",
 		"err", err,
-		"taskIndex", blsAggServiceResp.TaskIndex,
-		"merkleRoot", "0x"+hex.EncodeToString(batchData.BatchMerkleRoot[:]),
-		"senderAddress", "0x"+hex.EncodeToString(batchData.SenderAddress[:]),
+		"merkleRoot", "0x"+hex.EncodeToString(response.BatchMerkleRoot[:]),
+		"senderAddress", "0x"+hex.EncodeToString(response.SenderAddress[:]),
 		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
-	agg.telemetry.LogTaskError(batchData.BatchMerkleRoot, err)
+	agg.telemetry.LogTaskError(response.BatchMerkleRoot, err)
+	agg.taskInfos.failed(batchIdentifierHash, err)
+	agg.addDeadLetter(DeadLetter{
+		BatchIdentifierHash:         batchIdentifierHash,
+		BatchMerkleRoot:             response.BatchMerkleRoot,
+		SenderAddress:               response.SenderAddress,
+		TaskCreatedBlock:            response.TaskCreatedBlock,
+		NonSignerStakesAndSignature: response.NonSignerStakesAndSignature,
+	}, err)
 }
 
 // / Sends response to contract and waits for transaction receipt
 // / Returns error if it fails to send tx or receipt is not found
 func (agg *Aggregator) sendAggregatedResponse(batchIdentifierHash [32]byte, batchMerkleRoot [32]byte, senderAddress [20]byte, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature) (*gethtypes.Receipt, error) {
 
-	agg.walletMutex.Lock()
-	agg.logger.Infof("- Locked Wallet Resources: Sending aggregated response for batch",
+	lowPriority := agg.infeasibleTaskPolicy == InfeasibleTaskPolicyDeprioritize && agg.isInfeasible(batchIdentifierHash)
+	agg.metrics.SetInFlightResponses(agg.responseSlots.acquire(lowPriority))
+	defer func() {
+		agg.metrics.SetInFlightResponses(agg.responseSlots.release())
+	}()
+	// Leadership may have been lost while waiting for a slot
+	if !agg.isLeader() {
+		return nil, errNotLeader
+	}
+	agg.logger.Infof("Sending aggregated response for batch",
 		"merkleRoot", hex.EncodeToString(batchMerkleRoot[:]),
 		"senderAddress", hex.EncodeToString(senderAddress[:]),
 		"batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]))
@@ -330,6 +703,19 @@ func (agg *Aggregator) sendAggregatedResponse(batchIdentifierHash [32]byte, batc
 	// This function is a callback that is called when the gas price is bumped on the avsWriter.SendAggregatedResponse
 	onSetGasPrice := func(gasPrice *big.Int) {
 		agg.telemetry.TaskSetGasPrice(batchMerkleRoot, gasPrice.String())
+		agg.taskInfos.gasPriceSet(batchIdentifierHash, gasPrice.String())
+	}
+
+	costLedgerRecorder := agg.newCostLedgerRecorder(batchIdentifierHash, batchMerkleRoot, senderAddress)
+
+	// This function is a callback that is called each time a RespondToTask transaction is sent
+	onSentTx := func(tx *gethtypes.Transaction) {
+		agg.taskInfos.txSent(batchIdentifierHash, tx.Hash().String())
+		if costLedgerRecorder != nil {
+			if err := costLedgerRecorder.txSent(tx); err != nil {
+				agg.logger.Warn("Failed to record sent transaction in the cost ledger", "err", err)
+			}
+		}
 	}
 
 	startTime := time.Now()
@@ -342,89 +728,220 @@ func (agg *Aggregator) sendAggregatedResponse(batchIdentifierHash [32]byte, batc
 		agg.AggregatorConfig.Aggregator.GasBumpIncrementalPercentage,
 		agg.AggregatorConfig.Aggregator.GasBumpPercentageLimit,
 		agg.AggregatorConfig.Aggregator.TimeToWaitBeforeBump,
+		agg.dynamicFeeParams,
 		agg.metrics,
 		onSetGasPrice,
+		onSentTx,
 	)
+	if costLedgerRecorder != nil {
+		if ledgerErr := costLedgerRecorder.outcome(receipt, err); ledgerErr != nil {
+			agg.logger.Warn("Failed to record respond to task outcome in the cost ledger", "err", ledgerErr)
+		}
+	}
 	if err != nil {
-		agg.walletMutex.Unlock()
-		agg.logger.Infof("- Unlocked Wallet Resources: Error sending aggregated response for batch %s. Error: %s", hex.EncodeToString(batchIdentifierHash[:]), err)
+		agg.logger.Infof("Error sending aggregated response for batch %s. Error: %s", hex.EncodeToString(batchIdentifierHash[:]), err)
 		return nil, err
 	}
 
 	// We only send the latency metric if the response is successul
 	agg.metrics.ObserveLatencyForRespondToTask(time.Since(startTime))
 
-	agg.walletMutex.Unlock()
-	agg.logger.Infof("- Unlocked Wallet Resources: Sending aggregated response for batch %s", hex.EncodeToString(batchIdentifierHash[:]))
+	agg.logger.Infof("Sent aggregated response for batch %s", hex.EncodeToString(batchIdentifierHash[:]))
 
-	agg.metrics.IncAggregatedResponses()
+	version := NewBatchVersionV3
+	if task, ok := agg.taskInfos.get(batchIdentifierHash); ok {
+		version = task.Version
+	}
+	agg.metrics.IncAggregatedResponses(version)
 
 	return receipt, nil
 }
 
-func (agg *Aggregator) AddNewTask(batchMerkleRoot [32]byte, senderAddress [20]byte, taskCreatedBlock uint32) {
-	agg.telemetry.InitNewTrace(batchMerkleRoot)
-	batchIdentifier := append(batchMerkleRoot[:], senderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+func (agg *Aggregator) AddNewTask(task NewBatchTask) {
+	batchMerkleRoot, senderAddress, taskCreatedBlock := task.BatchMerkleRoot, task.SenderAddress, task.TaskCreatedBlock
+	batchIdentifierHash := task.BatchIdentifierHash()
+	agg.telemetry.InitNewTrace(batchMerkleRoot, batchIdentifierHash)
 
 	agg.AggregatorConfig.BaseConfig.Logger.Info("Adding new task",
 		"Batch merkle root", "0x"+hex.EncodeToString(batchMerkleRoot[:]),
 		"Sender Address", "0x"+hex.EncodeToString(senderAddress[:]),
-		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+		"version", task.Version)
+
+	agg.pendingResponses.observeBlock(uint64(taskCreatedBlock))
 
 	agg.taskMutex.Lock()
 	agg.AggregatorConfig.BaseConfig.Logger.Info("- Locked Resources: Adding new task")
 
 	// --- UPDATE BATCH - INDEX CACHES ---
-	batchIndex := agg.nextBatchIndex
-	if _, ok := agg.batchesIdxByIdentifierHash[batchIdentifierHash]; ok {
-		agg.logger.Warn("Batch already exists", "batchIndex", batchIndex, "batchIdentifierHash", batchIdentifierHash)
+	quorumConfig := agg.getQuorumConfig()
+	batchIndex, added := agg.trackTask(batchIdentifierHash, task, quorumConfig)
+	if !added {
 		agg.taskMutex.Unlock()
 		agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Adding new task")
 		return
 	}
 
+	err := agg.blsAggregationService.InitializeNewTaskWithWindow(batchIndex, taskCreatedBlock, quorumConfig.QuorumNums, quorumConfig.QuorumThresholdPercentages, agg.AggregatorConfig.Aggregator.BlsServiceTaskTimeout, 15*time.Second)
+	if err != nil {
+		agg.logger.Fatalf("BLS aggregation service error when initializing new task: %s", err)
+	}
+
+	agg.metrics.IncAggregatorReceivedTasks(task.Version)
+	agg.taskMutex.Unlock()
+	agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Adding new task")
+	agg.logger.Info("New task added", "batchIndex", batchIndex, "batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+
+	go agg.checkTaskFeasibility(batchIdentifierHash, senderAddress)
+
+	agg.replayPendingResponses(batchIdentifierHash, batchIndex)
+}
+
+// trackTask adds the task to the maps, the task infos and the task store, and returns its index.
+// Returns false if the task is already tracked. The taskMutex must be held
+func (agg *Aggregator) trackTask(batchIdentifierHash [32]byte, task NewBatchTask, quorumConfig QuorumConfig) (uint32, bool) {
+	taskCreatedBlock := task.TaskCreatedBlock
+	batchIndex := agg.nextBatchIndex
+	if _, ok := agg.batchesIdxByIdentifierHash[batchIdentifierHash]; ok {
+		agg.logger.Warn("Batch already exists", "batchIndex", batchIndex, "batchIdentifierHash", batchIdentifierHash)
+		return 0, false
+	}
+
 	// This shouldn't happen, since both maps are updated together
 	if _, ok := agg.batchesIdentifierHashByIdx[batchIndex]; ok {
 		agg.logger.Warn("Batch already exists", "batchIndex", batchIndex, "batchIdentifierHash", batchIdentifierHash)
-		agg.taskMutex.Unlock()
-		agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Adding new task")
-		return
+		return 0, false
 	}
 
 	agg.batchesIdxByIdentifierHash[batchIdentifierHash] = batchIndex
 	agg.batchCreatedBlockByIdx[batchIndex] = uint64(taskCreatedBlock)
 	agg.batchesIdentifierHashByIdx[batchIndex] = batchIdentifierHash
 	agg.batchDataByIdentifierHash[batchIdentifierHash] = BatchData{
-		BatchMerkleRoot: batchMerkleRoot,
-		SenderAddress:   senderAddress,
+		BatchMerkleRoot: task.BatchMerkleRoot,
+		SenderAddress:   task.SenderAddress,
 	}
 	agg.batchStartTimeByIdx[batchIndex] = time.Now()
+	agg.taskInfos.taskAdded(batchIdentifierHash, task, batchIndex, quorumConfig, agg.batchStartTimeByIdx[batchIndex])
+	err := agg.taskStore.SaveTask(StoredTask{
+		BatchIdentifierHash:   batchIdentifierHash,
+		BatchMerkleRoot:       task.BatchMerkleRoot,
+		SenderAddress:         task.SenderAddress,
+		TaskCreatedBlock:      taskCreatedBlock,
+		CreatedAt:             agg.batchStartTimeByIdx[batchIndex],
+		Version:               task.Version,
+		BatchDataPointer:      task.BatchDataPointer,
+		RespondToTaskFeeLimit: task.RespondToTaskFeeLimit,
+	})
+	if err != nil {
+		agg.logger.Warn("Failed to persist task, it will not be recovered after a restart", "err", err,
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+	}
 	agg.logger.Info(
 		"Task Info added in aggregator:",
 		"Task", batchIndex,
 		"batchIdentifierHash", batchIdentifierHash,
 	)
 	agg.nextBatchIndex += 1
+	if uint64(taskCreatedBlock) > agg.latestTaskCreatedBlock {
+		agg.latestTaskCreatedBlock = uint64(taskCreatedBlock)
+	}
 
-	quorumNums := eigentypes.QuorumNums{eigentypes.QuorumNum(QUORUM_NUMBER)}
-	quorumThresholdPercentages := eigentypes.QuorumThresholdPercentages{eigentypes.QuorumThresholdPercentage(QUORUM_THRESHOLD)}
+	// Don't wait for the garbage collector to enforce the cap, as tasks can arrive faster than it runs.
+	// Some room is made so the tasks are not all scanned again on the next one
+	if len(agg.batchesIdentifierHashByIdx) > agg.maxTrackedTasks() {
+		agg.evictTasks(time.Now(), agg.maxTrackedTasks()*9/10)
+	}
+	agg.metrics.SetTrackedTasks(len(agg.batchesIdentifierHashByIdx))
+	return batchIndex, true
+}
 
-	err := agg.blsAggregationService.InitializeNewTaskWithWindow(batchIndex, taskCreatedBlock, quorumNums, quorumThresholdPercentages, agg.AggregatorConfig.Aggregator.BlsServiceTaskTimeout, 15*time.Second)
+// RecoverPendingTasks re-initializes the tasks that were pending when the aggregator stopped.
+// Tasks are taken from the task store, and from the NewBatchV3 logs of the last TaskRecoveryBlocks blocks
+// that have not been responded yet. Tasks already responded onchain are dropped from the store.
+// It must be called before subscribing to new tasks.
+func (agg *Aggregator) RecoverPendingTasks() {
+	storedTasks, err := agg.taskStore.LoadTasks()
 	if err != nil {
-		agg.logger.Fatalf("BLS aggregation service error when initializing new task: %s", err)
+		agg.logger.Error("Failed to load tasks from task store", "err", err)
 	}
 
-	agg.metrics.IncAggregatorReceivedTasks()
-	agg.taskMutex.Unlock()
-	agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Adding new task")
-	agg.logger.Info("New task added", "batchIndex", batchIndex, "batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+	// Tasks are added in creation order, so indexes keep the same order they had before the restart
+	sort.Slice(storedTasks, func(i, j int) bool {
+		return storedTasks[i].TaskCreatedBlock < storedTasks[j].TaskCreatedBlock
+	})
+
+	recoveredTasks := 0
+	for _, task := range storedTasks {
+		batchState, err := agg.avsWriter.BatchesStateRetryable(&bind.CallOpts{}, task.BatchIdentifierHash, retry.NetworkRetryParams())
+		if err != nil {
+			agg.logger.Warn("Failed to get batch state of stored task, recovering it anyway", "err", err,
+				"batchIdentifierHash", "0x"+hex.EncodeToString(task.BatchIdentifierHash[:]))
+		} else if batchState.Responded {
+			if err := agg.taskStore.DeleteTask(task.BatchIdentifierHash); err != nil {
+				agg.logger.Warn("Failed to remove responded task from task store", "err", err)
+			}
+			continue
+		}
+		agg.AddNewTask(task.newBatchTask())
+		recoveredTasks++
+	}
+	agg.logger.Info("Recovered tasks from task store", "tasks", recoveredTasks)
+
+	recoveryBlocks := agg.AggregatorConfig.Aggregator.TaskRecoveryBlocks
+	if recoveryBlocks == 0 {
+		return
+	}
+
+	latestBlock, err := agg.avsSubscriber.BlockNumberRetryable(context.Background(), retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Error("Failed to get latest block, skipping onchain task recovery", "err", err)
+		return
+	}
+	fromBlock := uint64(0)
+	if latestBlock > recoveryBlocks {
+		fromBlock = latestBlock - recoveryBlocks
+	}
+
+	notRespondedTasksV3, err := agg.avsReader.GetNotRespondedTasksFrom(fromBlock)
+	if err != nil {
+		agg.logger.Error("Failed to get not responded tasks, skipping onchain task recovery", "err", err)
+		return
+	}
+	// V2 batches may still be created while the contract is being upgraded
+	notRespondedTasksV2, err := agg.avsReader.GetNotRespondedTasksV2From(fromBlock)
+	if err != nil {
+		agg.logger.Error("Failed to get not responded V2 tasks, recovering only V3 tasks", "err", err)
+	}
+
+	notRespondedTasks := make([]NewBatchTask, 0, len(notRespondedTasksV2)+len(notRespondedTasksV3))
+	for i := range notRespondedTasksV2 {
+		notRespondedTasks = append(notRespondedTasks, newBatchTaskFromV2(&notRespondedTasksV2[i]))
+	}
+	for i := range notRespondedTasksV3 {
+		notRespondedTasks = append(notRespondedTasks, newBatchTaskFromV3(&notRespondedTasksV3[i]))
+	}
+	sort.SliceStable(notRespondedTasks, func(i, j int) bool {
+		return notRespondedTasks[i].TaskCreatedBlock < notRespondedTasks[j].TaskCreatedBlock
+	})
+
+	for _, task := range notRespondedTasks {
+		batchIdentifierHash := task.BatchIdentifierHash()
+		agg.taskMutex.Lock()
+		_, exists := agg.batchesIdxByIdentifierHash[batchIdentifierHash]
+		agg.taskMutex.Unlock()
+		if exists {
+			continue
+		}
+		agg.AddNewTask(task)
+	}
+	agg.logger.Info("Recovered not responded tasks from chain", "fromBlock", fromBlock,
+		"tasksV2", len(notRespondedTasksV2), "tasksV3", len(notRespondedTasksV3))
 }
 
 // |---RETRYABLE---|
 
-// Long-lived goroutine that periodically checks and removes old Tasks from stored Maps
-// It runs every GarbageCollectorPeriod and removes all tasks older than GarbageCollectorTasksAge
+// Long-lived goroutine that periodically removes old Tasks from stored Maps
+// It runs every GarbageCollectorPeriod and evicts the tasks older than GarbageCollectorTasksAge, see evictTasks
 // This was added because each task occupies memory in the maps, and we need to free it to avoid a memory leak
 func (agg *Aggregator) ClearTasksFromMaps() {
 	defer func() {
@@ -435,41 +952,15 @@ func (agg *Aggregator) ClearTasksFromMaps() {
 	}()
 
 	agg.AggregatorConfig.BaseConfig.Logger.Info(fmt.Sprintf("- Removing finalized Task Infos from Maps every %v", agg.AggregatorConfig.Aggregator.GarbageCollectorPeriod))
-	lastIdxDeleted := uint32(0)
 
 	for {
 		time.Sleep(agg.AggregatorConfig.Aggregator.GarbageCollectorPeriod)
 
 		agg.AggregatorConfig.BaseConfig.Logger.Info("Cleaning finalized tasks from maps")
-		oldTaskIdHash, err := agg.avsReader.GetOldTaskHash(agg.AggregatorConfig.Aggregator.GarbageCollectorTasksAge, agg.AggregatorConfig.Aggregator.GarbageCollectorTasksInterval)
-		if err != nil {
-			agg.logger.Error("Error getting old task hash, skipping this garbage collect", "err", err)
-			continue // Retry in the next iteration
-		}
-		if oldTaskIdHash == nil {
-			agg.logger.Warn("No old tasks found")
-			continue // Retry in the next iteration
-		}
 		agg.taskMutex.Lock()
 		agg.AggregatorConfig.BaseConfig.Logger.Info("- Locked Resources: Cleaning finalized tasks")
-
-		taskIdxToDelete := agg.batchesIdxByIdentifierHash[*oldTaskIdHash]
-		agg.logger.Info("Old task found", "taskIndex", taskIdxToDelete)
-		// delete from lastIdxDeleted to taskIdxToDelete
-		for i := lastIdxDeleted + 1; i <= taskIdxToDelete; i++ {
-			batchIdentifierHash, exists := agg.batchesIdentifierHashByIdx[i]
-			if exists {
-				agg.logger.Info("Cleaning up finalized task", "taskIndex", i)
-				delete(agg.batchesIdxByIdentifierHash, batchIdentifierHash)
-				delete(agg.batchCreatedBlockByIdx, i)
-				delete(agg.batchesIdentifierHashByIdx, i)
-				delete(agg.batchDataByIdentifierHash, batchIdentifierHash)
-				delete(agg.batchStartTimeByIdx, i)
-			} else {
-				agg.logger.Warn("Task not found in maps", "taskIndex", i)
-			}
-		}
-		lastIdxDeleted = taskIdxToDelete
+		agg.evictTasks(time.Now(), agg.maxTrackedTasks())
+		agg.metrics.SetTrackedTasks(len(agg.batchesIdentifierHashByIdx))
 		agg.taskMutex.Unlock()
 		agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Cleaning finalized tasks")
 		agg.AggregatorConfig.BaseConfig.Logger.Info("Done cleaning finalized tasks from maps")
@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/attestation"
+)
+
+// Results of writing a certificate, used as metric labels
+const (
+	CertificateWritten = "written"
+	CertificateFailed  = "failed"
+)
+
+// CertificateStore writes the attestation certificates of the tasks that reach quorum, and next to them
+// the operator set of each reference block, which they are verified against offline
+type CertificateStore struct {
+	dir string
+}
+
+func NewCertificateStore(dir string) (*CertificateStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create certificates directory %s: %w", dir, err)
+	}
+	return &CertificateStore{dir: dir}, nil
+}
+
+// Write writes the operator set of the reference block of the certificate, unless it was already written
+// for another task of the block, and then the certificate, returning its path.
+// The set is written first, so a certificate can always be verified with the set next to it
+func (s *CertificateStore) Write(certificate *attestation.Certificate, pubkeys map[eigentypes.OperatorId]*bls.G1Point) (string, error) {
+	operatorSetPath := filepath.Join(s.dir, attestation.OperatorSetFileName(certificate.ReferenceBlock))
+	if _, err := os.Stat(operatorSetPath); errors.Is(err, os.ErrNotExist) {
+		operatorSet := make(map[common.Hash]attestation.G1Point, len(pubkeys))
+		for operatorId, pubkey := range pubkeys {
+			operatorSet[common.Hash(operatorId)] = attestation.NewG1PointFromBls(pubkey)
+		}
+		if _, err := attestation.WriteOperatorSet(s.dir, certificate.ReferenceBlock, operatorSet); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+	return attestation.WriteCertificate(s.dir, certificate)
+}
+
+// Path returns the path of the certificate of the task
+func (s *CertificateStore) Path(batchIdentifierHash [32]byte) string {
+	return filepath.Join(s.dir, attestation.CertificateFileName(batchIdentifierHash))
+}
+
+// writeAttestationCertificate writes the certificate of a task that reached quorum.
+// The signers are the operators registered at the task created block that are not non signers,
+// the same set the BLS aggregation service aggregated the signature of
+func (agg *Aggregator) writeAttestationCertificate(response AggregatedResponse) {
+	if agg.certificates == nil {
+		return
+	}
+	batchIdentifierHash := response.BatchIdentifierHash
+
+	quorumConfig := agg.getQuorumConfig()
+	if taskInfo, ok := agg.taskInfos.get(batchIdentifierHash); ok {
+		quorumConfig = taskInfo.QuorumConfig
+	}
+	operators, err := agg.operatorStates.get(response.TaskCreatedBlock, agg.fetchOperatorStates(response.TaskCreatedBlock, quorumConfig.QuorumNums))
+	if err != nil {
+		agg.metrics.IncAttestationCertificates(CertificateFailed)
+		agg.logger.Warn("Failed to get operators, not writing the attestation certificate", "err", err,
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+		return
+	}
+
+	nonSigners := make(map[eigentypes.OperatorId]struct{})
+	for _, nonSignerPubkey := range response.NonSignerStakesAndSignature.NonSignerPubkeys {
+		nonSigners[eigentypes.OperatorIdFromG1Pubkey(bls.NewG1Point(nonSignerPubkey.X, nonSignerPubkey.Y))] = struct{}{}
+	}
+	signers := make([]eigentypes.OperatorId, 0, len(operators))
+	pubkeys := make(map[eigentypes.OperatorId]*bls.G1Point, len(operators))
+	for operatorId, operator := range operators {
+		if operator.OperatorInfo.Pubkeys.G1Pubkey != nil {
+			pubkeys[operatorId] = operator.OperatorInfo.Pubkeys.G1Pubkey
+		}
+		if _, ok := nonSigners[operatorId]; !ok {
+			signers = append(signers, operatorId)
+		}
+	}
+
+	certificate := attestation.NewCertificate(response.BatchMerkleRoot, response.SenderAddress, response.TaskCreatedBlock,
+		quorumConfig.QuorumNums, quorumConfig.QuorumThresholdPercentages, signers, response.NonSignerStakesAndSignature, time.Now())
+	path, err := agg.certificates.Write(certificate, pubkeys)
+	if err != nil {
+		agg.metrics.IncAttestationCertificates(CertificateFailed)
+		agg.logger.Warn("Failed to write attestation certificate", "err", err,
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+		return
+	}
+	agg.metrics.IncAttestationCertificates(CertificateWritten)
+	agg.logger.Info("Wrote attestation certificate", "path", path,
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]), "signers", len(signers))
+}
@@ -0,0 +1,60 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/core/attestation"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+func TestCertificateStoreWritesVerifiableCertificates(t *testing.T) {
+	batchMerkleRoot, senderAddress := [32]byte{1}, [20]byte{2}
+	keyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Could not generate BLS keys: %v", err)
+	}
+	operatorId := eigentypes.OperatorIdFromG1Pubkey(keyPair.GetPubKeyG1())
+	signature := keyPair.SignMessage(attestation.BatchIdentifierHash(batchMerkleRoot, senderAddress))
+
+	certificate := attestation.NewCertificate(batchMerkleRoot, senderAddress, 10, eigentypes.QuorumNums{0}, eigentypes.QuorumThresholdPercentages{67},
+		[]eigentypes.OperatorId{operatorId}, servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature{
+			QuorumApks: []servicemanager.BN254G1Point{utils.ConvertToBN254G1Point(keyPair.GetPubKeyG1())},
+			ApkG2:      utils.ConvertToBN254G2Point(keyPair.GetPubKeyG2()),
+			Sigma:      utils.ConvertToBN254G1Point(signature.G1Point),
+		}, time.Now())
+
+	dir := filepath.Join(t.TempDir(), "certificates")
+	store, err := NewCertificateStore(dir)
+	if err != nil {
+		t.Fatalf("Could not create certificate store: %v", err)
+	}
+	path, err := store.Write(certificate, map[eigentypes.OperatorId]*bls.G1Point{operatorId: keyPair.GetPubKeyG1()})
+	if err != nil {
+		t.Fatalf("Could not write certificate: %v", err)
+	}
+	if path != store.Path(certificate.BatchIdentifierHash) {
+		t.Errorf("Expected the certificate at %s, got %s", store.Path(certificate.BatchIdentifierHash), path)
+	}
+
+	// The verifier must read the operator set as the store writes it, next to the certificate
+	operatorSet, err := attestation.ReadOperatorSet(filepath.Join(dir, attestation.OperatorSetFileName(certificate.ReferenceBlock)))
+	if err != nil {
+		t.Fatalf("Could not read operator set: %v", err)
+	}
+	if _, ok := operatorSet[common.Hash(operatorId)]; !ok {
+		t.Fatalf("Expected the operator key to be stored")
+	}
+	read, err := attestation.ReadCertificate(path)
+	if err != nil {
+		t.Fatalf("Could not read certificate: %v", err)
+	}
+	if err := attestation.VerifyOffline(read, operatorSet); err != nil {
+		t.Errorf("Expected the written certificate to be valid, got %v", err)
+	}
+}
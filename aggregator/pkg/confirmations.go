@@ -0,0 +1,455 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+const (
+	// DefaultRespondToTaskConfirmations is used when `respond_to_task_confirmations` is not set
+	DefaultRespondToTaskConfirmations = 12
+	// DefaultConfirmationCheckInterval is used when `confirmation_check_interval` is not set
+	DefaultConfirmationCheckInterval = 12 * time.Second
+)
+
+// Events of the respond to task transactions waiting for confirmations, used as metric labels
+const (
+	ConfirmationEventConfirmed          = "confirmed"
+	ConfirmationEventMoved              = "moved"
+	ConfirmationEventReorged            = "reorged"
+	ConfirmationEventRespondedByOtherTx = "responded_by_other_tx"
+	ConfirmationEventRebroadcast        = "rebroadcast"
+	ConfirmationEventResubmitted        = "resubmitted"
+)
+
+type confirmationStatus int
+
+const (
+	confirmationPending confirmationStatus = iota
+	confirmationConfirmed
+	// The transaction was included again in another block after a reorg
+	confirmationMoved
+	// The transaction is no longer in the chain, or reverted after being included again
+	confirmationMissing
+)
+
+// unconfirmedResponse is an aggregated response whose transaction was included in a block
+// that does not have enough confirmations yet
+type unconfirmedResponse struct {
+	response    AggregatedResponse
+	txHash      common.Hash
+	blockNumber uint64
+	blockHash   common.Hash
+	// Signed transaction, broadcast again if it is reorged out. Nil if it could not be fetched
+	tx *gethtypes.Transaction
+	// Whether the transaction was broadcast again after a reorg and is waiting to be included
+	rebroadcast bool
+}
+
+// StoredUnconfirmedResponse is an unconfirmed response as kept in the unconfirmed response store file,
+// with the aggregated signature and the signed transaction so a reorg can be recovered after a restart
+type StoredUnconfirmedResponse struct {
+	BatchIdentifierHash         [32]byte                                                       `json:"batch_identifier_hash"`
+	BatchMerkleRoot             [32]byte                                                       `json:"batch_merkle_root"`
+	SenderAddress               [20]byte                                                       `json:"sender_address"`
+	TaskCreatedBlock            uint32                                                         `json:"task_created_block"`
+	NonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature `json:"non_signer_stakes_and_signature"`
+	TxHash                      common.Hash                                                    `json:"tx_hash"`
+	BlockNumber                 uint64                                                         `json:"block_number"`
+	BlockHash                   common.Hash                                                    `json:"block_hash"`
+	RawTx                       []byte                                                         `json:"raw_tx,omitempty"`
+	Rebroadcast                 bool                                                           `json:"rebroadcast"`
+}
+
+func (u *unconfirmedResponse) stored() StoredUnconfirmedResponse {
+	stored := StoredUnconfirmedResponse{
+		BatchIdentifierHash:         u.response.BatchIdentifierHash,
+		BatchMerkleRoot:             u.response.BatchMerkleRoot,
+		SenderAddress:               u.response.SenderAddress,
+		TaskCreatedBlock:            u.response.TaskCreatedBlock,
+		NonSignerStakesAndSignature: u.response.NonSignerStakesAndSignature,
+		TxHash:                      u.txHash,
+		BlockNumber:                 u.blockNumber,
+		BlockHash:                   u.blockHash,
+		Rebroadcast:                 u.rebroadcast,
+	}
+	if u.tx != nil {
+		if rawTx, err := u.tx.MarshalBinary(); err == nil {
+			stored.RawTx = rawTx
+		}
+	}
+	return stored
+}
+
+func (s StoredUnconfirmedResponse) unconfirmedResponse() *unconfirmedResponse {
+	unconfirmed := &unconfirmedResponse{
+		response: AggregatedResponse{
+			BatchIdentifierHash:         s.BatchIdentifierHash,
+			BatchMerkleRoot:             s.BatchMerkleRoot,
+			SenderAddress:               s.SenderAddress,
+			TaskCreatedBlock:            s.TaskCreatedBlock,
+			NonSignerStakesAndSignature: s.NonSignerStakesAndSignature,
+		},
+		txHash:      s.TxHash,
+		blockNumber: s.BlockNumber,
+		blockHash:   s.BlockHash,
+		rebroadcast: s.Rebroadcast,
+	}
+	if len(s.RawTx) > 0 {
+		tx := new(gethtypes.Transaction)
+		if err := tx.UnmarshalBinary(s.RawTx); err == nil {
+			unconfirmed.tx = tx
+		}
+	}
+	return unconfirmed
+}
+
+// confirmationTracker keeps the unconfirmed responses by batch identifier hash, so they can be sent again
+// if their transaction is reorged out.
+// If a store path is set, they are mirrored to a JSON file and loaded back on restart
+type confirmationTracker struct {
+	responses             map[[32]byte]*unconfirmedResponse
+	requiredConfirmations uint64
+	// Nil if the unconfirmed responses are only kept in memory
+	store *jsonFileMap[StoredUnconfirmedResponse]
+	mutex sync.Mutex
+}
+
+func newConfirmationTracker(requiredConfirmations uint64, storePath string) (*confirmationTracker, error) {
+	if requiredConfirmations == 0 {
+		requiredConfirmations = DefaultRespondToTaskConfirmations
+	}
+	tracker := &confirmationTracker{
+		responses:             make(map[[32]byte]*unconfirmedResponse),
+		requiredConfirmations: requiredConfirmations,
+	}
+	if storePath == "" {
+		return tracker, nil
+	}
+
+	store, err := newJsonFileMap[StoredUnconfirmedResponse](storePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open unconfirmed response store: %w", err)
+	}
+	tracker.store = store
+	for _, stored := range store.values() {
+		tracker.responses[stored.BatchIdentifierHash] = stored.unconfirmedResponse()
+	}
+	return tracker, nil
+}
+
+// track adds the response included with receipt. tx is its signed transaction, nil if unknown
+func (t *confirmationTracker) track(response AggregatedResponse, receipt *gethtypes.Receipt, tx *gethtypes.Transaction) error {
+	unconfirmed := &unconfirmedResponse{
+		response:    response,
+		txHash:      receipt.TxHash,
+		blockNumber: receipt.BlockNumber.Uint64(),
+		blockHash:   receipt.BlockHash,
+		tx:          tx,
+	}
+	t.mutex.Lock()
+	t.responses[response.BatchIdentifierHash] = unconfirmed
+	t.mutex.Unlock()
+	return t.save(unconfirmed)
+}
+
+// moveTo sets the transaction and block the response is now included in
+func (t *confirmationTracker) moveTo(batchIdentifierHash [32]byte, txHash common.Hash, blockNumber uint64, blockHash common.Hash) error {
+	return t.update(batchIdentifierHash, func(unconfirmed *unconfirmedResponse) {
+		if unconfirmed.txHash != txHash {
+			// The signed transaction is the one of another sender
+			unconfirmed.tx = nil
+		}
+		unconfirmed.txHash = txHash
+		unconfirmed.blockNumber = blockNumber
+		unconfirmed.blockHash = blockHash
+		unconfirmed.rebroadcast = false
+	})
+}
+
+// rebroadcastAt flags the transaction of the response as broadcast again at latestBlock,
+// so it is checked again once the chain moves past it
+func (t *confirmationTracker) rebroadcastAt(batchIdentifierHash [32]byte, latestBlock uint64) error {
+	return t.update(batchIdentifierHash, func(unconfirmed *unconfirmedResponse) {
+		unconfirmed.blockNumber = latestBlock + 1
+		unconfirmed.blockHash = common.Hash{}
+		unconfirmed.rebroadcast = true
+	})
+}
+
+func (t *confirmationTracker) update(batchIdentifierHash [32]byte, updateFunc func(unconfirmed *unconfirmedResponse)) error {
+	t.mutex.Lock()
+	unconfirmed, ok := t.responses[batchIdentifierHash]
+	if ok {
+		updateFunc(unconfirmed)
+		copied := *unconfirmed
+		unconfirmed = &copied
+	}
+	t.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	return t.save(unconfirmed)
+}
+
+func (t *confirmationTracker) save(unconfirmed *unconfirmedResponse) error {
+	if t.store == nil {
+		return nil
+	}
+	return t.store.set(unconfirmed.response.BatchIdentifierHash, unconfirmed.stored())
+}
+
+func (t *confirmationTracker) remove(batchIdentifierHash [32]byte) error {
+	t.mutex.Lock()
+	delete(t.responses, batchIdentifierHash)
+	t.mutex.Unlock()
+	if t.store == nil {
+		return nil
+	}
+	return t.store.delete(batchIdentifierHash)
+}
+
+func (t *confirmationTracker) len() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return len(t.responses)
+}
+
+// list returns copies of the unconfirmed responses
+func (t *confirmationTracker) list() []unconfirmedResponse {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	responses := make([]unconfirmedResponse, 0, len(t.responses))
+	for _, unconfirmed := range t.responses {
+		responses = append(responses, *unconfirmed)
+	}
+	return responses
+}
+
+// check returns the status of the response, given the current receipt of its transaction (nil if it was not found)
+// and the latest block. A response is confirmed once requiredConfirmations blocks were built on top of its block
+func (t *confirmationTracker) check(unconfirmed *unconfirmedResponse, receipt *gethtypes.Receipt, latestBlock uint64) confirmationStatus {
+	if receipt == nil || receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		// The node may not have caught up with the block of the transaction yet
+		if latestBlock < unconfirmed.blockNumber {
+			return confirmationPending
+		}
+		return confirmationMissing
+	}
+	if receipt.BlockHash != unconfirmed.blockHash {
+		return confirmationMoved
+	}
+	if latestBlock >= receipt.BlockNumber.Uint64()+t.requiredConfirmations {
+		return confirmationConfirmed
+	}
+	return confirmationPending
+}
+
+// waitForConfirmations tracks the transaction of a sent response until it is confirmed.
+// Without receipt the batch was already responded by another transaction, so the task is done
+func (agg *Aggregator) waitForConfirmations(response AggregatedResponse, receipt *gethtypes.Receipt) {
+	if receipt == nil {
+		if err := agg.taskStore.DeleteTask(response.BatchIdentifierHash); err != nil {
+			agg.logger.Warn("Failed to remove responded task from task store", "err", err,
+				"batchIdentifierHash", "0x"+hex.EncodeToString(response.BatchIdentifierHash[:]))
+		}
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tx, err := agg.avsWriter.TransactionByHashRetryable(ctx, receipt.TxHash, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Warn("Failed to get respond to task transaction, a new one is sent if it is reorged out", "err", err,
+			"txHash", receipt.TxHash.String())
+		tx = nil
+	}
+	if err := agg.confirmations.track(response, receipt, tx); err != nil {
+		agg.logger.Warn("Failed to store unconfirmed response, a reorg after a restart won't be recovered", "err", err)
+	}
+	agg.metrics.SetUnconfirmedResponses(agg.confirmations.len())
+}
+
+// Long-lived goroutine that periodically checks the respond to task transactions waiting for confirmations.
+// Transactions reorged out are replaced by the BatchVerified event of another transaction if there is one,
+// otherwise the aggregated response is sent again
+func (agg *Aggregator) WatchConfirmations(ctx context.Context) {
+	checkInterval := agg.AggregatorConfig.Aggregator.ConfirmationCheckInterval
+	if checkInterval == 0 {
+		checkInterval = DefaultConfirmationCheckInterval
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Only the leader sends responses, so only the leader re-sends them
+			if !agg.isLeader() {
+				continue
+			}
+			agg.checkConfirmations(ctx)
+		}
+	}
+}
+
+func (agg *Aggregator) checkConfirmations(ctx context.Context) {
+	unconfirmedResponses := agg.confirmations.list()
+	if len(unconfirmedResponses) == 0 {
+		return
+	}
+
+	latestBlock, err := agg.avsSubscriber.BlockNumberRetryable(ctx, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Warn("Failed to get latest block, skipping confirmations check", "err", err)
+		return
+	}
+
+	for i := range unconfirmedResponses {
+		unconfirmed := &unconfirmedResponses[i]
+		batchIdentifierHash := unconfirmed.response.BatchIdentifierHash
+		receipt, err := agg.avsWriter.TransactionReceiptRetryable(ctx, unconfirmed.txHash, retry.NetworkRetryParams())
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			agg.logger.Warn("Failed to get respond to task receipt", "err", err, "txHash", unconfirmed.txHash.String())
+			continue
+		}
+
+		switch agg.confirmations.check(unconfirmed, receipt, latestBlock) {
+		case confirmationConfirmed:
+			agg.responseConfirmed(unconfirmed)
+		case confirmationMoved:
+			agg.logger.Info("Respond to task transaction was included in another block",
+				"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+				"txHash", receipt.TxHash.String(), "block", receipt.BlockNumber)
+			if err := agg.confirmations.moveTo(batchIdentifierHash, receipt.TxHash, receipt.BlockNumber.Uint64(), receipt.BlockHash); err != nil {
+				agg.logger.Warn("Failed to store unconfirmed response", "err", err)
+			}
+			agg.metrics.IncConfirmationEvents(ConfirmationEventMoved)
+		case confirmationMissing:
+			agg.recoverReorgedResponse(ctx, unconfirmed, latestBlock)
+		}
+	}
+	agg.metrics.SetUnconfirmedResponses(agg.confirmations.len())
+}
+
+func (agg *Aggregator) responseConfirmed(unconfirmed *unconfirmedResponse) {
+	batchIdentifierHash := unconfirmed.response.BatchIdentifierHash
+	if err := agg.confirmations.remove(batchIdentifierHash); err != nil {
+		agg.logger.Warn("Failed to remove confirmed response from the unconfirmed response store", "err", err)
+	}
+	agg.taskInfos.confirmed(batchIdentifierHash, unconfirmed.txHash.String())
+	if err := agg.taskStore.DeleteTask(batchIdentifierHash); err != nil {
+		agg.logger.Warn("Failed to remove confirmed task from task store", "err", err,
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+	}
+	agg.metrics.IncConfirmationEvents(ConfirmationEventConfirmed)
+	agg.logger.Info("Respond to task transaction confirmed",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+		"txHash", unconfirmed.txHash.String(), "block", unconfirmed.blockNumber)
+}
+
+// recoverReorgedResponse handles a response whose transaction is no longer in the chain.
+// If another transaction verified the batch, such as the one of another aggregator instance, it is tracked instead.
+// Otherwise the original transaction is broadcast again, as a new one would compete with it while it may still be
+// in the mempool. The aggregated response is only sent again if the original transaction is unknown or its nonce was taken
+func (agg *Aggregator) recoverReorgedResponse(ctx context.Context, unconfirmed *unconfirmedResponse, latestBlock uint64) {
+	response := unconfirmed.response
+	batchIdentifierHash := response.BatchIdentifierHash
+	if !unconfirmed.rebroadcast {
+		agg.metrics.IncConfirmationEvents(ConfirmationEventReorged)
+		agg.logger.Warn("Respond to task transaction is no longer in the chain",
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+			"txHash", unconfirmed.txHash.String(), "block", unconfirmed.blockNumber)
+	}
+
+	batchVerified, err := agg.findBatchVerified(ctx, response, latestBlock)
+	if err != nil {
+		agg.logger.Warn("Failed to get BatchVerified events, will check again", "err", err)
+		return
+	}
+	if batchVerified != nil {
+		agg.logger.Info("Batch was verified by another transaction, waiting for its confirmations",
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+			"txHash", batchVerified.Raw.TxHash.String())
+		if err := agg.confirmations.moveTo(batchIdentifierHash, batchVerified.Raw.TxHash, batchVerified.Raw.BlockNumber, batchVerified.Raw.BlockHash); err != nil {
+			agg.logger.Warn("Failed to store unconfirmed response", "err", err)
+		}
+		agg.metrics.IncConfirmationEvents(ConfirmationEventRespondedByOtherTx)
+		return
+	}
+	// The event of the transaction that responded the batch may not be available yet
+	batchState, err := agg.avsWriter.BatchesStateRetryable(&bind.CallOpts{Context: ctx}, batchIdentifierHash, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Warn("Failed to get batch state, will check again", "err", err)
+		return
+	}
+	if batchState.Responded {
+		agg.logger.Info("Batch is responded onchain, waiting for its BatchVerified event",
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+		return
+	}
+
+	if unconfirmed.tx != nil {
+		err := agg.avsWriter.RebroadcastTransaction(ctx, unconfirmed.tx)
+		if err == nil {
+			if !unconfirmed.rebroadcast {
+				agg.metrics.IncConfirmationEvents(ConfirmationEventRebroadcast)
+				agg.logger.Info("Respond to task transaction broadcast again",
+					"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+					"txHash", unconfirmed.txHash.String())
+			}
+			if err := agg.confirmations.rebroadcastAt(batchIdentifierHash, latestBlock); err != nil {
+				agg.logger.Warn("Failed to store unconfirmed response", "err", err)
+			}
+			return
+		}
+		if !errors.Is(err, chainio.ErrNonceTaken) {
+			agg.logger.Warn("Failed to broadcast the respond to task transaction again, will check again", "err", err,
+				"txHash", unconfirmed.txHash.String())
+			return
+		}
+		agg.logger.Info("Nonce of the respond to task transaction was taken by another transaction",
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+			"txHash", unconfirmed.txHash.String())
+	}
+
+	if err := agg.confirmations.remove(batchIdentifierHash); err != nil {
+		agg.logger.Warn("Failed to remove resubmitted response from the unconfirmed response store", "err", err)
+	}
+	agg.metrics.IncConfirmationEvents(ConfirmationEventResubmitted)
+	agg.logger.Info("Sending the aggregated response again",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+	go agg.respondToTask(response)
+}
+
+// findBatchVerified returns the BatchVerified event of the batch emitted since the task was created, if any
+func (agg *Aggregator) findBatchVerified(ctx context.Context, response AggregatedResponse, latestBlock uint64) (*servicemanager.ContractAlignedLayerServiceManagerBatchVerified, error) {
+	filterOpts := &bind.FilterOpts{Start: uint64(response.TaskCreatedBlock), End: &latestBlock, Context: ctx}
+	logs, err := agg.avsSubscriber.FilterBatchVerifiedRetryable(filterOpts, [][32]byte{response.BatchMerkleRoot}, retry.NetworkRetryParams())
+	if err != nil {
+		return nil, err
+	}
+	defer logs.Close()
+
+	var batchVerified *servicemanager.ContractAlignedLayerServiceManagerBatchVerified
+	for logs.Next() {
+		if logs.Event.SenderAddress == response.SenderAddress && !logs.Event.Raw.Removed {
+			batchVerified = logs.Event
+		}
+	}
+	return batchVerified, logs.Error()
+}
@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+func confirmationReceipt(txHash common.Hash, blockNumber int64, blockHash common.Hash) *gethtypes.Receipt {
+	return &gethtypes.Receipt{
+		Status:      gethtypes.ReceiptStatusSuccessful,
+		TxHash:      txHash,
+		BlockNumber: big.NewInt(blockNumber),
+		BlockHash:   blockHash,
+	}
+}
+
+func TestConfirmationTrackerCheck(t *testing.T) {
+	tracker, _ := newConfirmationTracker(3, "")
+	txHash, blockHash := common.Hash{1}, common.Hash{2}
+	response := AggregatedResponse{BatchIdentifierHash: [32]byte{3}}
+	receipt := confirmationReceipt(txHash, 100, blockHash)
+	tracker.track(response, receipt, nil)
+
+	unconfirmed := tracker.list()[0]
+	if status := tracker.check(&unconfirmed, receipt, 102); status != confirmationPending {
+		t.Errorf("Expected pending with 2 blocks on top, got %v", status)
+	}
+	if status := tracker.check(&unconfirmed, receipt, 103); status != confirmationConfirmed {
+		t.Errorf("Expected confirmed with 3 blocks on top, got %v", status)
+	}
+
+	// Reorged out
+	if status := tracker.check(&unconfirmed, nil, 101); status != confirmationMissing {
+		t.Errorf("Expected missing without receipt, got %v", status)
+	}
+	// The node is behind the block of the transaction
+	if status := tracker.check(&unconfirmed, nil, 99); status != confirmationPending {
+		t.Errorf("Expected pending while the node is behind, got %v", status)
+	}
+	// Included again in another block, but reverted
+	reverted := confirmationReceipt(txHash, 101, common.Hash{4})
+	reverted.Status = gethtypes.ReceiptStatusFailed
+	if status := tracker.check(&unconfirmed, reverted, 101); status != confirmationMissing {
+		t.Errorf("Expected missing for a reverted transaction, got %v", status)
+	}
+
+	// Included again in another block
+	moved := confirmationReceipt(txHash, 101, common.Hash{4})
+	if status := tracker.check(&unconfirmed, moved, 110); status != confirmationMoved {
+		t.Errorf("Expected moved, got %v", status)
+	}
+	tracker.moveTo(response.BatchIdentifierHash, moved.TxHash, moved.BlockNumber.Uint64(), moved.BlockHash)
+	unconfirmed = tracker.list()[0]
+	if status := tracker.check(&unconfirmed, moved, 103); status != confirmationPending {
+		t.Errorf("Confirmations should be counted from the new block, got %v", status)
+	}
+	if status := tracker.check(&unconfirmed, moved, 104); status != confirmationConfirmed {
+		t.Errorf("Expected confirmed in the new block, got %v", status)
+	}
+
+	tracker.remove(response.BatchIdentifierHash)
+	if tracker.len() != 0 {
+		t.Errorf("Expected no unconfirmed responses")
+	}
+}
+
+func TestConfirmationTrackerDefaultConfirmations(t *testing.T) {
+	if tracker, _ := newConfirmationTracker(0, ""); tracker.requiredConfirmations != DefaultRespondToTaskConfirmations {
+		t.Errorf("Expected %d confirmations by default, got %d", DefaultRespondToTaskConfirmations, tracker.requiredConfirmations)
+	}
+}
+
+func TestConfirmationTrackerStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "unconfirmed_responses.json")
+	tracker, err := newConfirmationTracker(3, path)
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	tx := gethtypes.NewTx(&gethtypes.LegacyTx{Nonce: 7, GasPrice: big.NewInt(1), Gas: 21000})
+	response := AggregatedResponse{BatchIdentifierHash: [32]byte{1}, TaskCreatedBlock: 90}
+	receipt := confirmationReceipt(tx.Hash(), 100, common.Hash{2})
+	if err := tracker.track(response, receipt, tx); err != nil {
+		t.Fatalf("Failed to track response: %v", err)
+	}
+	if err := tracker.rebroadcastAt(response.BatchIdentifierHash, 105); err != nil {
+		t.Fatalf("Failed to flag rebroadcast: %v", err)
+	}
+
+	// Loaded back after a restart, with the signed transaction
+	reloaded, err := newConfirmationTracker(3, path)
+	if err != nil {
+		t.Fatalf("Failed to reload tracker: %v", err)
+	}
+	unconfirmed := reloaded.list()
+	if len(unconfirmed) != 1 {
+		t.Fatalf("Expected 1 unconfirmed response, got %d", len(unconfirmed))
+	}
+	if unconfirmed[0].response.TaskCreatedBlock != 90 || unconfirmed[0].txHash != tx.Hash() {
+		t.Errorf("Unexpected unconfirmed response %+v", unconfirmed[0])
+	}
+	if !unconfirmed[0].rebroadcast || unconfirmed[0].blockNumber != 106 {
+		t.Errorf("Expected the rebroadcast to be stored, got block %d", unconfirmed[0].blockNumber)
+	}
+	if unconfirmed[0].tx == nil || unconfirmed[0].tx.Hash() != tx.Hash() {
+		t.Errorf("Expected the signed transaction to be stored")
+	}
+
+	// Verified by another transaction, whose signed transaction is unknown
+	if err := reloaded.moveTo(response.BatchIdentifierHash, common.Hash{3}, 106, common.Hash{4}); err != nil {
+		t.Fatalf("Failed to move response: %v", err)
+	}
+	if moved := reloaded.list()[0]; moved.tx != nil || moved.rebroadcast {
+		t.Errorf("Expected the signed transaction to be dropped when moved to another transaction")
+	}
+
+	if err := reloaded.remove(response.BatchIdentifierHash); err != nil {
+		t.Fatalf("Failed to remove response: %v", err)
+	}
+	if reloaded, _ := newConfirmationTracker(3, path); reloaded.len() != 0 {
+		t.Errorf("Expected the removed response to be deleted from the store")
+	}
+}
@@ -0,0 +1,246 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+// Events recorded in the cost ledger
+const (
+	// A RespondToTask transaction was sent, one per gas price bump
+	CostLedgerEventSent = "sent"
+	// The RespondToTask transaction was mined
+	CostLedgerEventMined = "mined"
+	// The batch was responded by another transaction, nothing was paid for it
+	CostLedgerEventAlreadyResponded = "already_responded"
+	// The response was deferred by the gas spend policy
+	CostLedgerEventDeferred = "deferred"
+	CostLedgerEventFailed   = "failed"
+)
+
+// CostLedgerEntry is a line of the cost ledger. Amounts are in wei, as decimal strings, empty if unknown
+type CostLedgerEntry struct {
+	Time                time.Time `json:"time"`
+	Event               string    `json:"event"`
+	BatchIdentifierHash string    `json:"batch_identifier_hash"`
+	BatchMerkleRoot     string    `json:"batch_merkle_root"`
+	SenderAddress       string    `json:"sender_address"`
+	FeeLimit            string    `json:"fee_limit"`
+	// Gas price of a sent transaction, the fee cap for EIP-1559 transactions
+	GasPrice          string `json:"gas_price,omitempty"`
+	GasUsed           uint64 `json:"gas_used,omitempty"`
+	EffectiveGasPrice string `json:"effective_gas_price,omitempty"`
+	Cost              string `json:"cost,omitempty"`
+	// Cost over the fee limit, paid by the aggregator
+	Overpayment string `json:"overpayment,omitempty"`
+	// Number of gas price bumps before this transaction
+	BumpCount int    `json:"bump_count"`
+	TxHash    string `json:"tx_hash,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+var costLedgerCsvHeader = []string{
+	"time", "event", "batch_identifier_hash", "batch_merkle_root", "sender_address", "fee_limit", "gas_price",
+	"gas_used", "effective_gas_price", "cost", "overpayment", "bump_count", "tx_hash", "error",
+}
+
+// CostLedger appends the entries as JSON lines to a file. Entries are never rewritten
+type CostLedger struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+func NewCostLedger(path string) (*CostLedger, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &CostLedger{file: file}, nil
+}
+
+func (l *CostLedger) Append(entry CostLedgerEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	_, err = l.file.Write(append(line, '\n'))
+	return err
+}
+
+// ReadCostLedger reads the entries of a cost ledger file, skipping the lines that are not entries
+func ReadCostLedger(reader io.Reader) ([]CostLedgerEntry, error) {
+	var entries []CostLedgerEntry
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry CostLedgerEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Event == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// CostLedgerFilter selects ledger entries. Zero values match every entry
+type CostLedgerFilter struct {
+	// Inclusive
+	From time.Time
+	// Exclusive
+	To            time.Time
+	SenderAddress string
+}
+
+func (f CostLedgerFilter) Apply(entries []CostLedgerEntry) []CostLedgerEntry {
+	var filtered []CostLedgerEntry
+	for _, entry := range entries {
+		if !f.From.IsZero() && entry.Time.Before(f.From) {
+			continue
+		}
+		if !f.To.IsZero() && !entry.Time.Before(f.To) {
+			continue
+		}
+		if f.SenderAddress != "" && !strings.EqualFold(entry.SenderAddress, f.SenderAddress) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+func WriteCostLedgerCsv(writer io.Writer, entries []CostLedgerEntry) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(costLedgerCsvHeader); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		var gasUsed string
+		if entry.GasUsed > 0 {
+			gasUsed = strconv.FormatUint(entry.GasUsed, 10)
+		}
+		err := csvWriter.Write([]string{
+			entry.Time.UTC().Format(time.RFC3339Nano), entry.Event, entry.BatchIdentifierHash, entry.BatchMerkleRoot,
+			entry.SenderAddress, entry.FeeLimit, entry.GasPrice, gasUsed, entry.EffectiveGasPrice, entry.Cost,
+			entry.Overpayment, strconv.Itoa(entry.BumpCount), entry.TxHash, entry.Error,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// costLedgerRecorder records the attempts and the outcome of sending one aggregated response
+type costLedgerRecorder struct {
+	ledger   *CostLedger
+	base     CostLedgerEntry
+	feeLimit *big.Int
+	sent     int
+	mutex    sync.Mutex
+}
+
+// newCostLedgerRecorder returns the recorder of the response, nil if the cost ledger is disabled.
+// The fee limit of the batch does not change, so it is fetched once
+func (agg *Aggregator) newCostLedgerRecorder(batchIdentifierHash [32]byte, batchMerkleRoot [32]byte, senderAddress [20]byte) *costLedgerRecorder {
+	if agg.costLedger == nil {
+		return nil
+	}
+	recorder := &costLedgerRecorder{
+		ledger: agg.costLedger,
+		base: CostLedgerEntry{
+			BatchIdentifierHash: "0x" + hex.EncodeToString(batchIdentifierHash[:]),
+			BatchMerkleRoot:     "0x" + hex.EncodeToString(batchMerkleRoot[:]),
+			SenderAddress:       "0x" + hex.EncodeToString(senderAddress[:]),
+		},
+	}
+	batchState, err := agg.avsWriter.BatchesStateRetryable(&bind.CallOpts{}, batchIdentifierHash, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Warn("Failed to get batch fee limit for the cost ledger", "err", err)
+	} else {
+		recorder.feeLimit = batchState.RespondToTaskFeeLimit
+		recorder.base.FeeLimit = batchState.RespondToTaskFeeLimit.String()
+	}
+	return recorder
+}
+
+func (r *costLedgerRecorder) txSent(tx *gethtypes.Transaction) error {
+	r.mutex.Lock()
+	entry := r.base
+	entry.BumpCount = r.sent
+	r.sent++
+	r.mutex.Unlock()
+
+	entry.Time = time.Now()
+	entry.Event = CostLedgerEventSent
+	entry.GasPrice = tx.GasPrice().String()
+	entry.TxHash = tx.Hash().String()
+	return r.ledger.Append(entry)
+}
+
+// outcome records the result of SendAggregatedResponse
+func (r *costLedgerRecorder) outcome(receipt *gethtypes.Receipt, sendErr error) error {
+	r.mutex.Lock()
+	entry := r.base
+	entry.BumpCount = max(r.sent-1, 0)
+	r.mutex.Unlock()
+
+	entry.Time = time.Now()
+	switch {
+	case errors.Is(sendErr, chainio.ErrGasPolicyDeferred):
+		entry.Event = CostLedgerEventDeferred
+		entry.Error = sendErr.Error()
+	case sendErr != nil:
+		entry.Event = CostLedgerEventFailed
+		entry.Error = sendErr.Error()
+	case receipt == nil:
+		entry.Event = CostLedgerEventAlreadyResponded
+	default:
+		entry.Event = CostLedgerEventMined
+		entry.TxHash = receipt.TxHash.String()
+		entry.GasUsed = receipt.GasUsed
+		if receipt.EffectiveGasPrice != nil {
+			cost := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+			entry.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+			entry.Cost = cost.String()
+			if r.feeLimit != nil {
+				overpayment := new(big.Int).Sub(cost, r.feeLimit)
+				if overpayment.Sign() < 0 {
+					overpayment.SetInt64(0)
+				}
+				entry.Overpayment = overpayment.String()
+			}
+		}
+	}
+	return r.ledger.Append(entry)
+}
+
+// ParseCostLedgerDate parses the dates of the cost ledger queries, either RFC 3339 times or YYYY-MM-DD days in UTC
+func ParseCostLedgerDate(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", value)
+	}
+	return parsed, nil
+}
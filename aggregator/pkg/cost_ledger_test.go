@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+func TestCostLedgerRecordsAttemptsAndOutcome(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cost_ledger.jsonl")
+	ledger, err := NewCostLedger(path)
+	if err != nil {
+		t.Fatalf("Could not create cost ledger: %v", err)
+	}
+	recorder := &costLedgerRecorder{
+		ledger:   ledger,
+		base:     CostLedgerEntry{SenderAddress: "0xAB", FeeLimit: "1000"},
+		feeLimit: big.NewInt(1000),
+	}
+
+	for _, gasPrice := range []int64{10, 12} {
+		tx := gethtypes.NewTx(&gethtypes.LegacyTx{GasPrice: big.NewInt(gasPrice)})
+		if err := recorder.txSent(tx); err != nil {
+			t.Fatalf("Could not record sent transaction: %v", err)
+		}
+	}
+	receipt := &gethtypes.Receipt{TxHash: common.Hash{1}, GasUsed: 100, EffectiveGasPrice: big.NewInt(12)}
+	if err := recorder.outcome(receipt, nil); err != nil {
+		t.Fatalf("Could not record outcome: %v", err)
+	}
+	if err := recorder.outcome(nil, fmt.Errorf("%w: spend_budget limit hit", chainio.ErrGasPolicyDeferred)); err != nil {
+		t.Fatalf("Could not record outcome: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Could not open cost ledger: %v", err)
+	}
+	defer file.Close()
+	entries, err := ReadCostLedger(file)
+	if err != nil {
+		t.Fatalf("Could not read cost ledger: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+	if entries[1].Event != CostLedgerEventSent || entries[1].BumpCount != 1 || entries[1].GasPrice != "12" {
+		t.Errorf("Unexpected bumped transaction entry: %+v", entries[1])
+	}
+	mined := entries[2]
+	if mined.Event != CostLedgerEventMined || mined.Cost != "1200" || mined.Overpayment != "200" || mined.BumpCount != 1 {
+		t.Errorf("Unexpected mined entry: %+v", mined)
+	}
+	if entries[3].Event != CostLedgerEventDeferred {
+		t.Errorf("Expected a deferred entry, got %s", entries[3].Event)
+	}
+}
+
+func TestCostLedgerFilterAndCsv(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	entries := []CostLedgerEntry{
+		{Time: day.Add(-time.Hour), Event: CostLedgerEventMined, SenderAddress: "0xab"},
+		{Time: day.Add(time.Hour), Event: CostLedgerEventMined, SenderAddress: "0xab", GasUsed: 100, Error: "a, b"},
+		{Time: day.Add(time.Hour), Event: CostLedgerEventMined, SenderAddress: "0xcd"},
+		{Time: day.Add(24 * time.Hour), Event: CostLedgerEventMined, SenderAddress: "0xab"},
+	}
+
+	from, err := ParseCostLedgerDate("2024-05-01")
+	if err != nil {
+		t.Fatalf("Could not parse date: %v", err)
+	}
+	to, err := ParseCostLedgerDate("2024-05-02T00:00:00Z")
+	if err != nil {
+		t.Fatalf("Could not parse date: %v", err)
+	}
+	if _, err := ParseCostLedgerDate("yesterday"); err == nil {
+		t.Errorf("Expected an error for an invalid date")
+	}
+
+	filtered := CostLedgerFilter{From: from, To: to, SenderAddress: "0xAB"}.Apply(entries)
+	if len(filtered) != 1 || filtered[0].GasUsed != 100 {
+		t.Fatalf("Expected only the entry of the sender within the range, got %+v", filtered)
+	}
+
+	var output bytes.Buffer
+	if err := WriteCostLedgerCsv(&output, filtered); err != nil {
+		t.Fatalf("Could not write csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "time,event,") || !strings.HasSuffix(lines[1], `,"a, b"`) {
+		t.Errorf("Unexpected csv output: %q", output.String())
+	}
+}
@@ -0,0 +1,335 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// DefaultDeadLetterRetryPeriod is used when `dead_letter_retry_period` is not set
+const DefaultDeadLetterRetryPeriod = 1 * time.Minute
+
+// DeadLetter is an aggregated response that failed to be sent onchain.
+// It keeps the aggregated signature so the response can be re-attempted without the operators signing again
+type DeadLetter struct {
+	BatchIdentifierHash         [32]byte                                                       `json:"batch_identifier_hash"`
+	BatchMerkleRoot             [32]byte                                                       `json:"batch_merkle_root"`
+	SenderAddress               [20]byte                                                       `json:"sender_address"`
+	TaskCreatedBlock            uint32                                                         `json:"task_created_block"`
+	NonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature `json:"non_signer_stakes_and_signature"`
+	Error                       string                                                         `json:"error"`
+	FailedAt                    time.Time                                                      `json:"failed_at"`
+	Attempts                    int                                                            `json:"attempts"`
+	// Whether the last failure reverts again whatever the conditions, see isPermanentFailure.
+	// Permanent dead letters are only re-attempted through the admin API
+	Permanent bool `json:"permanent"`
+	// Conditions at the time of the last failure, nil if they could not be fetched.
+	// The response is re-attempted automatically once any of them improves
+	AggregatorBalance *big.Int `json:"aggregator_balance"`
+	BatcherBalance    *big.Int `json:"batcher_balance"`
+	GasPrice          *big.Int `json:"gas_price"`
+}
+
+// DeadLetterStore keeps the aggregated responses that failed to be sent, until they are sent or expire
+type DeadLetterStore interface {
+	SaveDeadLetter(deadLetter DeadLetter) error
+	GetDeadLetter(batchIdentifierHash [32]byte) (DeadLetter, bool)
+	DeleteDeadLetter(batchIdentifierHash [32]byte) error
+	ListDeadLetters() []DeadLetter
+}
+
+// InMemoryDeadLetterStore keeps dead letters only in memory. Used when no dead letter store path is configured
+type InMemoryDeadLetterStore struct {
+	deadLetters map[[32]byte]DeadLetter
+	mutex       sync.Mutex
+}
+
+func NewInMemoryDeadLetterStore() *InMemoryDeadLetterStore {
+	return &InMemoryDeadLetterStore{
+		deadLetters: make(map[[32]byte]DeadLetter),
+	}
+}
+
+func (s *InMemoryDeadLetterStore) SaveDeadLetter(deadLetter DeadLetter) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.deadLetters[deadLetter.BatchIdentifierHash] = deadLetter
+	return nil
+}
+
+func (s *InMemoryDeadLetterStore) GetDeadLetter(batchIdentifierHash [32]byte) (DeadLetter, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	deadLetter, ok := s.deadLetters[batchIdentifierHash]
+	return deadLetter, ok
+}
+
+func (s *InMemoryDeadLetterStore) DeleteDeadLetter(batchIdentifierHash [32]byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.deadLetters, batchIdentifierHash)
+	return nil
+}
+
+func (s *InMemoryDeadLetterStore) ListDeadLetters() []DeadLetter {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	deadLetters := make([]DeadLetter, 0, len(s.deadLetters))
+	for _, deadLetter := range s.deadLetters {
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters
+}
+
+// FileDeadLetterStore keeps dead letters in memory and mirrors them to a JSON file on every change
+type FileDeadLetterStore struct {
+	deadLetters *jsonFileMap[DeadLetter]
+}
+
+func NewFileDeadLetterStore(path string) (*FileDeadLetterStore, error) {
+	deadLetters, err := newJsonFileMap[DeadLetter](path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dead letter store: %w", err)
+	}
+	return &FileDeadLetterStore{deadLetters: deadLetters}, nil
+}
+
+func (s *FileDeadLetterStore) SaveDeadLetter(deadLetter DeadLetter) error {
+	return s.deadLetters.set(deadLetter.BatchIdentifierHash, deadLetter)
+}
+
+func (s *FileDeadLetterStore) GetDeadLetter(batchIdentifierHash [32]byte) (DeadLetter, bool) {
+	return s.deadLetters.get(batchIdentifierHash)
+}
+
+func (s *FileDeadLetterStore) DeleteDeadLetter(batchIdentifierHash [32]byte) error {
+	return s.deadLetters.delete(batchIdentifierHash)
+}
+
+func (s *FileDeadLetterStore) ListDeadLetters() []DeadLetter {
+	return s.deadLetters.values()
+}
+
+// conditionsImproved reports whether it is worth re-attempting a dead letter:
+// the aggregator or the batcher were topped up, or the gas price dropped since the last failure.
+// Conditions that could not be fetched, now or at the time of the failure, count as improved
+func (d *DeadLetter) conditionsImproved(aggregatorBalance *big.Int, batcherBalance *big.Int, gasPrice *big.Int) bool {
+	if d.AggregatorBalance == nil || d.BatcherBalance == nil || d.GasPrice == nil ||
+		aggregatorBalance == nil || batcherBalance == nil || gasPrice == nil {
+		return true
+	}
+	return aggregatorBalance.Cmp(d.AggregatorBalance) > 0 ||
+		batcherBalance.Cmp(d.BatcherBalance) > 0 ||
+		gasPrice.Cmp(d.GasPrice) < 0
+}
+
+// isPermanentFailure reports whether sending the response again reverts the same way whatever the gas price and balances:
+// the aggregated signature does not verify or does not reach the quorum threshold, or no wallet is allowed to respond.
+// A batcher without funds is not, as topping it up fixes it
+func isPermanentFailure(err error) bool {
+	return errors.Is(err, chainio.ErrInvalidSignature) ||
+		errors.Is(err, chainio.ErrInvalidQuorumThreshold) ||
+		errors.Is(err, chainio.ErrSenderIsNotAggregator)
+}
+
+// fetchDeadLetterConditions returns the highest balance of the aggregator wallets, the batcher balance and the gas price.
+// Values that could not be fetched are returned as nil
+func (agg *Aggregator) fetchDeadLetterConditions(senderAddress [20]byte) (*big.Int, *big.Int, *big.Int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var aggregatorBalance *big.Int
+	for _, wallet := range agg.avsWriter.Wallets.Wallets() {
+		balance, err := agg.avsWriter.BalanceAtRetryable(ctx, wallet.Address, nil, retry.NetworkRetryParams())
+		if err != nil {
+			continue
+		}
+		if aggregatorBalance == nil || balance.Cmp(aggregatorBalance) > 0 {
+			aggregatorBalance = balance
+		}
+	}
+	batcherBalance, err := agg.avsWriter.BatcherBalancesRetryable(&bind.CallOpts{}, senderAddress, retry.NetworkRetryParams())
+	if err != nil {
+		batcherBalance = nil
+	}
+	gasPrice, err := utils.GetGasPriceRetryable(agg.avsWriter.Client, agg.avsWriter.ClientFallback, retry.NetworkRetryParams())
+	if err != nil {
+		gasPrice = nil
+	}
+	return aggregatorBalance, batcherBalance, gasPrice
+}
+
+// addDeadLetter stores an aggregated response that failed to be sent, with the conditions at the time of the failure
+func (agg *Aggregator) addDeadLetter(deadLetter DeadLetter, sendErr error) {
+	deadLetter.Error = sendErr.Error()
+	deadLetter.FailedAt = time.Now()
+	deadLetter.Permanent = isPermanentFailure(sendErr)
+	deadLetter.AggregatorBalance, deadLetter.BatcherBalance, deadLetter.GasPrice = agg.fetchDeadLetterConditions(deadLetter.SenderAddress)
+
+	if err := agg.deadLetterStore.SaveDeadLetter(deadLetter); err != nil {
+		agg.logger.Error("Failed to store dead letter, this batch will be lost", "err", err,
+			"batchIdentifierHash", "0x"+hex.EncodeToString(deadLetter.BatchIdentifierHash[:]))
+		return
+	}
+	agg.metrics.IncDeadLetters()
+	if deadLetter.Permanent {
+		agg.logger.Error("Aggregated response stored as dead letter, it will revert again and is only re-attempted through the admin API",
+			"batchIdentifierHash", "0x"+hex.EncodeToString(deadLetter.BatchIdentifierHash[:]),
+			"attempts", deadLetter.Attempts)
+		return
+	}
+	agg.logger.Warn("Aggregated response stored as dead letter, it will be re-attempted",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(deadLetter.BatchIdentifierHash[:]),
+		"attempts", deadLetter.Attempts)
+}
+
+// Long-lived goroutine that periodically re-attempts the dead letters whose conditions improved,
+// except the permanent ones, and drops the ones that were already responded onchain
+func (agg *Aggregator) ProcessDeadLetters(ctx context.Context) {
+	retryPeriod := agg.AggregatorConfig.Aggregator.DeadLetterRetryPeriod
+	if retryPeriod == 0 {
+		retryPeriod = DefaultDeadLetterRetryPeriod
+	}
+
+	ticker := time.NewTicker(retryPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Standbys keep their dead letters until they are elected
+			if !agg.isLeader() {
+				continue
+			}
+			deadLetters := agg.deadLetterStore.ListDeadLetters()
+			agg.metrics.SetPendingDeadLetters(len(deadLetters))
+			// oldest first
+			sort.Slice(deadLetters, func(i, j int) bool {
+				return deadLetters[i].FailedAt.Before(deadLetters[j].FailedAt)
+			})
+			for _, deadLetter := range deadLetters {
+				if agg.expireDeadLetter(deadLetter.BatchIdentifierHash) || deadLetter.Permanent {
+					continue
+				}
+				aggregatorBalance, batcherBalance, gasPrice := agg.fetchDeadLetterConditions(deadLetter.SenderAddress)
+				if !deadLetter.conditionsImproved(aggregatorBalance, batcherBalance, gasPrice) {
+					continue
+				}
+				agg.logger.Info("Conditions improved, re-attempting dead letter",
+					"batchIdentifierHash", "0x"+hex.EncodeToString(deadLetter.BatchIdentifierHash[:]))
+				_ = agg.ReplayDeadLetter(deadLetter.BatchIdentifierHash)
+			}
+		}
+	}
+}
+
+// expireDeadLetter removes the dead letter if its batch was already responded onchain, and reports if it did
+func (agg *Aggregator) expireDeadLetter(batchIdentifierHash [32]byte) bool {
+	batchState, err := agg.avsWriter.BatchesStateRetryable(&bind.CallOpts{}, batchIdentifierHash, retry.NetworkRetryParams())
+	if err != nil || !batchState.Responded {
+		return false
+	}
+	agg.logger.Info("Dead letter batch was already responded, removing it",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+	if err := agg.deadLetterStore.DeleteDeadLetter(batchIdentifierHash); err != nil {
+		agg.logger.Warn("Failed to remove dead letter", "err", err)
+	}
+	agg.metrics.IncDeadLettersExpired()
+	return true
+}
+
+// ReplayDeadLetter sends the stored aggregated response again, regardless of the current conditions.
+// Concurrent replays of the same dead letter are rejected
+func (agg *Aggregator) ReplayDeadLetter(batchIdentifierHash [32]byte) error {
+	if !agg.isLeader() {
+		return errNotLeader
+	}
+
+	agg.deadLetterReplayMutex.Lock()
+	if _, replaying := agg.deadLettersReplaying[batchIdentifierHash]; replaying {
+		agg.deadLetterReplayMutex.Unlock()
+		return fmt.Errorf("dead letter is already being replayed")
+	}
+	agg.deadLettersReplaying[batchIdentifierHash] = struct{}{}
+	agg.deadLetterReplayMutex.Unlock()
+
+	defer func() {
+		agg.deadLetterReplayMutex.Lock()
+		delete(agg.deadLettersReplaying, batchIdentifierHash)
+		agg.deadLetterReplayMutex.Unlock()
+	}()
+
+	deadLetter, ok := agg.deadLetterStore.GetDeadLetter(batchIdentifierHash)
+	if !ok {
+		return fmt.Errorf("dead letter not found")
+	}
+	if agg.expireDeadLetter(batchIdentifierHash) {
+		return nil
+	}
+
+	deadLetter.Attempts++
+	receipt, err := agg.sendAggregatedResponse(deadLetter.BatchIdentifierHash, deadLetter.BatchMerkleRoot, deadLetter.SenderAddress, deadLetter.NonSignerStakesAndSignature)
+	if errors.Is(err, errNotLeader) {
+		// Leadership was lost while waiting for the wallet, the dead letter is left untouched
+		return err
+	}
+	if errors.Is(err, chainio.ErrGasPolicyDeferred) && agg.gasPolicyDeferrals != nil {
+		// The response is held until the gas spend policy allows it, not retried as a failure
+		if err := agg.deadLetterStore.DeleteDeadLetter(batchIdentifierHash); err != nil {
+			agg.logger.Warn("Failed to remove deferred dead letter", "err", err)
+		}
+		agg.holdGasPolicyDeferredResponse(AggregatedResponse{
+			BatchIdentifierHash:         deadLetter.BatchIdentifierHash,
+			BatchMerkleRoot:             deadLetter.BatchMerkleRoot,
+			SenderAddress:               deadLetter.SenderAddress,
+			TaskCreatedBlock:            deadLetter.TaskCreatedBlock,
+			NonSignerStakesAndSignature: deadLetter.NonSignerStakesAndSignature,
+		}, err)
+		return err
+	}
+	if err != nil {
+		agg.logger.Error("Dead letter replay failed", "err", err,
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+			"attempts", deadLetter.Attempts)
+		agg.addDeadLetter(deadLetter, err)
+		agg.metrics.IncDeadLetterReplays("failed")
+		return err
+	}
+
+	txHash := "Unknown"
+	effectiveGasPrice := "Unknown"
+	if receipt != nil {
+		txHash = receipt.TxHash.String()
+		effectiveGasPrice = receipt.EffectiveGasPrice.String()
+	}
+	agg.telemetry.TaskSentToEthereum(deadLetter.BatchMerkleRoot, txHash, effectiveGasPrice)
+	agg.taskInfos.responded(batchIdentifierHash, txHash, effectiveGasPrice)
+	if err := agg.deadLetterStore.DeleteDeadLetter(batchIdentifierHash); err != nil {
+		agg.logger.Warn("Failed to remove replayed dead letter", "err", err)
+	}
+	agg.waitForConfirmations(AggregatedResponse{
+		BatchIdentifierHash:         deadLetter.BatchIdentifierHash,
+		BatchMerkleRoot:             deadLetter.BatchMerkleRoot,
+		SenderAddress:               deadLetter.SenderAddress,
+		TaskCreatedBlock:            deadLetter.TaskCreatedBlock,
+		NonSignerStakesAndSignature: deadLetter.NonSignerStakesAndSignature,
+	}, receipt)
+	agg.metrics.IncDeadLetterReplays("succeeded")
+	agg.logger.Info("Dead letter replayed successfully",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+		"txHash", txHash)
+	return nil
+}
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+func TestDeadLetterConditionsImproved(t *testing.T) {
+	deadLetter := DeadLetter{
+		AggregatorBalance: big.NewInt(100),
+		BatcherBalance:    big.NewInt(100),
+		GasPrice:          big.NewInt(10),
+	}
+
+	cases := []struct {
+		name              string
+		aggregatorBalance *big.Int
+		batcherBalance    *big.Int
+		gasPrice          *big.Int
+		want              bool
+	}{
+		{"Nothing changed", big.NewInt(100), big.NewInt(100), big.NewInt(10), false},
+		{"Conditions got worse", big.NewInt(90), big.NewInt(90), big.NewInt(11), false},
+		{"Aggregator topped up", big.NewInt(101), big.NewInt(100), big.NewInt(10), true},
+		{"Batcher topped up", big.NewInt(100), big.NewInt(101), big.NewInt(10), true},
+		{"Gas price dropped", big.NewInt(100), big.NewInt(100), big.NewInt(9), true},
+		{"Gas price unknown", big.NewInt(100), big.NewInt(100), nil, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := deadLetter.conditionsImproved(c.aggregatorBalance, c.batcherBalance, c.gasPrice)
+			if got != c.want {
+				t.Errorf("Expected conditionsImproved to be %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestIsPermanentFailure(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Invalid signature", chainio.DecodeRevertError(errors.New("execution reverted: signature is invalid")), true},
+		{"Quorum threshold not reached", chainio.DecodeRevertError(errors.New("execution reverted: custom error 0xa61eb88a")), true},
+		{"Sender is not the aggregator", chainio.DecodeRevertError(errors.New("execution reverted: custom error 0x2cbe4195")), true},
+		{"Wrapped", fmt.Errorf("respond to task: %w", chainio.DecodeRevertError(errors.New("execution reverted: custom error 0x2cbe4195"))), true},
+		{"Batcher without funds", chainio.DecodeRevertError(errors.New("execution reverted: custom error 0x5c54305e")), false},
+		{"Network error", errors.New("connection refused"), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isPermanentFailure(c.err); got != c.want {
+				t.Errorf("Expected isPermanentFailure to be %v, got %v", c.want, got)
+			}
+		})
+	}
+}
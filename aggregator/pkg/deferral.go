@@ -0,0 +1,306 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	retry "github.com/yetanotherco/aligned_layer/core"
+)
+
+const (
+	// DefaultResponseDeferralMaxWait is used when `response_deferral_max_wait` is not set
+	DefaultResponseDeferralMaxWait = 30 * time.Minute
+	// DefaultResponseDeferralCheckInterval is used when `response_deferral_check_interval` is not set
+	DefaultResponseDeferralCheckInterval = 12 * time.Second
+)
+
+// Reasons a deferred response is submitted, used as metric labels
+const (
+	DeferralSubmitGasDropped = "gas_dropped"
+	DeferralSubmitMaxWait    = "max_wait"
+)
+
+// deferredResponse is an aggregated response held until the gas price drops enough for the fee limit
+// of its batch to cover the transaction
+type deferredResponse struct {
+	response AggregatedResponse
+	feeLimit *big.Int
+	gas      uint64
+	// Highest gas price at which the fee limit covers the transaction
+	maxGasPrice *big.Int
+	deferredAt  time.Time
+}
+
+func newDeferredResponse(response AggregatedResponse, feeLimit *big.Int, gas uint64, deferredAt time.Time) *deferredResponse {
+	maxGasPrice := new(big.Int)
+	if gas > 0 {
+		maxGasPrice.Div(feeLimit, new(big.Int).SetUint64(gas))
+	}
+	return &deferredResponse{
+		response:    response,
+		feeLimit:    feeLimit,
+		gas:         gas,
+		maxGasPrice: maxGasPrice,
+		deferredAt:  deferredAt,
+	}
+}
+
+// headroom returns how much of the fee limit is left after paying the transaction at gasPrice, negative if it is not enough
+func (d *deferredResponse) headroom(gasPrice *big.Int) *big.Int {
+	cost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(d.gas))
+	return cost.Sub(d.feeLimit, cost)
+}
+
+// StoredDeferredResponse is a deferred response as kept in the deferral store file,
+// with the aggregated signature so it can be submitted after a restart
+type StoredDeferredResponse struct {
+	BatchIdentifierHash         [32]byte                                                       `json:"batch_identifier_hash"`
+	BatchMerkleRoot             [32]byte                                                       `json:"batch_merkle_root"`
+	SenderAddress               [20]byte                                                       `json:"sender_address"`
+	TaskCreatedBlock            uint32                                                         `json:"task_created_block"`
+	NonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature `json:"non_signer_stakes_and_signature"`
+	FeeLimit                    *big.Int                                                       `json:"fee_limit"`
+	Gas                         uint64                                                         `json:"gas"`
+	DeferredAt                  time.Time                                                      `json:"deferred_at"`
+}
+
+func (d *deferredResponse) stored() StoredDeferredResponse {
+	return StoredDeferredResponse{
+		BatchIdentifierHash:         d.response.BatchIdentifierHash,
+		BatchMerkleRoot:             d.response.BatchMerkleRoot,
+		SenderAddress:               d.response.SenderAddress,
+		TaskCreatedBlock:            d.response.TaskCreatedBlock,
+		NonSignerStakesAndSignature: d.response.NonSignerStakesAndSignature,
+		FeeLimit:                    d.feeLimit,
+		Gas:                         d.gas,
+		DeferredAt:                  d.deferredAt,
+	}
+}
+
+func (s StoredDeferredResponse) deferredResponse() *deferredResponse {
+	feeLimit := s.FeeLimit
+	if feeLimit == nil {
+		feeLimit = new(big.Int)
+	}
+	return newDeferredResponse(AggregatedResponse{
+		BatchIdentifierHash:         s.BatchIdentifierHash,
+		BatchMerkleRoot:             s.BatchMerkleRoot,
+		SenderAddress:               s.SenderAddress,
+		TaskCreatedBlock:            s.TaskCreatedBlock,
+		NonSignerStakesAndSignature: s.NonSignerStakesAndSignature,
+	}, feeLimit, s.Gas, s.DeferredAt)
+}
+
+// deferralQueue holds the deferred responses by batch identifier hash.
+// If a store path is set, they are mirrored to a JSON file and loaded back on restart
+type deferralQueue struct {
+	responses map[[32]byte]*deferredResponse
+	maxWait   time.Duration
+	// Nil if the deferred responses are only kept in memory
+	store *jsonFileMap[StoredDeferredResponse]
+	mutex sync.Mutex
+}
+
+func newDeferralQueue(maxWait time.Duration, storePath string) (*deferralQueue, error) {
+	if maxWait == 0 {
+		maxWait = DefaultResponseDeferralMaxWait
+	}
+	queue := &deferralQueue{
+		responses: make(map[[32]byte]*deferredResponse),
+		maxWait:   maxWait,
+	}
+	if storePath == "" {
+		return queue, nil
+	}
+
+	store, err := newJsonFileMap[StoredDeferredResponse](storePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open deferral store: %w", err)
+	}
+	queue.store = store
+	for _, stored := range store.values() {
+		queue.responses[stored.BatchIdentifierHash] = stored.deferredResponse()
+	}
+	return queue, nil
+}
+
+// add holds the response, and writes it to the store if set
+func (q *deferralQueue) add(deferred *deferredResponse) error {
+	q.mutex.Lock()
+	q.responses[deferred.response.BatchIdentifierHash] = deferred
+	q.mutex.Unlock()
+	if q.store == nil {
+		return nil
+	}
+	return q.store.set(deferred.response.BatchIdentifierHash, deferred.stored())
+}
+
+func (q *deferralQueue) len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.responses)
+}
+
+// expired reports whether the response was deferred for the max wait, so it must be submitted regardless of the gas price
+func (q *deferralQueue) expired(deferred *deferredResponse, now time.Time) bool {
+	return now.Sub(deferred.deferredAt) >= q.maxWait
+}
+
+// ordered returns the deferred responses in submission order: the expired ones first, oldest first,
+// then the ones with the most fee headroom at gasPrice, older first on ties.
+// If gasPrice is nil, they are ordered by age
+func (q *deferralQueue) ordered(gasPrice *big.Int, now time.Time) []*deferredResponse {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	responses := make([]*deferredResponse, 0, len(q.responses))
+	for _, deferred := range q.responses {
+		responses = append(responses, deferred)
+	}
+	sort.Slice(responses, func(i, j int) bool {
+		expiredI, expiredJ := q.expired(responses[i], now), q.expired(responses[j], now)
+		if expiredI != expiredJ {
+			return expiredI
+		}
+		if !expiredI && gasPrice != nil {
+			if cmp := responses[i].headroom(gasPrice).Cmp(responses[j].headroom(gasPrice)); cmp != 0 {
+				return cmp > 0
+			}
+		}
+		return responses[i].deferredAt.Before(responses[j].deferredAt)
+	})
+	return responses
+}
+
+// due removes and returns the responses to submit at gasPrice, in submission order, with the reason for each.
+// If gasPrice is nil only the expired responses are due. The error is the first failure removing them from the store
+func (q *deferralQueue) due(gasPrice *big.Int, now time.Time) ([]*deferredResponse, []string, error) {
+	var due []*deferredResponse
+	var reasons []string
+	for _, deferred := range q.ordered(gasPrice, now) {
+		switch {
+		case q.expired(deferred, now):
+			reasons = append(reasons, DeferralSubmitMaxWait)
+		case gasPrice != nil && gasPrice.Cmp(deferred.maxGasPrice) <= 0:
+			reasons = append(reasons, DeferralSubmitGasDropped)
+		default:
+			continue
+		}
+		due = append(due, deferred)
+	}
+
+	q.mutex.Lock()
+	for _, deferred := range due {
+		delete(q.responses, deferred.response.BatchIdentifierHash)
+	}
+	q.mutex.Unlock()
+
+	var storeErr error
+	if q.store != nil {
+		for _, deferred := range due {
+			if err := q.store.delete(deferred.response.BatchIdentifierHash); err != nil && storeErr == nil {
+				storeErr = err
+			}
+		}
+	}
+	return due, reasons, storeErr
+}
+
+// deferResponse holds the response if the fee limit of its batch does not cover the transaction at the current gas price,
+// and reports whether it did. Responses whose cost can't be estimated are not deferred.
+// With the defer infeasible task policy and deferral disabled, only the tasks flagged as infeasible are deferred
+func (agg *Aggregator) deferResponse(response AggregatedResponse) bool {
+	if agg.deferrals == nil {
+		return false
+	}
+	if !agg.AggregatorConfig.Aggregator.ResponseDeferralEnabled && !agg.isInfeasible(response.BatchIdentifierHash) {
+		return false
+	}
+	batchIdentifierHash := response.BatchIdentifierHash
+
+	batchState, err := agg.avsWriter.BatchesStateRetryable(&bind.CallOpts{}, batchIdentifierHash, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Warn("Failed to get batch fee limit, not deferring the response", "err", err)
+		return false
+	}
+	gas, err := agg.avsWriter.EstimateRespondToTaskGas(response.BatchMerkleRoot, response.SenderAddress, response.NonSignerStakesAndSignature)
+	if err != nil {
+		agg.logger.Warn("Failed to estimate respond to task gas, not deferring the response", "err", err)
+		return false
+	}
+	gasPrice, err := agg.currentGasPrice()
+	if err != nil {
+		agg.logger.Warn("Failed to get gas price, not deferring the response", "err", err)
+		return false
+	}
+
+	deferred := newDeferredResponse(response, batchState.RespondToTaskFeeLimit, gas, time.Now())
+	if deferred.headroom(gasPrice).Sign() >= 0 {
+		return false
+	}
+	if err := agg.deferrals.add(deferred); err != nil {
+		agg.logger.Warn("Failed to store deferred response, it will be lost on restart", "err", err)
+	}
+	agg.taskInfos.deferred(batchIdentifierHash)
+	agg.metrics.SetDeferredResponses(agg.deferrals.len())
+	agg.logger.Info("Gas price is above what the batch fee limit covers, deferring the response",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+		"gasPrice", gasPrice, "maxGasPrice", deferred.maxGasPrice, "feeLimit", deferred.feeLimit)
+	return true
+}
+
+// Long-lived goroutine that periodically submits the deferred responses whose fee limit covers the current gas price,
+// and the ones deferred for longer than the max wait
+func (agg *Aggregator) ProcessDeferredResponses(ctx context.Context) {
+	if agg.deferrals == nil {
+		return
+	}
+	checkInterval := agg.AggregatorConfig.Aggregator.ResponseDeferralCheckInterval
+	if checkInterval == 0 {
+		checkInterval = DefaultResponseDeferralCheckInterval
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Only the leader sends responses, standbys hold theirs until they are elected
+			if !agg.isLeader() || agg.deferrals.len() == 0 {
+				continue
+			}
+			agg.submitDueResponses()
+		}
+	}
+}
+
+func (agg *Aggregator) submitDueResponses() {
+	gasPrice, err := agg.currentGasPrice()
+	if err != nil {
+		agg.logger.Warn("Failed to get gas price, only submitting the responses deferred for the max wait", "err", err)
+		gasPrice = nil
+	}
+
+	due, reasons, err := agg.deferrals.due(gasPrice, time.Now())
+	if err != nil {
+		agg.logger.Warn("Failed to remove submitted responses from the deferral store, they will be submitted again on restart", "err", err)
+	}
+	for i, deferred := range due {
+		batchIdentifierHash := deferred.response.BatchIdentifierHash
+		agg.logger.Info("Submitting deferred response",
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+			"reason", reasons[i], "gasPrice", gasPrice, "deferredFor", time.Since(deferred.deferredAt))
+		agg.metrics.IncDeferredResponsesSubmitted(reasons[i])
+		go agg.respondToTask(deferred.response)
+	}
+	agg.metrics.SetDeferredResponses(agg.deferrals.len())
+}
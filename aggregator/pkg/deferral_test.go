@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testDeferredResponse(id byte, feeLimit int64, gas uint64, deferredAt time.Time) *deferredResponse {
+	return newDeferredResponse(AggregatedResponse{BatchIdentifierHash: [32]byte{id}}, big.NewInt(feeLimit), gas, deferredAt)
+}
+
+func TestDeferralQueueOrder(t *testing.T) {
+	queue, _ := newDeferralQueue(time.Hour, "")
+	now := time.Now()
+	// At a gas price of 10: headroom -100, -500 and -500, the last one older. The first one expired
+	expired := testDeferredResponse(1, 0, 10, now.Add(-2*time.Hour))
+	smallHeadroom := testDeferredResponse(2, 500, 100, now.Add(-time.Minute))
+	bigHeadroom := testDeferredResponse(3, 900, 100, now)
+	olderSmallHeadroom := testDeferredResponse(4, 500, 100, now.Add(-2*time.Minute))
+	for _, deferred := range []*deferredResponse{expired, smallHeadroom, bigHeadroom, olderSmallHeadroom} {
+		queue.add(deferred)
+	}
+
+	ordered := queue.ordered(big.NewInt(10), now)
+	expected := []*deferredResponse{expired, bigHeadroom, olderSmallHeadroom, smallHeadroom}
+	for i := range expected {
+		if ordered[i] != expected[i] {
+			t.Fatalf("Unexpected order at %d: got batch %d, expected %d", i, ordered[i].response.BatchIdentifierHash[0], expected[i].response.BatchIdentifierHash[0])
+		}
+	}
+
+	// Without gas price only the age counts
+	if ordered := queue.ordered(nil, now); ordered[1] != olderSmallHeadroom || ordered[3] != bigHeadroom {
+		t.Errorf("Expected age order without gas price")
+	}
+}
+
+func TestDeferralQueueDue(t *testing.T) {
+	queue, _ := newDeferralQueue(time.Hour, "")
+	now := time.Now()
+	expired := testDeferredResponse(1, 0, 10, now.Add(-time.Hour))
+	cheap := testDeferredResponse(2, 1000, 100, now)
+	expensive := testDeferredResponse(3, 500, 100, now)
+	for _, deferred := range []*deferredResponse{expired, cheap, expensive} {
+		queue.add(deferred)
+	}
+	if cheap.maxGasPrice.Int64() != 10 {
+		t.Fatalf("Expected a max gas price of 10, got %v", cheap.maxGasPrice)
+	}
+
+	// Gas price unknown: only the expired one is due
+	due, reasons, _ := queue.due(nil, now)
+	if len(due) != 1 || due[0] != expired || reasons[0] != DeferralSubmitMaxWait {
+		t.Fatalf("Expected only the expired response, got %d responses", len(due))
+	}
+
+	due, reasons, _ = queue.due(big.NewInt(10), now)
+	if len(due) != 1 || due[0] != cheap || reasons[0] != DeferralSubmitGasDropped {
+		t.Fatalf("Expected only the response covered by its fee limit, got %d responses", len(due))
+	}
+	if queue.len() != 1 {
+		t.Errorf("Expected the expensive response to stay deferred, got %d responses", queue.len())
+	}
+}
+
+func TestDeferralQueueReloadsStoredResponses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deferred_responses.json")
+	queue, err := newDeferralQueue(time.Hour, path)
+	if err != nil {
+		t.Fatalf("Could not create deferral queue: %v", err)
+	}
+	now := time.Now()
+	deferred := testDeferredResponse(1, 1000, 100, now)
+	deferred.response.TaskCreatedBlock = 7
+	if err := queue.add(deferred); err != nil {
+		t.Fatalf("Could not store deferred response: %v", err)
+	}
+	if err := queue.add(testDeferredResponse(2, 500, 100, now)); err != nil {
+		t.Fatalf("Could not store deferred response: %v", err)
+	}
+	if _, _, err := queue.due(big.NewInt(10), now); err != nil {
+		t.Fatalf("Could not remove the due response from the store: %v", err)
+	}
+
+	// After a restart only the response still deferred is loaded back
+	reloaded, err := newDeferralQueue(time.Hour, path)
+	if err != nil {
+		t.Fatalf("Could not reload deferral queue: %v", err)
+	}
+	if reloaded.len() != 1 {
+		t.Fatalf("Expected 1 deferred response after reload, got %d", reloaded.len())
+	}
+	restored := reloaded.ordered(nil, now)[0]
+	if restored.response.BatchIdentifierHash != ([32]byte{2}) || restored.maxGasPrice.Int64() != 5 || !restored.deferredAt.Equal(now) {
+		t.Errorf("Unexpected reloaded response: batch %d, max gas price %v, deferred at %v",
+			restored.response.BatchIdentifierHash[0], restored.maxGasPrice, restored.deferredAt)
+	}
+}
@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+// What is done with the tasks flagged as infeasible, set in the infeasible_task_policy config
+const (
+	// Respond to them as to any other task
+	InfeasibleTaskPolicyAttempt = "attempt"
+	// Only send their responses when no feasible response is waiting to be sent
+	InfeasibleTaskPolicyDeprioritize = "deprioritize"
+	// Defer their responses until the gas price drops, as done for every response with response_deferral_enabled
+	InfeasibleTaskPolicyDefer = "defer"
+)
+
+const (
+	// DefaultRespondToTaskGasEstimate is used when `respond_to_task_gas_estimate` is not set.
+	// The response can't be simulated before quorum, as it needs the aggregated signature
+	DefaultRespondToTaskGasEstimate = 400_000
+	// DefaultMinFeasibilityScore is used when `min_fee_feasibility_score` is not set
+	DefaultMinFeasibilityScore = 1.0
+)
+
+// Reasons a task is infeasible, used as metric labels
+const (
+	InfeasibleReasonBatcherBalance = "batcher_balance"
+	InfeasibleReasonFeeLimit       = "fee_limit"
+)
+
+// TaskFeasibility is how well the fee limit and the balance of the batcher cover the response of a task,
+// as checked when the task was created
+type TaskFeasibility struct {
+	FeeLimit       *big.Int
+	BatcherBalance *big.Int
+	GasPrice       *big.Int
+	// Cost of the response at GasPrice, with the gas estimate
+	EstimatedCost *big.Int
+	// Fee limit over the estimated cost: below 1 the aggregator pays the difference
+	Score    float64
+	Feasible bool
+	// Why the task is infeasible, empty if it is feasible
+	Reason    string
+	CheckedAt time.Time
+}
+
+// scoreTaskFeasibility flags the task as infeasible if the batcher can't pay its fee limit, as the response would fail
+// the balance check, or if the fee limit covers less than minScore times the estimated cost
+func scoreTaskFeasibility(feeLimit *big.Int, batcherBalance *big.Int, gasPrice *big.Int, gasEstimate uint64, minScore float64) TaskFeasibility {
+	estimatedCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasEstimate))
+	feasibility := TaskFeasibility{
+		FeeLimit:       feeLimit,
+		BatcherBalance: batcherBalance,
+		GasPrice:       gasPrice,
+		EstimatedCost:  estimatedCost,
+		Score:          minScore,
+		Feasible:       true,
+		CheckedAt:      time.Now(),
+	}
+	if estimatedCost.Sign() > 0 {
+		feasibility.Score, _ = new(big.Float).Quo(new(big.Float).SetInt(feeLimit), new(big.Float).SetInt(estimatedCost)).Float64()
+	}
+
+	switch {
+	case batcherBalance.Cmp(feeLimit) < 0:
+		feasibility.Reason = InfeasibleReasonBatcherBalance
+	case feasibility.Score < minScore:
+		feasibility.Reason = InfeasibleReasonFeeLimit
+	default:
+		return feasibility
+	}
+	feasibility.Feasible = false
+	return feasibility
+}
+
+// infeasibleTaskPolicy returns the configured policy for infeasible tasks
+func infeasibleTaskPolicy(aggregatorConfig *config.AggregatorConfig) (string, error) {
+	switch aggregatorConfig.Aggregator.InfeasibleTaskPolicy {
+	case "":
+		return InfeasibleTaskPolicyAttempt, nil
+	case InfeasibleTaskPolicyAttempt, InfeasibleTaskPolicyDeprioritize, InfeasibleTaskPolicyDefer:
+		return aggregatorConfig.Aggregator.InfeasibleTaskPolicy, nil
+	default:
+		return "", fmt.Errorf("unknown infeasible task policy %q, expected %s, %s or %s", aggregatorConfig.Aggregator.InfeasibleTaskPolicy,
+			InfeasibleTaskPolicyAttempt, InfeasibleTaskPolicyDeprioritize, InfeasibleTaskPolicyDefer)
+	}
+}
+
+// checkTaskFeasibility scores the task against the current gas price, the one the deferrals use, and flags it if it is infeasible.
+// It runs when the task is created, so the operators don't have to sign a task that will fail after quorum
+func (agg *Aggregator) checkTaskFeasibility(batchIdentifierHash [32]byte, senderAddress [20]byte) {
+	batchState, err := agg.avsWriter.BatchesStateRetryable(&bind.CallOpts{}, batchIdentifierHash, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Warn("Failed to get batch fee limit, skipping feasibility check", "err", err)
+		return
+	}
+	batcherBalance, err := agg.avsWriter.BatcherBalancesRetryable(&bind.CallOpts{}, senderAddress, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Warn("Failed to get batcher balance, skipping feasibility check", "err", err)
+		return
+	}
+	gasPrice, err := agg.currentGasPrice()
+	if err != nil {
+		agg.logger.Warn("Failed to get gas price, skipping feasibility check", "err", err)
+		return
+	}
+
+	gasEstimate := agg.AggregatorConfig.Aggregator.RespondToTaskGasEstimate
+	if gasEstimate == 0 {
+		gasEstimate = DefaultRespondToTaskGasEstimate
+	}
+	minScore := agg.AggregatorConfig.Aggregator.MinFeasibilityScore
+	if minScore == 0 {
+		minScore = DefaultMinFeasibilityScore
+	}
+
+	feasibility := scoreTaskFeasibility(batchState.RespondToTaskFeeLimit, batcherBalance, gasPrice, gasEstimate, minScore)
+	agg.taskInfos.feasibilityChecked(batchIdentifierHash, feasibility)
+	if feasibility.Feasible {
+		return
+	}
+	agg.metrics.IncInfeasibleTasks(feasibility.Reason)
+	agg.logger.Warn("Task is infeasible at the current gas price",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+		"reason", feasibility.Reason, "score", feasibility.Score, "policy", agg.infeasibleTaskPolicy,
+		"feeLimit", feasibility.FeeLimit, "batcherBalance", feasibility.BatcherBalance, "estimatedCost", feasibility.EstimatedCost)
+}
+
+// isInfeasible reports whether the task was flagged as infeasible when it was created
+func (agg *Aggregator) isInfeasible(batchIdentifierHash [32]byte) bool {
+	task, ok := agg.taskInfos.get(batchIdentifierHash)
+	return ok && task.Feasibility != nil && !task.Feasibility.Feasible
+}
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestScoreTaskFeasibility(t *testing.T) {
+	// Estimated cost: 10 * 100 = 1000
+	feasibility := scoreTaskFeasibility(big.NewInt(1500), big.NewInt(2000), big.NewInt(10), 100, 1)
+	if !feasibility.Feasible || feasibility.Score != 1.5 || feasibility.EstimatedCost.Int64() != 1000 {
+		t.Errorf("Expected a feasible task with score 1.5, got %+v", feasibility)
+	}
+
+	feasibility = scoreTaskFeasibility(big.NewInt(500), big.NewInt(2000), big.NewInt(10), 100, 1)
+	if feasibility.Feasible || feasibility.Reason != InfeasibleReasonFeeLimit || feasibility.Score != 0.5 {
+		t.Errorf("Expected the fee limit to make the task infeasible, got %+v", feasibility)
+	}
+	if feasibility := scoreTaskFeasibility(big.NewInt(500), big.NewInt(2000), big.NewInt(10), 100, 0.4); !feasibility.Feasible {
+		t.Errorf("Expected the task to be feasible with a lower min score, got %+v", feasibility)
+	}
+
+	// The batcher can't pay its fee limit, the balance check would fail after quorum
+	feasibility = scoreTaskFeasibility(big.NewInt(1500), big.NewInt(1000), big.NewInt(10), 100, 1)
+	if feasibility.Feasible || feasibility.Reason != InfeasibleReasonBatcherBalance {
+		t.Errorf("Expected the batcher balance to make the task infeasible, got %+v", feasibility)
+	}
+
+	if feasibility := scoreTaskFeasibility(big.NewInt(0), big.NewInt(0), big.NewInt(0), 100, 1); !feasibility.Feasible {
+		t.Errorf("Expected a free response to be feasible, got %+v", feasibility)
+	}
+}
+
+func TestResponseSlotsPrioritizeFeasibleResponses(t *testing.T) {
+	slots := newResponseSlots(1)
+	slots.acquire(false)
+
+	acquired := make(chan string, 2)
+	go func() {
+		slots.acquire(true)
+		acquired <- "low"
+	}()
+	// Let the low priority response wait first
+	time.Sleep(20 * time.Millisecond)
+	go func() {
+		slots.acquire(false)
+		acquired <- "high"
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	slots.release()
+	if first := <-acquired; first != "high" {
+		t.Fatalf("Expected the high priority response to take the slot first, got %s", first)
+	}
+	slots.release()
+	select {
+	case second := <-acquired:
+		if second != "low" {
+			t.Errorf("Expected the low priority response, got %s", second)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("The low priority response never took the slot")
+	}
+}
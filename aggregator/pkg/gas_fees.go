@@ -0,0 +1,60 @@
+package pkg
+
+import (
+	"fmt"
+	"math/big"
+
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// Fee modes of the respond to task transactions, set in the gas_fee_mode config
+const (
+	GasFeeModeLegacy  = "legacy"
+	GasFeeModeDynamic = "dynamic"
+)
+
+const (
+	DefaultFeeHistoryBlocks      = 10
+	DefaultPriorityFeePercentile = 50
+)
+
+// newDynamicFeeParams returns the EIP-1559 fee estimation params, or nil in legacy mode
+func newDynamicFeeParams(aggregatorConfig *config.AggregatorConfig) (*utils.DynamicFeeParams, error) {
+	switch aggregatorConfig.Aggregator.GasFeeMode {
+	case "", GasFeeModeLegacy:
+		return nil, nil
+	case GasFeeModeDynamic:
+	default:
+		return nil, fmt.Errorf("unknown gas fee mode %q, expected %s or %s", aggregatorConfig.Aggregator.GasFeeMode, GasFeeModeLegacy, GasFeeModeDynamic)
+	}
+
+	params := &utils.DynamicFeeParams{
+		FeeHistoryBlocks: aggregatorConfig.Aggregator.FeeHistoryBlocks,
+		RewardPercentile: aggregatorConfig.Aggregator.PriorityFeePercentile,
+	}
+	if params.FeeHistoryBlocks == 0 {
+		params.FeeHistoryBlocks = DefaultFeeHistoryBlocks
+	}
+	if params.RewardPercentile == 0 {
+		params.RewardPercentile = DefaultPriorityFeePercentile
+	}
+	if params.RewardPercentile < 0 || params.RewardPercentile > 100 {
+		return nil, fmt.Errorf("priority_fee_percentile must be between 0 and 100, got %v", params.RewardPercentile)
+	}
+	return params, nil
+}
+
+// currentGasPrice returns the price per gas a respond to task transaction pays now: the suggested gas price in legacy
+// mode, the base fee of the next block plus the estimated priority fee in dynamic mode
+func (agg *Aggregator) currentGasPrice() (*big.Int, error) {
+	if agg.dynamicFeeParams == nil {
+		return utils.GetGasPriceRetryable(agg.avsWriter.Client, agg.avsWriter.ClientFallback, retry.NetworkRetryParams())
+	}
+	fees, err := utils.GetDynamicFeesRetryable(agg.avsWriter.Client, agg.avsWriter.ClientFallback, *agg.dynamicFeeParams, retry.NetworkRetryParams())
+	if err != nil {
+		return nil, err
+	}
+	return fees.EffectiveGasPrice(), nil
+}
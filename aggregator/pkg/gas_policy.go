@@ -0,0 +1,231 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+const (
+	// DefaultGasSpendBudgetWindow is used when `gas_spend_budget_window` is not set
+	DefaultGasSpendBudgetWindow = 24 * time.Hour
+	// DefaultGasPolicyAction is used when `gas_policy_action` is not set
+	DefaultGasPolicyAction = chainio.GasPolicyActionAlert
+	// GasPolicyRetryInterval is how often the responses deferred by the gas spend policy are evaluated again
+	GasPolicyRetryInterval = 1 * time.Minute
+	// DefaultGasPolicyMaxWait is used when `gas_policy_max_wait` is not set
+	DefaultGasPolicyMaxWait = 1 * time.Hour
+)
+
+// DeferralSubmitGasPolicy is the reason a response deferred by the gas spend policy is submitted, used as metric label
+const DeferralSubmitGasPolicy = "gas_policy_allows"
+
+// newGasPolicy returns the gas spend policy of the respond to task transactions, or nil if no limit is set
+func newGasPolicy(aggregatorConfig *config.AggregatorConfig) (*chainio.GasPolicy, error) {
+	policyConfig := chainio.GasPolicyConfig{
+		SpendBudgetWindow: aggregatorConfig.Aggregator.GasSpendBudgetWindow,
+		Action:            aggregatorConfig.Aggregator.GasPolicyAction,
+	}
+
+	switch policyConfig.Action {
+	case "":
+		policyConfig.Action = DefaultGasPolicyAction
+	case chainio.GasPolicyActionDefer, chainio.GasPolicyActionAlert, chainio.GasPolicyActionSend:
+	default:
+		return nil, fmt.Errorf("unknown gas policy action %q, expected %s, %s or %s", policyConfig.Action,
+			chainio.GasPolicyActionDefer, chainio.GasPolicyActionAlert, chainio.GasPolicyActionSend)
+	}
+	if policyConfig.SpendBudgetWindow == 0 {
+		policyConfig.SpendBudgetWindow = DefaultGasSpendBudgetWindow
+	}
+
+	if aggregatorConfig.Aggregator.MaxGasPriceGwei > 0 {
+		policyConfig.MaxGasPrice = utils.EthToWei(aggregatorConfig.Aggregator.MaxGasPriceGwei / 1e9)
+	}
+	if aggregatorConfig.Aggregator.MaxOverpaymentPerBatch > 0 {
+		policyConfig.MaxOverpaymentPerBatch = utils.EthToWei(aggregatorConfig.Aggregator.MaxOverpaymentPerBatch)
+	}
+	if aggregatorConfig.Aggregator.GasSpendBudget > 0 {
+		policyConfig.SpendBudget = utils.EthToWei(aggregatorConfig.Aggregator.GasSpendBudget)
+	}
+	if policyConfig.MaxGasPrice == nil && policyConfig.MaxOverpaymentPerBatch == nil && policyConfig.SpendBudget == nil {
+		return nil, nil
+	}
+	return chainio.NewGasPolicy(policyConfig), nil
+}
+
+// gasPolicyDeferrals holds the responses deferred by the gas spend policy, by batch identifier hash,
+// until the policy allows sending them or they were held for the max wait.
+// If a store path is set, they are mirrored to a JSON file and loaded back on restart
+type gasPolicyDeferrals struct {
+	responses map[[32]byte]*deferredResponse
+	maxWait   time.Duration
+	// Nil if the deferred responses are only kept in memory
+	store *jsonFileMap[StoredDeferredResponse]
+	mutex sync.Mutex
+}
+
+func newGasPolicyDeferrals(maxWait time.Duration, storePath string) (*gasPolicyDeferrals, error) {
+	if maxWait == 0 {
+		maxWait = DefaultGasPolicyMaxWait
+	}
+	deferrals := &gasPolicyDeferrals{
+		responses: make(map[[32]byte]*deferredResponse),
+		maxWait:   maxWait,
+	}
+	if storePath == "" {
+		return deferrals, nil
+	}
+
+	store, err := newJsonFileMap[StoredDeferredResponse](storePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gas policy deferral store: %w", err)
+	}
+	deferrals.store = store
+	for _, stored := range store.values() {
+		deferred := stored.deferredResponse()
+		// An unknown fee limit is kept unknown, so the overpayment limit is not checked against it
+		if stored.FeeLimit == nil {
+			deferred.feeLimit = nil
+		}
+		deferrals.responses[stored.BatchIdentifierHash] = deferred
+	}
+	return deferrals, nil
+}
+
+// add holds the response, and writes it to the store if set
+func (d *gasPolicyDeferrals) add(deferred *deferredResponse) error {
+	d.mutex.Lock()
+	d.responses[deferred.response.BatchIdentifierHash] = deferred
+	d.mutex.Unlock()
+	if d.store == nil {
+		return nil
+	}
+	return d.store.set(deferred.response.BatchIdentifierHash, deferred.stored())
+}
+
+func (d *gasPolicyDeferrals) len() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return len(d.responses)
+}
+
+// due removes and returns the responses to submit, oldest first, with the reason for each: the ones held for the max wait,
+// and the ones the policy lets through at gasPrice. Responses whose gas could not be estimated are always due,
+// SendAggregatedResponse evaluates them again. If gasPrice is nil only the expired responses are due.
+// The error is the first failure removing them from the store
+func (d *gasPolicyDeferrals) due(policy *chainio.GasPolicy, gasPrice *big.Int, now time.Time) ([]*deferredResponse, []string, error) {
+	d.mutex.Lock()
+	var due []*deferredResponse
+	for batchIdentifierHash, deferred := range d.responses {
+		expired := now.Sub(deferred.deferredAt) >= d.maxWait
+		if !expired && (gasPrice == nil || deferred.gas > 0 && policy.Evaluate(gasPrice, deferred.gas, deferred.feeLimit, now).Limit != chainio.GasPolicyLimitNone) {
+			continue
+		}
+		due = append(due, deferred)
+		delete(d.responses, batchIdentifierHash)
+	}
+	d.mutex.Unlock()
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].deferredAt.Before(due[j].deferredAt)
+	})
+	reasons := make([]string, len(due))
+	for i, deferred := range due {
+		reasons[i] = DeferralSubmitGasPolicy
+		if now.Sub(deferred.deferredAt) >= d.maxWait {
+			reasons[i] = DeferralSubmitMaxWait
+		}
+	}
+
+	var storeErr error
+	if d.store != nil {
+		for _, deferred := range due {
+			if err := d.store.delete(deferred.response.BatchIdentifierHash); err != nil && storeErr == nil {
+				storeErr = err
+			}
+		}
+	}
+	return due, reasons, storeErr
+}
+
+// holdGasPolicyDeferredResponse keeps a response the gas spend policy deferred, with its fee limit and gas
+// so the policy can be evaluated again without simulating the transaction
+func (agg *Aggregator) holdGasPolicyDeferredResponse(response AggregatedResponse, deferErr error) {
+	batchIdentifierHash := response.BatchIdentifierHash
+	deferred := &deferredResponse{response: response, deferredAt: time.Now()}
+	if batchState, err := agg.avsWriter.BatchesStateRetryable(&bind.CallOpts{}, batchIdentifierHash, retry.NetworkRetryParams()); err == nil {
+		deferred.feeLimit = batchState.RespondToTaskFeeLimit
+	}
+	if gas, err := agg.avsWriter.EstimateRespondToTaskGas(response.BatchMerkleRoot, response.SenderAddress, response.NonSignerStakesAndSignature); err == nil {
+		deferred.gas = gas
+	}
+
+	if err := agg.gasPolicyDeferrals.add(deferred); err != nil {
+		agg.logger.Warn("Failed to store the response deferred by the gas spend policy, it will be lost on restart", "err", err)
+	}
+	agg.taskInfos.deferred(batchIdentifierHash)
+	agg.logger.Info("Gas spend policy deferred the response, it will be sent once the policy allows it or after the max wait",
+		"reason", deferErr,
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]))
+}
+
+// Long-lived goroutine that periodically evaluates the responses deferred by the gas spend policy and submits the ones
+// it allows, as the gas price drops or the spend budget window moves on, and the ones deferred for longer than the max wait
+func (agg *Aggregator) ProcessGasPolicyDeferredResponses(ctx context.Context) {
+	if agg.gasPolicyDeferrals == nil {
+		return
+	}
+	ticker := time.NewTicker(GasPolicyRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Only the leader sends responses, standbys hold theirs until they are elected
+			if !agg.isLeader() || agg.gasPolicyDeferrals.len() == 0 {
+				continue
+			}
+			agg.submitGasPolicyDueResponses()
+		}
+	}
+}
+
+func (agg *Aggregator) submitGasPolicyDueResponses() {
+	// Evaluated at the price SendAggregatedResponse evaluates the transaction at, so an allowed response is not deferred again
+	gasPrice, err := agg.avsWriter.GasPolicyPrice(agg.AggregatorConfig.Aggregator.GasBaseBumpPercentage,
+		agg.AggregatorConfig.Aggregator.GasBumpIncrementalPercentage, agg.AggregatorConfig.Aggregator.GasBumpPercentageLimit, agg.dynamicFeeParams)
+	if err != nil {
+		agg.logger.Warn("Failed to get gas price, only submitting the responses deferred by the gas spend policy for the max wait", "err", err)
+		gasPrice = nil
+	}
+
+	due, reasons, err := agg.gasPolicyDeferrals.due(agg.avsWriter.GasPolicy, gasPrice, time.Now())
+	if err != nil {
+		agg.logger.Warn("Failed to remove submitted responses from the gas policy deferral store, they will be submitted again on restart", "err", err)
+	}
+	for i, deferred := range due {
+		batchIdentifierHash := deferred.response.BatchIdentifierHash
+		if reasons[i] == DeferralSubmitMaxWait {
+			agg.avsWriter.GasPolicy.Force(batchIdentifierHash)
+		}
+		agg.logger.Info("Submitting response deferred by the gas spend policy",
+			"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+			"reason", reasons[i], "gasPrice", gasPrice, "deferredFor", time.Since(deferred.deferredAt))
+		agg.metrics.IncDeferredResponsesSubmitted(reasons[i])
+		// If the policy defers it again, respondToTask holds it back here
+		go agg.respondToTask(deferred.response)
+	}
+}
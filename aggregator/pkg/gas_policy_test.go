@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+func TestGasPolicyDeferralsDue(t *testing.T) {
+	policy := chainio.NewGasPolicy(chainio.GasPolicyConfig{
+		MaxGasPrice:       big.NewInt(100),
+		SpendBudget:       big.NewInt(10_000),
+		SpendBudgetWindow: time.Hour,
+		Action:            chainio.GasPolicyActionDefer,
+	})
+	now := time.Now()
+	deferrals, err := newGasPolicyDeferrals(3*time.Hour, "")
+	if err != nil {
+		t.Fatalf("Could not create gas policy deferrals: %v", err)
+	}
+	cheap := &deferredResponse{response: AggregatedResponse{BatchIdentifierHash: [32]byte{1}}, gas: 10, deferredAt: now.Add(-time.Minute)}
+	expensive := &deferredResponse{response: AggregatedResponse{BatchIdentifierHash: [32]byte{2}}, gas: 1_000, deferredAt: now}
+	unknownGas := &deferredResponse{response: AggregatedResponse{BatchIdentifierHash: [32]byte{3}}, deferredAt: now.Add(-2 * time.Minute)}
+	for _, deferred := range []*deferredResponse{cheap, expensive, unknownGas} {
+		if err := deferrals.add(deferred); err != nil {
+			t.Fatalf("Could not add deferred response: %v", err)
+		}
+	}
+
+	// Above the max gas price only the response with unknown gas is let through, SendAggregatedResponse evaluates it
+	if due, _, _ := deferrals.due(policy, big.NewInt(200), now); len(due) != 1 || due[0] != unknownGas {
+		t.Fatalf("Expected only the response with unknown gas to be due, got %d", len(due))
+	}
+
+	// The budget is spent within the window, the expensive response stays deferred
+	policy.RecordSpend(big.NewInt(5_000), now)
+	if due, _, _ := deferrals.due(policy, big.NewInt(10), now); len(due) != 1 || due[0] != cheap {
+		t.Fatalf("Expected only the cheap response to be due, got %d", len(due))
+	}
+	if deferrals.len() != 1 {
+		t.Fatalf("Expected the expensive response to stay deferred")
+	}
+
+	// Once the spend leaves the window it is allowed
+	if due, reasons, _ := deferrals.due(policy, big.NewInt(10), now.Add(2*time.Hour)); len(due) != 1 || due[0] != expensive || reasons[0] != DeferralSubmitGasPolicy {
+		t.Fatalf("Expected the expensive response to be allowed once the window moved on")
+	}
+	if deferrals.len() != 0 {
+		t.Errorf("Expected no deferred responses left")
+	}
+}
+
+func TestGasPolicyDeferralsMaxWaitAndStore(t *testing.T) {
+	policy := chainio.NewGasPolicy(chainio.GasPolicyConfig{MaxGasPrice: big.NewInt(100), Action: chainio.GasPolicyActionDefer})
+	storePath := filepath.Join(t.TempDir(), "gas_policy_deferrals.json")
+	now := time.Now()
+	deferrals, err := newGasPolicyDeferrals(time.Hour, storePath)
+	if err != nil {
+		t.Fatalf("Could not create gas policy deferrals: %v", err)
+	}
+	if err := deferrals.add(&deferredResponse{response: AggregatedResponse{BatchIdentifierHash: [32]byte{1}}, gas: 10, deferredAt: now}); err != nil {
+		t.Fatalf("Could not add deferred response: %v", err)
+	}
+
+	// The response survives a restart, with its fee limit still unknown
+	deferrals, err = newGasPolicyDeferrals(time.Hour, storePath)
+	if err != nil {
+		t.Fatalf("Could not load gas policy deferrals: %v", err)
+	}
+	if deferrals.len() != 1 || deferrals.responses[[32]byte{1}].feeLimit != nil {
+		t.Fatalf("Expected the deferred response to be loaded with an unknown fee limit")
+	}
+
+	// Held at a price the policy rejects, and without a price, until the max wait
+	if due, _, _ := deferrals.due(policy, big.NewInt(200), now.Add(time.Minute)); len(due) != 0 {
+		t.Fatalf("Expected the response to stay deferred before the max wait")
+	}
+	due, reasons, err := deferrals.due(policy, nil, now.Add(time.Hour))
+	if err != nil || len(due) != 1 || reasons[0] != DeferralSubmitMaxWait {
+		t.Fatalf("Expected the response to be due after the max wait, got %d: %v", len(due), err)
+	}
+
+	deferrals, err = newGasPolicyDeferrals(time.Hour, storePath)
+	if err != nil || deferrals.len() != 0 {
+		t.Errorf("Expected the submitted response to be removed from the store")
+	}
+}
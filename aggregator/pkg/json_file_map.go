@@ -0,0 +1,158 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// jsonFileMap is a map keyed by a 32 bytes hash that is mirrored to a JSON file on every change.
+// The file is written to a temporary path and then renamed, so a crash never leaves a half written file.
+// It backs the file implementations of the aggregator stores.
+// A map is used either through set and delete, which write the file before returning, or through
+// setDeferred and deleteDeferred, which leave the write to flushIfDirty
+type jsonFileMap[T any] struct {
+	path    string
+	entries map[string]T
+	mutex   sync.Mutex
+	// Entries changed since the last write, only used by the deferred methods
+	dirty bool
+	// Serializes the deferred writes, so an older snapshot never replaces a newer one
+	flushMutex sync.Mutex
+}
+
+func newJsonFileMap[T any](path string) (*jsonFileMap[T], error) {
+	// check if the directory exist, the file itself is created on the first write
+	folderPath := filepath.Dir(path)
+	if _, err := os.Stat(folderPath); err != nil {
+		return nil, fmt.Errorf("directory %s is not accessible: %w", folderPath, err)
+	}
+
+	fileMap := &jsonFileMap[T]{
+		path:    path,
+		entries: make(map[string]T),
+	}
+
+	file, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fileMap, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(file) == 0 {
+		return fileMap, nil
+	}
+
+	if err := json.Unmarshal(file, &fileMap.entries); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	return fileMap, nil
+}
+
+func (m *jsonFileMap[T]) set(key [32]byte, value T) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.entries[hex.EncodeToString(key[:])] = value
+	return m.flush()
+}
+
+func (m *jsonFileMap[T]) get(key [32]byte) (T, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	value, ok := m.entries[hex.EncodeToString(key[:])]
+	return value, ok
+}
+
+func (m *jsonFileMap[T]) delete(key [32]byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	encodedKey := hex.EncodeToString(key[:])
+	if _, ok := m.entries[encodedKey]; !ok {
+		return nil
+	}
+	delete(m.entries, encodedKey)
+	return m.flush()
+}
+
+func (m *jsonFileMap[T]) values() []T {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	values := make([]T, 0, len(m.entries))
+	for _, value := range m.entries {
+		values = append(values, value)
+	}
+	return values
+}
+
+// setDeferred is set without writing the file, see flushIfDirty
+func (m *jsonFileMap[T]) setDeferred(key [32]byte, value T) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.entries[hex.EncodeToString(key[:])] = value
+	m.dirty = true
+}
+
+// deleteDeferred is delete without writing the file, see flushIfDirty
+func (m *jsonFileMap[T]) deleteDeferred(key [32]byte) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	encodedKey := hex.EncodeToString(key[:])
+	if _, ok := m.entries[encodedKey]; !ok {
+		return
+	}
+	delete(m.entries, encodedKey)
+	m.dirty = true
+}
+
+// flushIfDirty writes the file if entries changed since the last write.
+// The entries are copied under the mutex, and encoded and written outside it, so callers are not blocked by the disk
+func (m *jsonFileMap[T]) flushIfDirty() error {
+	m.flushMutex.Lock()
+	defer m.flushMutex.Unlock()
+
+	m.mutex.Lock()
+	if !m.dirty {
+		m.mutex.Unlock()
+		return nil
+	}
+	snapshot := make(map[string]T, len(m.entries))
+	for key, value := range m.entries {
+		snapshot[key] = value
+	}
+	m.dirty = false
+	m.mutex.Unlock()
+
+	if err := m.write(snapshot); err != nil {
+		m.mutex.Lock()
+		m.dirty = true
+		m.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// flush must be called with the mutex held
+func (m *jsonFileMap[T]) flush() error {
+	return m.write(m.entries)
+}
+
+func (m *jsonFileMap[T]) write(entries map[string]T) error {
+	encoded, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", m.path, err)
+	}
+
+	tmpPath := m.path + ".tmp"
+	if err := os.WriteFile(tmpPath, encoded, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, m.path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", m.path, err)
+	}
+	return nil
+}
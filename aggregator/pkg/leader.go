@@ -0,0 +1,318 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+const (
+	DefaultLeaderLeaseDuration = 10 * time.Second
+	LeaderLockBackendFile      = "file"
+	LeaderLockBackendMemory    = "memory"
+)
+
+// LeaderLock is the backend used to elect the aggregator instance that submits responses onchain.
+// A lease is held by a single instance until it expires or is released.
+// Implementations must make TryAcquire atomic across every instance sharing the lock
+type LeaderLock interface {
+	// TryAcquire acquires the lease for instanceId if it is free or expired, or renews it if instanceId holds it.
+	// Returns true if instanceId holds the lease after the call
+	TryAcquire(instanceId string, leaseDuration time.Duration) (bool, error)
+	// Release frees the lease if instanceId holds it
+	Release(instanceId string) error
+}
+
+type leaderLease struct {
+	Holder    string    `json:"holder"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// InMemoryLeaderLock is a LeaderLock shared by instances living in the same process. Used in tests
+type InMemoryLeaderLock struct {
+	lease leaderLease
+	mutex sync.Mutex
+}
+
+func NewInMemoryLeaderLock() *InMemoryLeaderLock {
+	return &InMemoryLeaderLock{}
+}
+
+func (l *InMemoryLeaderLock) TryAcquire(instanceId string, leaseDuration time.Duration) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	if l.lease.Holder != instanceId && l.lease.Holder != "" && now.Before(l.lease.ExpiresAt) {
+		return false, nil
+	}
+	l.lease = leaderLease{Holder: instanceId, ExpiresAt: now.Add(leaseDuration)}
+	return true, nil
+}
+
+func (l *InMemoryLeaderLock) Release(instanceId string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.lease.Holder == instanceId {
+		l.lease = leaderLease{}
+	}
+	return nil
+}
+
+// FileLeaderLock is a LeaderLock backed by a lease file, for instances sharing a filesystem.
+// Read-modify-write of the lease is serialized with flock on a sibling `.lock` file
+type FileLeaderLock struct {
+	path string
+}
+
+func NewFileLeaderLock(path string) (*FileLeaderLock, error) {
+	folderPath := filepath.Dir(path)
+	if _, err := os.Stat(folderPath); err != nil {
+		return nil, fmt.Errorf("leader lock directory %s is not accessible: %w", folderPath, err)
+	}
+	return &FileLeaderLock{path: path}, nil
+}
+
+func (l *FileLeaderLock) TryAcquire(instanceId string, leaseDuration time.Duration) (bool, error) {
+	acquired := false
+	err := l.withFileLock(func(lease leaderLease) (*leaderLease, error) {
+		now := time.Now()
+		if lease.Holder != instanceId && lease.Holder != "" && now.Before(lease.ExpiresAt) {
+			return nil, nil
+		}
+		acquired = true
+		return &leaderLease{Holder: instanceId, ExpiresAt: now.Add(leaseDuration)}, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return acquired, nil
+}
+
+func (l *FileLeaderLock) Release(instanceId string) error {
+	return l.withFileLock(func(lease leaderLease) (*leaderLease, error) {
+		if lease.Holder != instanceId {
+			return nil, nil
+		}
+		return &leaderLease{}, nil
+	})
+}
+
+// withFileLock reads the lease while holding the flock and writes back the lease returned by update, if any
+func (l *FileLeaderLock) withFileLock(update func(lease leaderLease) (*leaderLease, error)) error {
+	lockFile, err := os.OpenFile(l.path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open leader lock file: %w", err)
+	}
+	defer lockFile.Close()
+
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to lock leader lock file: %w", err)
+	}
+	defer func() {
+		_ = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+	}()
+
+	var lease leaderLease
+	content, err := os.ReadFile(l.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read leader lease: %w", err)
+	}
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &lease); err != nil {
+			return fmt.Errorf("failed to decode leader lease: %w", err)
+		}
+	}
+
+	newLease, err := update(lease)
+	if err != nil || newLease == nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(newLease)
+	if err != nil {
+		return fmt.Errorf("failed to encode leader lease: %w", err)
+	}
+	tmpPath := l.path + ".tmp"
+	if err := os.WriteFile(tmpPath, encoded, 0o600); err != nil {
+		return fmt.Errorf("failed to write leader lease: %w", err)
+	}
+	return os.Rename(tmpPath, l.path)
+}
+
+// leaderElector keeps trying to hold the leader lease, and reports transitions through onElected and onDemoted.
+// When the lease can't be renewed, the instance steps down once its last lease would have expired,
+// so two instances never consider themselves leaders at the same time
+type leaderElector struct {
+	lock          LeaderLock
+	instanceId    string
+	leaseDuration time.Duration
+	isLeader      atomic.Bool
+	onElected     func()
+	onDemoted     func()
+	logger        logging.Logger
+}
+
+func newLeaderElector(lock LeaderLock, instanceId string, leaseDuration time.Duration, logger logging.Logger) *leaderElector {
+	if leaseDuration == 0 {
+		leaseDuration = DefaultLeaderLeaseDuration
+	}
+	return &leaderElector{
+		lock:          lock,
+		instanceId:    instanceId,
+		leaseDuration: leaseDuration,
+		onElected:     func() {},
+		onDemoted:     func() {},
+		logger:        logger,
+	}
+}
+
+func (e *leaderElector) IsLeader() bool {
+	return e.isLeader.Load()
+}
+
+// Run renews the lease every third of the lease duration until ctx is done, then releases it
+func (e *leaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.leaseDuration / 3)
+	defer ticker.Stop()
+
+	var leaseExpiresAt time.Time
+	for {
+		acquireStartedAt := time.Now()
+		acquired, err := e.lock.TryAcquire(e.instanceId, e.leaseDuration)
+		switch {
+		case err != nil:
+			e.logger.Warn("Failed to acquire leader lease", "instanceId", e.instanceId, "err", err)
+			if e.IsLeader() && time.Now().After(leaseExpiresAt) {
+				e.demote()
+			}
+		case acquired:
+			// The lease is counted from before the call, to stay on the safe side
+			leaseExpiresAt = acquireStartedAt.Add(e.leaseDuration)
+			if !e.IsLeader() {
+				e.isLeader.Store(true)
+				e.logger.Info("Aggregator instance elected as leader", "instanceId", e.instanceId)
+				e.onElected()
+			}
+		default:
+			if e.IsLeader() {
+				e.demote()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if e.IsLeader() {
+				e.isLeader.Store(false)
+				if err := e.lock.Release(e.instanceId); err != nil {
+					e.logger.Warn("Failed to release leader lease", "instanceId", e.instanceId, "err", err)
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *leaderElector) demote() {
+	e.isLeader.Store(false)
+	e.logger.Warn("Aggregator instance is no longer the leader", "instanceId", e.instanceId)
+	e.onDemoted()
+}
+
+var errNotLeader = errors.New("aggregator instance is not the leader")
+
+// AggregatedResponse is a task response ready to be sent onchain
+type AggregatedResponse struct {
+	BatchIdentifierHash         [32]byte
+	BatchMerkleRoot             [32]byte
+	SenderAddress               [20]byte
+	TaskCreatedBlock            uint32
+	NonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature
+}
+
+// newLeaderLock builds the lock backend configured in `ha_lock_backend`, defaulting to a lock file.
+// The in-memory lock is refused: each process would hold its own, so every instance would elect itself
+// and send the responses. It is only built directly by tests
+func newLeaderLock(aggregatorConfig config.AggregatorConfig) (LeaderLock, error) {
+	switch aggregatorConfig.Aggregator.HaLockBackend {
+	case "", LeaderLockBackendFile:
+		if aggregatorConfig.Aggregator.HaLockFilePath == "" {
+			return nil, fmt.Errorf("ha_lock_file_path is required by the %s leader lock backend", LeaderLockBackendFile)
+		}
+		return NewFileLeaderLock(aggregatorConfig.Aggregator.HaLockFilePath)
+	case LeaderLockBackendMemory:
+		return nil, fmt.Errorf("the %s leader lock backend is not shared between instances, use the %s backend",
+			LeaderLockBackendMemory, LeaderLockBackendFile)
+	default:
+		return nil, fmt.Errorf("unknown leader lock backend %s", aggregatorConfig.Aggregator.HaLockBackend)
+	}
+}
+
+func defaultInstanceId() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "aggregator"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// isLeader reports if this instance sends responses onchain. Always true when HA is disabled
+func (agg *Aggregator) isLeader() bool {
+	return agg.leaderElector == nil || agg.leaderElector.IsLeader()
+}
+
+// holdStandbyResponse keeps a response aggregated while in standby, to be sent if this instance is elected
+func (agg *Aggregator) holdStandbyResponse(response AggregatedResponse) {
+	agg.standbyResponsesMutex.Lock()
+	agg.standbyResponses[response.BatchIdentifierHash] = response
+	agg.standbyResponsesMutex.Unlock()
+	agg.logger.Info("Aggregator instance is a standby, holding aggregated response",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(response.BatchIdentifierHash[:]))
+}
+
+// onElectedLeader sends the responses held while in standby that the previous leader did not send
+func (agg *Aggregator) onElectedLeader() {
+	agg.metrics.SetIsLeader(true)
+	agg.metrics.IncLeaderTakeovers()
+
+	agg.standbyResponsesMutex.Lock()
+	responses := make([]AggregatedResponse, 0, len(agg.standbyResponses))
+	for _, response := range agg.standbyResponses {
+		responses = append(responses, response)
+	}
+	agg.standbyResponses = make(map[[32]byte]AggregatedResponse)
+	agg.standbyResponsesMutex.Unlock()
+
+	agg.logger.Info("Taking over pending responses", "count", len(responses))
+	for _, response := range responses {
+		go func(response AggregatedResponse) {
+			batchState, err := agg.avsWriter.BatchesStateRetryable(&bind.CallOpts{}, response.BatchIdentifierHash, retry.NetworkRetryParams())
+			if err == nil && batchState.Responded {
+				agg.taskInfos.responded(response.BatchIdentifierHash, "Unknown", "Unknown")
+				if err := agg.taskStore.DeleteTask(response.BatchIdentifierHash); err != nil {
+					agg.logger.Warn("Failed to remove responded task from task store", "err", err)
+				}
+				return
+			}
+			agg.respondToTask(response)
+		}(response)
+	}
+}
+
+func (agg *Aggregator) onDemotedLeader() {
+	agg.metrics.SetIsLeader(false)
+}
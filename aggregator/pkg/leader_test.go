@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestStandbyTakesOverWhenLeaderStops(t *testing.T) {
+	logger := logging.NewTextSLogger(io.Discard, nil)
+	lock := NewInMemoryLeaderLock()
+	leaseDuration := 300 * time.Millisecond
+
+	leader := newLeaderElector(lock, "leader", leaseDuration, logger)
+	leaderCtx, stopLeader := context.WithCancel(context.Background())
+	defer stopLeader()
+	go leader.Run(leaderCtx)
+	if !waitFor(t, time.Second, leader.IsLeader) {
+		t.Fatalf("First instance was not elected")
+	}
+
+	elected := make(chan struct{}, 1)
+	standby := newLeaderElector(lock, "standby", leaseDuration, logger)
+	standby.onElected = func() { elected <- struct{}{} }
+	standbyCtx, stopStandby := context.WithCancel(context.Background())
+	defer stopStandby()
+	go standby.Run(standbyCtx)
+
+	time.Sleep(2 * leaseDuration)
+	if standby.IsLeader() {
+		t.Fatalf("Standby was elected while the leader holds the lease")
+	}
+
+	stopLeader()
+	select {
+	case <-elected:
+	case <-time.After(2 * leaseDuration):
+		t.Fatalf("Standby did not take over after the leader stopped")
+	}
+	if leader.IsLeader() {
+		t.Errorf("Stopped instance still considers itself the leader")
+	}
+}
+
+func TestFileLeaderLockIsExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.json")
+	first, err := NewFileLeaderLock(path)
+	if err != nil {
+		t.Fatalf("Could not create leader lock: %v", err)
+	}
+	second, err := NewFileLeaderLock(path)
+	if err != nil {
+		t.Fatalf("Could not create leader lock: %v", err)
+	}
+
+	if acquired, err := first.TryAcquire("first", time.Minute); err != nil || !acquired {
+		t.Fatalf("First instance could not acquire a free lease: %v", err)
+	}
+	if acquired, err := second.TryAcquire("second", time.Minute); err != nil || acquired {
+		t.Fatalf("Second instance acquired a held lease: %v", err)
+	}
+	if acquired, err := first.TryAcquire("first", time.Minute); err != nil || !acquired {
+		t.Fatalf("First instance could not renew its lease: %v", err)
+	}
+	if err := first.Release("first"); err != nil {
+		t.Fatalf("Could not release lease: %v", err)
+	}
+	if acquired, err := second.TryAcquire("second", time.Minute); err != nil || !acquired {
+		t.Fatalf("Second instance could not acquire a released lease: %v", err)
+	}
+}
+
+func TestNewLeaderLockRefusesMemoryBackend(t *testing.T) {
+	var aggregatorConfig config.AggregatorConfig
+	aggregatorConfig.Aggregator.HaEnabled = true
+	aggregatorConfig.Aggregator.HaLockBackend = LeaderLockBackendMemory
+	if _, err := newLeaderLock(aggregatorConfig); err == nil {
+		t.Errorf("Expected the memory backend to be refused, every instance would elect itself")
+	}
+
+	aggregatorConfig.Aggregator.HaLockBackend = LeaderLockBackendFile
+	aggregatorConfig.Aggregator.HaLockFilePath = filepath.Join(t.TempDir(), "leader.json")
+	if _, err := newLeaderLock(aggregatorConfig); err != nil {
+		t.Errorf("Expected the file backend to be built, got %v", err)
+	}
+}
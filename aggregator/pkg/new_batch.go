@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+// Versions of the NewBatch event a task is created from, used as metric labels.
+// Both are answered with respondToTaskV2, so the contract can be upgraded while the aggregator is running
+const (
+	NewBatchVersionV2 = "v2"
+	NewBatchVersionV3 = "v3"
+)
+
+// NewBatchTask is a task created from either version of the NewBatch event
+type NewBatchTask struct {
+	Version          string
+	BatchMerkleRoot  [32]byte
+	SenderAddress    [20]byte
+	TaskCreatedBlock uint32
+	BatchDataPointer string
+	// Fee limit emitted with the batch, nil for V2 batches as their event does not include it.
+	// The fee limit charged on the response is always read from the batch state
+	RespondToTaskFeeLimit *big.Int
+}
+
+func newBatchTaskFromV2(newBatch *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2) NewBatchTask {
+	return NewBatchTask{
+		Version:          NewBatchVersionV2,
+		BatchMerkleRoot:  newBatch.BatchMerkleRoot,
+		SenderAddress:    newBatch.SenderAddress,
+		TaskCreatedBlock: newBatch.TaskCreatedBlock,
+		BatchDataPointer: newBatch.BatchDataPointer,
+	}
+}
+
+func newBatchTaskFromV3(newBatch *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3) NewBatchTask {
+	return NewBatchTask{
+		Version:               NewBatchVersionV3,
+		BatchMerkleRoot:       newBatch.BatchMerkleRoot,
+		SenderAddress:         newBatch.SenderAddress,
+		TaskCreatedBlock:      newBatch.TaskCreatedBlock,
+		BatchDataPointer:      newBatch.BatchDataPointer,
+		RespondToTaskFeeLimit: newBatch.RespondToTaskFeeLimit,
+	}
+}
+
+// BatchIdentifierHash returns the hash of the merkle root and the sender address, which identifies the task
+func (t NewBatchTask) BatchIdentifierHash() [32]byte {
+	batchIdentifier := append(t.BatchMerkleRoot[:], t.SenderAddress[:]...)
+	return *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+}
@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+func TestNewBatchTaskFromBothVersions(t *testing.T) {
+	merkleRoot, sender := [32]byte{1}, [20]byte{2}
+	taskV2 := newBatchTaskFromV2(&servicemanager.ContractAlignedLayerServiceManagerNewBatchV2{
+		BatchMerkleRoot:  merkleRoot,
+		SenderAddress:    sender,
+		TaskCreatedBlock: 10,
+		BatchDataPointer: "https://storage/v2.json",
+	})
+	taskV3 := newBatchTaskFromV3(&servicemanager.ContractAlignedLayerServiceManagerNewBatchV3{
+		BatchMerkleRoot:       merkleRoot,
+		SenderAddress:         sender,
+		TaskCreatedBlock:      10,
+		BatchDataPointer:      "https://storage/v3.json",
+		RespondToTaskFeeLimit: big.NewInt(1000),
+	})
+
+	if taskV2.Version != NewBatchVersionV2 || taskV2.RespondToTaskFeeLimit != nil || taskV2.BatchDataPointer != "https://storage/v2.json" {
+		t.Errorf("Expected a V2 task without fee limit, got %+v", taskV2)
+	}
+	if taskV3.Version != NewBatchVersionV3 || taskV3.RespondToTaskFeeLimit.Int64() != 1000 || taskV3.BatchDataPointer != "https://storage/v3.json" {
+		t.Errorf("Expected a V3 task with its fee limit, got %+v", taskV3)
+	}
+	// Both versions are answered the same way, so the same batch must map to the same task
+	if taskV2.BatchIdentifierHash() != taskV3.BatchIdentifierHash() {
+		t.Errorf("Expected both versions to have the same batch identifier hash")
+	}
+}
+
+func TestStoredTaskWithoutVersionIsV3(t *testing.T) {
+	stored := StoredTask{BatchMerkleRoot: [32]byte{1}, TaskCreatedBlock: 10}
+	if task := stored.newBatchTask(); task.Version != NewBatchVersionV3 || task.TaskCreatedBlock != 10 {
+		t.Errorf("Expected a task stored before versions were tracked to be V3, got %+v", task)
+	}
+
+	stored.Version = NewBatchVersionV2
+	if task := stored.newBatchTask(); task.Version != NewBatchVersionV2 {
+		t.Errorf("Expected the stored version to be kept, got %+v", task)
+	}
+}
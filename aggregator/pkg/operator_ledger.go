@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+// OperatorStats is the performance record of an operator, as seen by the aggregator since it started
+type OperatorStats struct {
+	OperatorId        eigentypes.OperatorId
+	TasksSigned       uint64
+	TasksMissed       uint64
+	InvalidSignatures uint64
+	// Time between the task creation and the operator signature being accepted
+	LastLatency  time.Duration
+	TotalLatency time.Duration
+	LastSeen     time.Time
+}
+
+func (s *OperatorStats) AverageLatency() time.Duration {
+	if s.TasksSigned == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.TasksSigned)
+}
+
+// ResponseRate is the ratio of tasks signed over the tasks the operator was expected to sign
+func (s *OperatorStats) ResponseRate() float64 {
+	expected := s.TasksSigned + s.TasksMissed
+	if expected == 0 {
+		return 0
+	}
+	return float64(s.TasksSigned) / float64(expected)
+}
+
+// operatorLedger keeps the OperatorStats of every registered operator that was expected to sign a task.
+// Only operators registered onchain are recorded, so the ledger can't be grown with made up operator ids
+type operatorLedger struct {
+	operators map[eigentypes.OperatorId]*OperatorStats
+	mutex     sync.Mutex
+	metrics   *metrics.Metrics
+}
+
+func newOperatorLedger(aggregatorMetrics *metrics.Metrics) *operatorLedger {
+	return &operatorLedger{
+		operators: make(map[eigentypes.OperatorId]*OperatorStats),
+		metrics:   aggregatorMetrics,
+	}
+}
+
+func operatorIdLabel(operatorId eigentypes.OperatorId) string {
+	return "0x" + hex.EncodeToString(operatorId[:])
+}
+
+// stats returns the record of the operator, creating it if needed. The mutex must be held
+func (l *operatorLedger) stats(operatorId eigentypes.OperatorId) *OperatorStats {
+	stats, ok := l.operators[operatorId]
+	if !ok {
+		stats = &OperatorStats{OperatorId: operatorId}
+		l.operators[operatorId] = stats
+	}
+	return stats
+}
+
+func (l *operatorLedger) signed(operatorId eigentypes.OperatorId, latency time.Duration) {
+	l.mutex.Lock()
+	stats := l.stats(operatorId)
+	stats.TasksSigned++
+	stats.LastLatency = latency
+	stats.TotalLatency += latency
+	stats.LastSeen = time.Now()
+	lastSeen := stats.LastSeen
+	l.mutex.Unlock()
+
+	label := operatorIdLabel(operatorId)
+	l.metrics.IncOperatorTasksSigned(label)
+	l.metrics.ObserveOperatorResponseLatency(label, latency)
+	l.metrics.SetOperatorLastSeen(label, lastSeen)
+}
+
+func (l *operatorLedger) missed(operatorId eigentypes.OperatorId) {
+	l.mutex.Lock()
+	l.stats(operatorId).TasksMissed++
+	l.mutex.Unlock()
+
+	l.metrics.IncOperatorTasksMissed(operatorIdLabel(operatorId))
+}
+
+func (l *operatorLedger) invalidSignature(operatorId eigentypes.OperatorId) {
+	l.mutex.Lock()
+	l.stats(operatorId).InvalidSignatures++
+	l.mutex.Unlock()
+
+	l.metrics.IncOperatorInvalidSignatures(operatorIdLabel(operatorId))
+}
+
+func (l *operatorLedger) get(operatorId eigentypes.OperatorId) (OperatorStats, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	stats, ok := l.operators[operatorId]
+	if !ok {
+		return OperatorStats{}, false
+	}
+	return *stats, true
+}
+
+// list returns copies of every record, sorted by operator id
+func (l *operatorLedger) list() []OperatorStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	operators := make([]OperatorStats, 0, len(l.operators))
+	for _, stats := range l.operators {
+		operators = append(operators, *stats)
+	}
+	sort.Slice(operators, func(i, j int) bool {
+		return bytes.Compare(operators[i].OperatorId[:], operators[j].OperatorId[:]) < 0
+	})
+	return operators
+}
+
+// recordMissedOperators counts a missed task for every operator registered when the task was created
+// that is not in signers. Used for tasks that did not reach quorum, the ones that did report their non signers
+func (agg *Aggregator) recordMissedOperators(taskCreatedBlock uint32, quorumNums eigentypes.QuorumNums, signers map[eigentypes.OperatorId]time.Time) {
+	operators, err := agg.operatorStates.get(taskCreatedBlock, agg.fetchOperatorStates(taskCreatedBlock, quorumNums))
+	if err != nil {
+		agg.logger.Warn("Could not fetch operators to record missed tasks", "taskCreatedBlock", taskCreatedBlock, "err", err)
+		return
+	}
+	for operatorId := range operators {
+		if _, signed := signers[operatorId]; !signed {
+			agg.operatorLedger.missed(operatorId)
+		}
+	}
+}
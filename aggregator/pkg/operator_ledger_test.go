@@ -0,0 +1,50 @@
+package pkg
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+func TestOperatorLedgerStats(t *testing.T) {
+	logger := logging.NewTextSLogger(io.Discard, nil)
+	ledger := newOperatorLedger(metrics.NewMetrics("", prometheus.NewRegistry(), logger))
+	reliable := eigentypes.OperatorId{1}
+	unreliable := eigentypes.OperatorId{2}
+
+	ledger.signed(reliable, 2*time.Second)
+	ledger.signed(reliable, 4*time.Second)
+	ledger.signed(unreliable, 10*time.Second)
+	ledger.missed(unreliable)
+	ledger.missed(unreliable)
+	ledger.invalidSignature(unreliable)
+
+	stats, ok := ledger.get(reliable)
+	if !ok {
+		t.Fatalf("Reliable operator not found")
+	}
+	if stats.TasksSigned != 2 || stats.AverageLatency() != 3*time.Second || stats.LastLatency != 4*time.Second {
+		t.Errorf("Unexpected reliable operator stats: %+v", stats)
+	}
+	if stats.ResponseRate() != 1 {
+		t.Errorf("Expected response rate 1, got %f", stats.ResponseRate())
+	}
+
+	stats, _ = ledger.get(unreliable)
+	if stats.TasksMissed != 2 || stats.InvalidSignatures != 1 {
+		t.Errorf("Unexpected unreliable operator stats: %+v", stats)
+	}
+	if rate := stats.ResponseRate(); rate < 0.33 || rate > 0.34 {
+		t.Errorf("Expected response rate 1/3, got %f", rate)
+	}
+
+	operators := ledger.list()
+	if len(operators) != 2 || operators[0].OperatorId != reliable {
+		t.Errorf("Expected both operators sorted by id, got %+v", operators)
+	}
+}
@@ -0,0 +1,266 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+const (
+	// Several blocks, enough for a lagging subscription to catch up with the task
+	DefaultPendingResponseTtlBlocks = 5
+	// Batch identifier hashes are chosen by whoever calls the rpc, so the buffer must be bounded
+	DefaultMaxPendingResponses = 10000
+	// Each operator signs one task per batch, so a few per block are enough for an honest operator
+	DefaultMaxPendingResponsesPerOperator = 100
+	// The latest block is polled about once per block, to expire the pending responses
+	latestBlockPollPeriod = 12 * time.Second
+)
+
+var (
+	errPendingResponsesFull         = errors.New("pending responses buffer is full")
+	errOperatorPendingResponsesFull = errors.New("pending responses of the operator are at their limit")
+)
+
+type pendingResponse struct {
+	signedTaskResponse types.SignedTaskResponse
+	receivedBlock      uint64
+}
+
+// pendingResponseBuffer holds the operator responses received before the aggregator knows their task,
+// keyed by batch identifier hash and operator id, until the task is added or they expire.
+// Responses expire ttlBlocks after the latest block known when they were received
+type pendingResponseBuffer struct {
+	responses      map[[32]byte]map[eigentypes.OperatorId]pendingResponse
+	operatorCounts map[eigentypes.OperatorId]int
+	count          int
+	ttlBlocks      uint64
+	maxResponses   int
+	maxPerOperator int
+	latestBlock    uint64
+	lastSweepBlock uint64
+	mutex          sync.Mutex
+}
+
+func newPendingResponseBuffer(ttlBlocks uint64, maxResponses int, maxPerOperator int) *pendingResponseBuffer {
+	if ttlBlocks == 0 {
+		ttlBlocks = DefaultPendingResponseTtlBlocks
+	}
+	if maxResponses == 0 {
+		maxResponses = DefaultMaxPendingResponses
+	}
+	if maxPerOperator == 0 {
+		maxPerOperator = DefaultMaxPendingResponsesPerOperator
+	}
+	return &pendingResponseBuffer{
+		responses:      make(map[[32]byte]map[eigentypes.OperatorId]pendingResponse),
+		operatorCounts: make(map[eigentypes.OperatorId]int),
+		ttlBlocks:      ttlBlocks,
+		maxResponses:   maxResponses,
+		maxPerOperator: maxPerOperator,
+	}
+}
+
+// observeBlock advances the latest known block, never moving it back
+func (b *pendingResponseBuffer) observeBlock(block uint64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if block > b.latestBlock {
+		b.latestBlock = block
+	}
+}
+
+// currentBlock returns the latest known block, 0 if none was observed yet
+func (b *pendingResponseBuffer) currentBlock() uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.latestBlock
+}
+
+// add buffers the response, replacing a previous one from the same operator for the same batch.
+// Returns the number of expired responses removed, and an error if the buffer or the operator is at its limit
+func (b *pendingResponseBuffer) add(signedTaskResponse *types.SignedTaskResponse) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	expired := 0
+	if b.latestBlock != b.lastSweepBlock || b.count >= b.maxResponses {
+		expired = b.removeExpired()
+		b.lastSweepBlock = b.latestBlock
+	}
+
+	operatorId := signedTaskResponse.OperatorId
+	operatorResponses := b.responses[signedTaskResponse.BatchIdentifierHash]
+	if _, replaced := operatorResponses[operatorId]; !replaced {
+		if b.count >= b.maxResponses {
+			return expired, errPendingResponsesFull
+		}
+		if b.operatorCounts[operatorId] >= b.maxPerOperator {
+			return expired, errOperatorPendingResponsesFull
+		}
+		if operatorResponses == nil {
+			operatorResponses = make(map[eigentypes.OperatorId]pendingResponse)
+			b.responses[signedTaskResponse.BatchIdentifierHash] = operatorResponses
+		}
+		b.count++
+		b.operatorCounts[operatorId]++
+	}
+	operatorResponses[operatorId] = pendingResponse{
+		signedTaskResponse: *signedTaskResponse,
+		receivedBlock:      b.latestBlock,
+	}
+	return expired, nil
+}
+
+// take removes and returns the unexpired responses buffered for the batch
+func (b *pendingResponseBuffer) take(batchIdentifierHash [32]byte) []types.SignedTaskResponse {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	operatorResponses, ok := b.responses[batchIdentifierHash]
+	if !ok {
+		return nil
+	}
+	b.remove(batchIdentifierHash, operatorResponses)
+
+	responses := make([]types.SignedTaskResponse, 0, len(operatorResponses))
+	for _, response := range operatorResponses {
+		if !b.expired(response) {
+			responses = append(responses, response.signedTaskResponse)
+		}
+	}
+	return responses
+}
+
+// removeExpired drops the responses received more than ttlBlocks blocks ago. The mutex must be held
+func (b *pendingResponseBuffer) removeExpired() int {
+	expired := 0
+	for batchIdentifierHash, operatorResponses := range b.responses {
+		for operatorId, response := range operatorResponses {
+			if b.expired(response) {
+				delete(operatorResponses, operatorId)
+				b.removeOperatorResponse(operatorId)
+				expired++
+			}
+		}
+		if len(operatorResponses) == 0 {
+			delete(b.responses, batchIdentifierHash)
+		}
+	}
+	b.count -= expired
+	return expired
+}
+
+// remove drops the responses of the batch from the counts. The mutex must be held
+func (b *pendingResponseBuffer) remove(batchIdentifierHash [32]byte, operatorResponses map[eigentypes.OperatorId]pendingResponse) {
+	delete(b.responses, batchIdentifierHash)
+	b.count -= len(operatorResponses)
+	for operatorId := range operatorResponses {
+		b.removeOperatorResponse(operatorId)
+	}
+}
+
+// removeOperatorResponse decrements the count of the operator. The mutex must be held
+func (b *pendingResponseBuffer) removeOperatorResponse(operatorId eigentypes.OperatorId) {
+	b.operatorCounts[operatorId]--
+	if b.operatorCounts[operatorId] <= 0 {
+		delete(b.operatorCounts, operatorId)
+	}
+}
+
+// expired reports whether the response is older than ttlBlocks. The mutex must be held
+func (b *pendingResponseBuffer) expired(response pendingResponse) bool {
+	return b.latestBlock > response.receivedBlock+b.ttlBlocks
+}
+
+func (b *pendingResponseBuffer) len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.count
+}
+
+// validatePendingResponse checks the signature of a response whose task is not known yet against the operators
+// registered at the latest block, so only registered operators can take space in the buffer.
+// Errors that are not rejections ask the operator to retry, as the response can't be checked yet
+func (agg *Aggregator) validatePendingResponse(signedTaskResponse *types.SignedTaskResponse) error {
+	block := agg.pendingResponses.currentBlock()
+	if block == 0 {
+		return errors.New("task is not known yet, retry later")
+	}
+	operators, err := agg.operatorStates.get(uint32(block), agg.fetchOperatorStates(uint32(block), agg.getQuorumConfig().QuorumNums))
+	if err != nil {
+		agg.logger.Warn("Could not fetch operators to validate a response of an unknown task", "block", block, "err", err)
+		return errors.New("task is not known yet, retry later")
+	}
+	if err := checkOperatorSignature(signedTaskResponse, operators); err != nil {
+		return agg.rejectSignature(signedTaskResponse, err)
+	}
+	return nil
+}
+
+// bufferPendingResponse keeps a response whose task is not known yet, to be processed once it is added.
+// The response must have been validated with validatePendingResponse
+func (agg *Aggregator) bufferPendingResponse(signedTaskResponse *types.SignedTaskResponse) bool {
+	expired, err := agg.pendingResponses.add(signedTaskResponse)
+	agg.metrics.AddPendingOperatorResponses("expired", expired)
+	if err != nil {
+		agg.metrics.AddPendingOperatorResponses("dropped", 1)
+		agg.logger.Warn("Operator signature will be lost", "err", err,
+			"batchIdentifierHash", "0x"+hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
+			"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]))
+		return false
+	}
+	agg.metrics.AddPendingOperatorResponses("buffered", 1)
+	agg.metrics.SetPendingOperatorResponses(agg.pendingResponses.len())
+	agg.logger.Info("Task not known yet, operator response buffered until it is added",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
+		"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]))
+	return true
+}
+
+// replayPendingResponses processes the responses buffered for a task that was just added
+func (agg *Aggregator) replayPendingResponses(batchIdentifierHash [32]byte, taskIndex uint32) {
+	responses := agg.pendingResponses.take(batchIdentifierHash)
+	agg.metrics.SetPendingOperatorResponses(agg.pendingResponses.len())
+	if len(responses) == 0 {
+		return
+	}
+	agg.logger.Info("Replaying buffered operator responses",
+		"batchIdentifierHash", "0x"+hex.EncodeToString(batchIdentifierHash[:]),
+		"count", len(responses))
+	agg.metrics.AddPendingOperatorResponses("replayed", len(responses))
+	for i := range responses {
+		// The operator rate limit was charged when the response was buffered
+		go func(signedTaskResponse *types.SignedTaskResponse) {
+			_, _ = agg.processSignedTaskResponse(signedTaskResponse, taskIndex, false)
+		}(&responses[i])
+	}
+}
+
+// TrackLatestBlock polls the latest block, which the pending responses expire and are validated against.
+// Blocks of new tasks are observed as they are added too
+func (agg *Aggregator) TrackLatestBlock(ctx context.Context) {
+	ticker := time.NewTicker(latestBlockPollPeriod)
+	defer ticker.Stop()
+
+	for {
+		latestBlock, err := agg.avsSubscriber.BlockNumberRetryable(ctx, retry.NetworkRetryParams())
+		if err != nil {
+			agg.logger.Warn("Could not get the latest block", "err", err)
+		} else {
+			agg.pendingResponses.observeBlock(latestBlock)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+func TestPendingResponseBuffer(t *testing.T) {
+	buffer := newPendingResponseBuffer(5, 2, 10)
+	batch := [32]byte{1}
+	otherBatch := [32]byte{2}
+
+	for _, response := range []types.SignedTaskResponse{
+		{BatchIdentifierHash: batch, OperatorId: eigentypes.OperatorId{1}},
+		// Resent by the same operator, replaces the previous one
+		{BatchIdentifierHash: batch, OperatorId: eigentypes.OperatorId{1}},
+		{BatchIdentifierHash: batch, OperatorId: eigentypes.OperatorId{2}},
+	} {
+		if _, err := buffer.add(&response); err != nil {
+			t.Fatalf("Response should have been buffered: %v", err)
+		}
+	}
+	if _, err := buffer.add(&types.SignedTaskResponse{BatchIdentifierHash: otherBatch, OperatorId: eigentypes.OperatorId{1}}); !errors.Is(err, errPendingResponsesFull) {
+		t.Fatalf("Response should have been dropped, the buffer is full: %v", err)
+	}
+	if buffer.len() != 2 {
+		t.Fatalf("Expected 2 buffered responses, got %d", buffer.len())
+	}
+
+	if responses := buffer.take(batch); len(responses) != 2 {
+		t.Fatalf("Expected 2 responses for the batch, got %d", len(responses))
+	}
+	if responses := buffer.take(batch); len(responses) != 0 {
+		t.Fatalf("Responses should be taken only once, got %d", len(responses))
+	}
+	if buffer.len() != 0 || len(buffer.operatorCounts) != 0 {
+		t.Fatalf("Expected an empty buffer, got %d responses of %d operators", buffer.len(), len(buffer.operatorCounts))
+	}
+}
+
+func TestPendingResponseBufferLimitsEachOperator(t *testing.T) {
+	buffer := newPendingResponseBuffer(5, 10, 2)
+	for i := byte(0); i < 2; i++ {
+		if _, err := buffer.add(&types.SignedTaskResponse{BatchIdentifierHash: [32]byte{i}, OperatorId: eigentypes.OperatorId{1}}); err != nil {
+			t.Fatalf("Response should have been buffered: %v", err)
+		}
+	}
+	if _, err := buffer.add(&types.SignedTaskResponse{BatchIdentifierHash: [32]byte{2}, OperatorId: eigentypes.OperatorId{1}}); !errors.Is(err, errOperatorPendingResponsesFull) {
+		t.Fatalf("Response should have been dropped, the operator is at its limit: %v", err)
+	}
+	// Other operators are not affected
+	if _, err := buffer.add(&types.SignedTaskResponse{BatchIdentifierHash: [32]byte{2}, OperatorId: eigentypes.OperatorId{2}}); err != nil {
+		t.Fatalf("Response of another operator should have been buffered: %v", err)
+	}
+
+	buffer.take([32]byte{0})
+	if _, err := buffer.add(&types.SignedTaskResponse{BatchIdentifierHash: [32]byte{2}, OperatorId: eigentypes.OperatorId{1}}); err != nil {
+		t.Fatalf("Response should have been buffered once the operator has room: %v", err)
+	}
+}
+
+func TestPendingResponseBufferExpiresAfterBlocks(t *testing.T) {
+	buffer := newPendingResponseBuffer(5, 10, 10)
+	batch := [32]byte{1}
+	buffer.observeBlock(100)
+	buffer.add(&types.SignedTaskResponse{BatchIdentifierHash: batch, OperatorId: eigentypes.OperatorId{1}})
+
+	buffer.observeBlock(105)
+	if expired, _ := buffer.add(&types.SignedTaskResponse{BatchIdentifierHash: [32]byte{2}, OperatorId: eigentypes.OperatorId{1}}); expired != 0 {
+		t.Fatalf("Responses should be kept for 5 blocks, got %d expired", expired)
+	}
+	// The latest block never moves back
+	buffer.observeBlock(90)
+	buffer.observeBlock(106)
+	expired, _ := buffer.add(&types.SignedTaskResponse{BatchIdentifierHash: [32]byte{3}, OperatorId: eigentypes.OperatorId{1}})
+	if expired != 1 {
+		t.Errorf("Expected 1 expired response, got %d", expired)
+	}
+	if responses := buffer.take(batch); len(responses) != 0 {
+		t.Errorf("Expired responses should not be replayed, got %d", len(responses))
+	}
+	if buffer.operatorCounts[eigentypes.OperatorId{1}] != 2 {
+		t.Errorf("Expected 2 responses of the operator, got %d", buffer.operatorCounts[eigentypes.OperatorId{1}])
+	}
+}
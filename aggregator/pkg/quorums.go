@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+// DefaultQuorumThresholdPercentage matches QUORUM_THRESHOLD_PERCENTAGE in AlignedLayerServiceManager.
+// It is only used if the threshold can't be read from the service manager, deployed before it was public
+const DefaultQuorumThresholdPercentage = uint8(67)
+
+// onchainCheckedQuorums are the quorums respondToTaskV2 checks the signed stake of, against QUORUM_THRESHOLD_PERCENTAGE.
+// The NonSignerStakesAndSignature has to be aggregated over these quorums only, as checkSignatures
+// verifies its quorum APKs and stake indices against them.
+// Other quorums of the registry coordinator are not aggregated, as the service manager has a single threshold
+// for the quorums it checks, so per quorum thresholds need a service manager upgrade first
+var onchainCheckedQuorums = eigentypes.QuorumNums{0}
+
+// DefaultQuorumRefreshPeriod is used when `quorum_refresh_period` is not set
+const DefaultQuorumRefreshPeriod = 1 * time.Minute
+
+// QuorumConfig holds the quorums a task has to reach and the stake threshold of each of them.
+// Both slices have the same length, QuorumThresholdPercentages[i] is the threshold of QuorumNums[i]
+type QuorumConfig struct {
+	QuorumNums                 eigentypes.QuorumNums
+	QuorumThresholdPercentages eigentypes.QuorumThresholdPercentages
+}
+
+// validateQuorumThreshold rejects a `quorum_threshold_percentage` that is not a percentage.
+// It is checked against the threshold of the service manager when the quorum config is built
+func validateQuorumThreshold(aggregatorConfig *config.AggregatorConfig) error {
+	if aggregatorConfig.Aggregator.QuorumThresholdPercentage > 100 {
+		return fmt.Errorf("invalid quorum threshold percentage %d", aggregatorConfig.Aggregator.QuorumThresholdPercentage)
+	}
+	return nil
+}
+
+// buildQuorumConfig requires the quorums respondToTaskV2 checks, which must be created in the registry coordinator,
+// with the threshold of the service manager. A configured threshold can only make the aggregator wait for more stake:
+// with a lower one it would declare quorum on responses that revert with InvalidQuorumThreshold
+func buildQuorumConfig(quorumCount uint8, onchainThreshold uint8, configThreshold uint8) (QuorumConfig, error) {
+	threshold := onchainThreshold
+	if configThreshold != 0 {
+		if configThreshold < onchainThreshold {
+			return QuorumConfig{}, fmt.Errorf("quorum threshold percentage %d is below the %d%% respondToTaskV2 requires, responses would revert",
+				configThreshold, onchainThreshold)
+		}
+		threshold = configThreshold
+	}
+
+	quorumConfig := QuorumConfig{
+		QuorumNums:                 make(eigentypes.QuorumNums, 0, len(onchainCheckedQuorums)),
+		QuorumThresholdPercentages: make(eigentypes.QuorumThresholdPercentages, 0, len(onchainCheckedQuorums)),
+	}
+	for _, quorumNumber := range onchainCheckedQuorums {
+		if uint8(quorumNumber) >= quorumCount {
+			return QuorumConfig{}, fmt.Errorf("quorum %d is not created in the registry coordinator, %d quorums found", quorumNumber, quorumCount)
+		}
+		quorumConfig.QuorumNums = append(quorumConfig.QuorumNums, quorumNumber)
+		quorumConfig.QuorumThresholdPercentages = append(quorumConfig.QuorumThresholdPercentages, eigentypes.QuorumThresholdPercentage(threshold))
+	}
+	return quorumConfig, nil
+}
+
+// fetchQuorumConfig builds the quorum config from the quorums created in the registry coordinator
+// and the threshold of the service manager
+func (agg *Aggregator) fetchQuorumConfig() (QuorumConfig, error) {
+	quorumCount, err := agg.avsReader.GetQuorumCountRetryable(&bind.CallOpts{}, retry.NetworkRetryParams())
+	if err != nil {
+		return QuorumConfig{}, fmt.Errorf("failed to get quorum count: %w", err)
+	}
+
+	onchainThreshold, err := agg.avsReader.QuorumThresholdPercentageRetryable(&bind.CallOpts{}, retry.NetworkRetryParams())
+	if err != nil {
+		agg.logger.Warn("Failed to get the quorum threshold of the service manager, using the default one",
+			"default", DefaultQuorumThresholdPercentage, "err", err)
+		onchainThreshold = DefaultQuorumThresholdPercentage
+	}
+
+	return buildQuorumConfig(quorumCount, onchainThreshold, agg.AggregatorConfig.Aggregator.QuorumThresholdPercentage)
+}
+
+// getQuorumConfig returns the quorum config used for new tasks
+func (agg *Aggregator) getQuorumConfig() QuorumConfig {
+	agg.quorumConfigMutex.RLock()
+	defer agg.quorumConfigMutex.RUnlock()
+	return agg.quorumConfig
+}
+
+// Long-lived goroutine that periodically reloads the quorum config from chain,
+// so new quorums are picked up by the next tasks without restarting the aggregator.
+// Tasks already initialized keep the quorums they were created with
+func (agg *Aggregator) RefreshQuorumConfig(ctx context.Context) {
+	refreshPeriod := agg.AggregatorConfig.Aggregator.QuorumRefreshPeriod
+	if refreshPeriod == 0 {
+		refreshPeriod = DefaultQuorumRefreshPeriod
+	}
+
+	ticker := time.NewTicker(refreshPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			quorumConfig, err := agg.fetchQuorumConfig()
+			if err != nil {
+				agg.logger.Warn("Failed to refresh quorum config, keeping the current one", "err", err)
+				continue
+			}
+
+			agg.quorumConfigMutex.Lock()
+			if !reflect.DeepEqual(agg.quorumConfig, quorumConfig) {
+				agg.logger.Info("Quorum config changed",
+					"quorumNums", quorumConfig.QuorumNums,
+					"quorumThresholdPercentages", quorumConfig.QuorumThresholdPercentages)
+				agg.quorumConfig = quorumConfig
+			}
+			agg.quorumConfigMutex.Unlock()
+		}
+	}
+}
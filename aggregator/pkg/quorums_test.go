@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"testing"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+)
+
+func TestBuildQuorumConfig(t *testing.T) {
+	tests := map[string]struct {
+		quorumCount       uint8
+		configThreshold   uint8
+		valid             bool
+		expectedThreshold eigentypes.QuorumThresholdPercentage
+	}{
+		"service manager threshold": {quorumCount: 1, valid: true, expectedThreshold: 67},
+		"stricter threshold":        {quorumCount: 1, configThreshold: 80, valid: true, expectedThreshold: 80},
+		"lower threshold":           {quorumCount: 1, configThreshold: 50, valid: false},
+		"quorum 0 not created":      {quorumCount: 0, valid: false},
+		// Only quorum 0 is checked by respondToTaskV2, so only it is aggregated
+		"several quorums": {quorumCount: 2, valid: true, expectedThreshold: 67},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			quorumConfig, err := buildQuorumConfig(test.quorumCount, 67, test.configThreshold)
+			if (err == nil) != test.valid {
+				t.Fatalf("Expected valid=%v, got %v", test.valid, err)
+			}
+			if !test.valid {
+				return
+			}
+			if len(quorumConfig.QuorumNums) != 1 || quorumConfig.QuorumNums[0] != 0 {
+				t.Errorf("Expected only quorum 0, got %v", quorumConfig.QuorumNums)
+			}
+			if quorumConfig.QuorumThresholdPercentages[0] != test.expectedThreshold {
+				t.Errorf("Expected threshold %d, got %d", test.expectedThreshold, quorumConfig.QuorumThresholdPercentages[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import "sync"
+
+// responseSlots limits the aggregated responses sent concurrently.
+// Low priority responses only take a free slot when no high priority one is waiting for it
+type responseSlots struct {
+	size        int
+	used        int
+	highWaiting int
+	mutex       sync.Mutex
+	cond        *sync.Cond
+}
+
+func newResponseSlots(size int) *responseSlots {
+	slots := &responseSlots{size: size}
+	slots.cond = sync.NewCond(&slots.mutex)
+	return slots
+}
+
+// acquire blocks until a slot is taken, and returns the slots in use
+func (s *responseSlots) acquire(lowPriority bool) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !lowPriority {
+		s.highWaiting++
+		defer func() {
+			s.highWaiting--
+			// Low priority responses may be waiting only for this one
+			s.cond.Broadcast()
+		}()
+	}
+	for s.used >= s.size || (lowPriority && s.highWaiting > 0) {
+		s.cond.Wait()
+	}
+	s.used++
+	return s.used
+}
+
+// release frees a slot, and returns the slots in use
+func (s *responseSlots) release() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.used--
+	s.cond.Broadcast()
+	return s.used
+}
@@ -0,0 +1,375 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+// Defaults used when the rpc limits are not set in the config
+const (
+	DefaultRpcMaxRequestBodySize = int64(64 * 1024)
+	DefaultRpcReadTimeout        = 5 * time.Minute
+	DefaultRpcWriteTimeout       = 30 * time.Second
+)
+
+// Reasons an rpc call is rejected, used as metric labels
+const (
+	RpcRejectionIpRateLimit       = "ip_rate_limit"
+	RpcRejectionOperatorRateLimit = "operator_rate_limit"
+	RpcRejectionBodyTooLarge      = "body_too_large"
+)
+
+// Rate limiter buckets that were not used for this long are dropped
+const rateLimiterIdleTimeout = 10 * time.Minute
+
+var errRequestBodyTooLarge = errors.New("request body too large")
+
+// tokenBucket allows `rate` calls per second on average, with bursts of up to `burst` calls
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// rateLimiter keeps one token bucket per key. A rate of 0 disables it
+type rateLimiter struct {
+	rate      float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	mutex     sync.Mutex
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *rateLimiter) allow(key string) bool {
+	if l.rate <= 0 {
+		return true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > rateLimiterIdleTimeout {
+		for bucketKey, bucket := range l.buckets {
+			if now.Sub(bucket.lastRefill) > rateLimiterIdleTimeout {
+				delete(l.buckets, bucketKey)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, lastRefill: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.lastRefill).Seconds()*l.rate)
+	bucket.lastRefill = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// rpcLimits protects the operators rpc server from clients flooding it
+type rpcLimits struct {
+	ipLimiter          *rateLimiter
+	operatorLimiter    *rateLimiter
+	bypassOperatorIds  map[eigentypes.OperatorId]struct{}
+	bypassIps          map[string]struct{}
+	concurrentCalls    chan struct{}
+	maxRequestBodySize int64
+	readTimeout        time.Duration
+	writeTimeout       time.Duration
+	metrics            *metrics.Metrics
+}
+
+func newRpcLimits(aggregatorConfig *config.AggregatorConfig, aggregatorMetrics *metrics.Metrics) (*rpcLimits, error) {
+	limits := &rpcLimits{
+		ipLimiter:          newRateLimiter(aggregatorConfig.Aggregator.RpcIpRateLimit, aggregatorConfig.Aggregator.RpcIpRateBurst),
+		operatorLimiter:    newRateLimiter(aggregatorConfig.Aggregator.RpcOperatorRateLimit, aggregatorConfig.Aggregator.RpcOperatorRateBurst),
+		bypassOperatorIds:  make(map[eigentypes.OperatorId]struct{}),
+		bypassIps:          make(map[string]struct{}),
+		maxRequestBodySize: aggregatorConfig.Aggregator.RpcMaxRequestBodySize,
+		readTimeout:        aggregatorConfig.Aggregator.RpcReadTimeout,
+		writeTimeout:       aggregatorConfig.Aggregator.RpcWriteTimeout,
+		metrics:            aggregatorMetrics,
+	}
+	if limits.maxRequestBodySize == 0 {
+		limits.maxRequestBodySize = DefaultRpcMaxRequestBodySize
+	}
+	if limits.readTimeout == 0 {
+		limits.readTimeout = DefaultRpcReadTimeout
+	}
+	if limits.writeTimeout == 0 {
+		limits.writeTimeout = DefaultRpcWriteTimeout
+	}
+	if aggregatorConfig.Aggregator.RpcMaxConcurrentCalls > 0 {
+		limits.concurrentCalls = make(chan struct{}, aggregatorConfig.Aggregator.RpcMaxConcurrentCalls)
+	}
+	for _, operatorIdHex := range aggregatorConfig.Aggregator.RpcBypassOperatorIds {
+		operatorIdBytes, err := hex.DecodeString(strings.TrimPrefix(operatorIdHex, "0x"))
+		if err != nil || len(operatorIdBytes) != len(eigentypes.OperatorId{}) {
+			return nil, fmt.Errorf("invalid operator id in rpc_bypass_operator_ids: %s", operatorIdHex)
+		}
+		limits.bypassOperatorIds[eigentypes.OperatorId(operatorIdBytes)] = struct{}{}
+	}
+	for _, ip := range aggregatorConfig.Aggregator.RpcBypassIps {
+		limits.bypassIps[ip] = struct{}{}
+	}
+
+	aggregatorMetrics.SetRpcLimit("ip_rate_limit", aggregatorConfig.Aggregator.RpcIpRateLimit)
+	aggregatorMetrics.SetRpcLimit("ip_rate_burst", float64(aggregatorConfig.Aggregator.RpcIpRateBurst))
+	aggregatorMetrics.SetRpcLimit("operator_rate_limit", aggregatorConfig.Aggregator.RpcOperatorRateLimit)
+	aggregatorMetrics.SetRpcLimit("operator_rate_burst", float64(aggregatorConfig.Aggregator.RpcOperatorRateBurst))
+	aggregatorMetrics.SetRpcLimit("max_concurrent_calls", float64(aggregatorConfig.Aggregator.RpcMaxConcurrentCalls))
+	aggregatorMetrics.SetRpcLimit("max_request_body_size", float64(limits.maxRequestBodySize))
+	aggregatorMetrics.SetRpcLimit("read_timeout_seconds", limits.readTimeout.Seconds())
+	aggregatorMetrics.SetRpcLimit("write_timeout_seconds", limits.writeTimeout.Seconds())
+
+	return limits, nil
+}
+
+func (l *rpcLimits) isBypassedOperator(operatorId eigentypes.OperatorId) bool {
+	_, ok := l.bypassOperatorIds[operatorId]
+	return ok
+}
+
+// allowOperator applies the per operator rate limit. The operator id is claimed by the caller, so it must only be
+// charged, or bypassed, once the signature of the response is verified, otherwise anyone could drain the bucket
+// of an operator or skip the limit by claiming a bypassed id
+func (l *rpcLimits) allowOperator(operatorId eigentypes.OperatorId) error {
+	if l.isBypassedOperator(operatorId) || l.operatorLimiter.allow(string(operatorId[:])) {
+		return nil
+	}
+	l.metrics.IncRpcRejectedCalls(RpcRejectionOperatorRateLimit)
+	return errors.New("operator rate limit exceeded")
+}
+
+// handler accepts net/rpc connections like rpc.Server.ServeHTTP does,
+// serving each of them through a codec that enforces the limits
+func (l *rpcLimits) handler(server *rpc.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodConnect {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, _ = io.WriteString(w, "405 must CONNECT\n")
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+		if err != nil {
+			ip = conn.RemoteAddr().String()
+		}
+		_, _ = io.WriteString(conn, "HTTP/1.0 200 Connected to Go RPC\n\n")
+		server.ServeCodec(newLimitedServerCodec(conn, ip, l))
+	}
+}
+
+// deadlineConn sets the read and write deadlines before each operation
+type deadlineConn struct {
+	net.Conn
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+}
+
+func (c *deadlineConn) Read(p []byte) (int, error) {
+	_ = c.Conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+	return c.Conn.Read(p)
+}
+
+func (c *deadlineConn) Write(p []byte) (int, error) {
+	_ = c.Conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	return c.Conn.Write(p)
+}
+
+// gobMessageSizeLimiter passes a gob stream through, failing on any message longer than maxMessageSize.
+// The gob decoder allocates the length a message announces before reading it,
+// so the length prefix is checked here, before the decoder sees it
+type gobMessageSizeLimiter struct {
+	reader         *bufio.Reader
+	maxMessageSize uint64
+	prefix         []byte
+	remaining      uint64
+}
+
+func (l *gobMessageSizeLimiter) Read(p []byte) (int, error) {
+	if len(l.prefix) > 0 {
+		n := copy(p, l.prefix)
+		l.prefix = l.prefix[n:]
+		return n, nil
+	}
+	if l.remaining == 0 {
+		if err := l.readPrefix(); err != nil {
+			return 0, err
+		}
+		return l.Read(p)
+	}
+	if uint64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.reader.Read(p)
+	l.remaining -= uint64(n)
+	return n, err
+}
+
+// readPrefix reads a message length, encoded as a gob unsigned integer:
+// a single byte if it is lower than 128, otherwise the negated byte count followed by the big endian bytes
+func (l *gobMessageSizeLimiter) readPrefix() error {
+	first, err := l.reader.ReadByte()
+	if err != nil {
+		return err
+	}
+	prefix := []byte{first}
+	length := uint64(first)
+	if first >= 0x80 {
+		byteCount := int(-int8(first))
+		if byteCount > 8 {
+			return errRequestBodyTooLarge
+		}
+		lengthBytes := make([]byte, byteCount)
+		if _, err := io.ReadFull(l.reader, lengthBytes); err != nil {
+			return err
+		}
+		prefix = append(prefix, lengthBytes...)
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | uint64(b)
+		}
+	}
+	if length > l.maxMessageSize {
+		return errRequestBodyTooLarge
+	}
+	l.prefix = prefix
+	l.remaining = length
+	return nil
+}
+
+// limitedServerCodec is the net/rpc gob codec, with the rpc limits applied on every call.
+// Rejected calls get an error response, only oversized requests close the connection
+type limitedServerCodec struct {
+	conn     *deadlineConn
+	ip       string
+	limits   *rpcLimits
+	dec      *gob.Decoder
+	enc      *gob.Encoder
+	encBuf   *bufio.Writer
+	closed   bool
+	acquired atomic.Int64
+}
+
+func newLimitedServerCodec(conn net.Conn, ip string, limits *rpcLimits) *limitedServerCodec {
+	deadlines := &deadlineConn{
+		Conn:         conn,
+		readTimeout:  limits.readTimeout,
+		writeTimeout: limits.writeTimeout,
+	}
+	sizeLimiter := &gobMessageSizeLimiter{
+		reader:         bufio.NewReader(deadlines),
+		maxMessageSize: uint64(limits.maxRequestBodySize),
+	}
+	encBuf := bufio.NewWriter(deadlines)
+	return &limitedServerCodec{
+		conn:   deadlines,
+		ip:     ip,
+		limits: limits,
+		dec:    gob.NewDecoder(sizeLimiter),
+		enc:    gob.NewEncoder(encBuf),
+		encBuf: encBuf,
+	}
+}
+
+func (c *limitedServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	if err := c.dec.Decode(r); err != nil {
+		if errors.Is(err, errRequestBodyTooLarge) {
+			c.limits.metrics.IncRpcRejectedCalls(RpcRejectionBodyTooLarge)
+		}
+		return err
+	}
+	// Every call reaching this point gets a response, which releases the slot
+	if c.limits.concurrentCalls != nil {
+		c.limits.concurrentCalls <- struct{}{}
+		c.acquired.Add(1)
+	}
+	c.limits.metrics.IncRpcInflightCalls()
+	return nil
+}
+
+func (c *limitedServerCodec) ReadRequestBody(body any) error {
+	if err := c.dec.Decode(body); err != nil {
+		if errors.Is(err, errRequestBodyTooLarge) {
+			c.limits.metrics.IncRpcRejectedCalls(RpcRejectionBodyTooLarge)
+		}
+		return err
+	}
+	// The operator id in the body is not verified yet, so only the ip can bypass this limit
+	if _, ok := c.limits.bypassIps[c.ip]; ok {
+		return nil
+	}
+	if !c.limits.ipLimiter.allow(c.ip) {
+		c.limits.metrics.IncRpcRejectedCalls(RpcRejectionIpRateLimit)
+		return errors.New("ip rate limit exceeded")
+	}
+	return nil
+}
+
+func (c *limitedServerCodec) WriteResponse(r *rpc.Response, body any) (err error) {
+	defer c.releaseCall()
+	if err = c.enc.Encode(r); err != nil {
+		if c.encBuf.Flush() == nil {
+			c.Close()
+		}
+		return
+	}
+	if err = c.enc.Encode(body); err != nil {
+		if c.encBuf.Flush() == nil {
+			c.Close()
+		}
+		return
+	}
+	return c.encBuf.Flush()
+}
+
+func (c *limitedServerCodec) releaseCall() {
+	c.limits.metrics.DecRpcInflightCalls()
+	if c.limits.concurrentCalls != nil && c.acquired.Add(-1) >= 0 {
+		<-c.limits.concurrentCalls
+	}
+}
+
+func (c *limitedServerCodec) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http/httptest"
+	"net/rpc"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/types"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+type RpcLimitsTestService struct{}
+
+func (s *RpcLimitsTestService) Echo(args *string, reply *string) error {
+	*reply = *args
+	return nil
+}
+
+func (s *RpcLimitsTestService) Respond(args *types.SignedTaskResponse, reply *uint8) error {
+	*reply = 0
+	return nil
+}
+
+func newRpcLimitsTestClient(t *testing.T, aggregatorConfig *config.AggregatorConfig) *rpc.Client {
+	t.Helper()
+	logger := logging.NewTextSLogger(io.Discard, nil)
+	limits, err := newRpcLimits(aggregatorConfig, metrics.NewMetrics("", prometheus.NewRegistry(), logger))
+	if err != nil {
+		t.Fatalf("Could not create rpc limits: %v", err)
+	}
+	server := rpc.NewServer()
+	if err := server.Register(&RpcLimitsTestService{}); err != nil {
+		t.Fatalf("Could not register rpc service: %v", err)
+	}
+	httpServer := httptest.NewServer(limits.handler(server))
+	t.Cleanup(httpServer.Close)
+
+	client, err := rpc.DialHTTPPath("tcp", strings.TrimPrefix(httpServer.URL, "http://"), rpc.DefaultRPCPath)
+	if err != nil {
+		t.Fatalf("Could not dial rpc server: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestRpcIpRateLimitKeepsConnectionUsable(t *testing.T) {
+	aggregatorConfig := &config.AggregatorConfig{}
+	aggregatorConfig.Aggregator.RpcIpRateLimit = 0.001
+	aggregatorConfig.Aggregator.RpcIpRateBurst = 1
+	client := newRpcLimitsTestClient(t, aggregatorConfig)
+
+	var reply string
+	if err := client.Call("RpcLimitsTestService.Echo", "first", &reply); err != nil || reply != "first" {
+		t.Fatalf("First call should be allowed, got %q, %v", reply, err)
+	}
+	for i := 0; i < 2; i++ {
+		err := client.Call("RpcLimitsTestService.Echo", "again", &reply)
+		if err == nil || !strings.Contains(err.Error(), "ip rate limit exceeded") {
+			t.Fatalf("Expected ip rate limit error, got %v", err)
+		}
+	}
+}
+
+func TestRpcIpRateLimitAppliesToClaimedBypassedOperator(t *testing.T) {
+	bypassedOperatorId := eigentypes.OperatorId{1}
+	aggregatorConfig := &config.AggregatorConfig{}
+	aggregatorConfig.Aggregator.RpcIpRateLimit = 0.001
+	aggregatorConfig.Aggregator.RpcIpRateBurst = 1
+	aggregatorConfig.Aggregator.RpcBypassOperatorIds = []string{hex.EncodeToString(bypassedOperatorId[:])}
+	client := newRpcLimitsTestClient(t, aggregatorConfig)
+
+	// The request is not signed, so claiming a bypassed operator id must not skip the ip rate limit
+	unsignedResponse := &types.SignedTaskResponse{OperatorId: bypassedOperatorId}
+	var reply uint8
+	if err := client.Call("RpcLimitsTestService.Respond", unsignedResponse, &reply); err != nil {
+		t.Fatalf("First call should be allowed, got %v", err)
+	}
+	err := client.Call("RpcLimitsTestService.Respond", unsignedResponse, &reply)
+	if err == nil || !strings.Contains(err.Error(), "ip rate limit exceeded") {
+		t.Fatalf("Expected ip rate limit error, got %v", err)
+	}
+}
+
+func TestRpcRejectsOversizedRequests(t *testing.T) {
+	aggregatorConfig := &config.AggregatorConfig{}
+	aggregatorConfig.Aggregator.RpcMaxRequestBodySize = 1024
+	client := newRpcLimitsTestClient(t, aggregatorConfig)
+
+	var reply string
+	if err := client.Call("RpcLimitsTestService.Echo", "small", &reply); err != nil {
+		t.Fatalf("Small request should be allowed: %v", err)
+	}
+	if err := client.Call("RpcLimitsTestService.Echo", strings.Repeat("a", 4096), &reply); err == nil {
+		t.Fatalf("Oversized request should be rejected")
+	}
+}
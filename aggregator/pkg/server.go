@@ -9,19 +9,21 @@ import (
 	"net/rpc"
 	"time"
 
-	retry "github.com/yetanotherco/aligned_layer/core"
 	"github.com/yetanotherco/aligned_layer/core/types"
 )
 
 func (agg *Aggregator) ServeOperators() error {
 	// Registers a new RPC server
-	err := rpc.Register(agg)
+	server := rpc.NewServer()
+	err := server.Register(agg)
 	if err != nil {
 		return err
 	}
 
-	// Registers an HTTP handler for RPC messages
-	rpc.HandleHTTP()
+	// Registers an HTTP handler for RPC messages, at the path operators dial with rpc.DialHTTP.
+	// Connections are served through a codec that applies the rpc limits to every call
+	mux := http.NewServeMux()
+	mux.Handle(rpc.DefaultRPCPath, agg.rpcLimits.handler(server))
 
 	// Start listening for requests on aggregator address
 	// ServeOperators accepts incoming HTTP connections on the listener, creating
@@ -30,7 +32,13 @@ func (agg *Aggregator) ServeOperators() error {
 	agg.logger.Info("Starting RPC server on address", "address",
 		agg.AggregatorConfig.Aggregator.ServerIpPortAddress)
 
-	err = http.ListenAndServe(agg.AggregatorConfig.Aggregator.ServerIpPortAddress, nil)
+	httpServer := http.Server{
+		Addr:              agg.AggregatorConfig.Aggregator.ServerIpPortAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		MaxHeaderBytes:    1 << 20, // This is 1MB
+	}
+	err = httpServer.ListenAndServe()
 
 	return err
 }
@@ -43,6 +51,7 @@ func (agg *Aggregator) ServeOperators() error {
 // Returns:
 //   - 0: Success
 //   - 1: Error
+//   - 2: Accepted, the task is not known yet and the response will be processed once it is
 func (agg *Aggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *types.SignedTaskResponse, reply *uint8) error {
 	agg.AggregatorConfig.BaseConfig.Logger.Info("New task response",
 		"BatchMerkleRoot", "0x"+hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
@@ -51,28 +60,71 @@ func (agg *Aggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *t
 		"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]))
 
 	if signedTaskResponse.BlsSignature.G1Point == nil {
-		agg.logger.Warn("invalid operator response with nil signature",
-			"BatchMerkleRoot", "0x"+hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
-			"SenderAddress", "0x"+hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
-			"BatchIdentifierHash", "0x"+hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
-			"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]))
 		*reply = 1
-		return errors.New("invalid response: nil signature")
+		return agg.rejectSignature(signedTaskResponse, &SignatureRejection{
+			Reason: RejectionNilSignature,
+			Detail: "nil signature",
+		})
+	}
+	if err := checkBatchIdentifierHash(signedTaskResponse); err != nil {
+		*reply = 1
+		return agg.rejectSignature(signedTaskResponse, err)
 	}
-
-	taskIndex := uint32(0)
 
 	// The Aggregator may receive the Task Identifier after the operators.
-	// If that's the case, we won't know about the task at this point
-	// so we make GetTaskIndex retryable, waiting for some seconds,
-	// before trying to fetch the task again from the map.
-	taskIndex, err := agg.GetTaskIndexRetryable(signedTaskResponse.BatchIdentifierHash, retry.NetworkRetryParams())
+	// If that's the case, the response is validated against the current operators outside the task mutex,
+	// then buffered and processed once the task is added.
+	// The buffering happens under the task mutex, so AddNewTask can't add the task in between
+	agg.taskMutex.Lock()
+	taskIndex, ok := agg.batchesIdxByIdentifierHash[signedTaskResponse.BatchIdentifierHash]
+	agg.taskMutex.Unlock()
+	if !ok {
+		if err := agg.validatePendingResponse(signedTaskResponse); err != nil {
+			*reply = 1
+			return err
+		}
+		if err := agg.rpcLimits.allowOperator(signedTaskResponse.OperatorId); err != nil {
+			*reply = 1
+			return err
+		}
+		agg.taskMutex.Lock()
+		taskIndex, ok = agg.batchesIdxByIdentifierHash[signedTaskResponse.BatchIdentifierHash]
+		if !ok {
+			buffered := agg.bufferPendingResponse(signedTaskResponse)
+			agg.taskMutex.Unlock()
+			if !buffered {
+				*reply = 1
+				return nil
+			}
+			*reply = 2
+			return nil
+		}
+		agg.taskMutex.Unlock()
+	}
 
-	if err != nil {
-		agg.logger.Warn("Task not found in the internal map, operator signature will be lost. Batch may not reach quorum")
-		*reply = 1
-		return nil
+	res, err := agg.processSignedTaskResponse(signedTaskResponse, taskIndex, true)
+	*reply = res
+	return err
+}
+
+// processSignedTaskResponse validates the response of a known task and hands it to the BLS aggregation service.
+// If limitOperator is set, the operator rate limit is charged once the signature is valid, as anyone can claim
+// an operator id. Until then, only the ip rate limit applies.
+// Returns the rpc reply code, and the error to send to the operator if the signature was rejected
+func (agg *Aggregator) processSignedTaskResponse(signedTaskResponse *types.SignedTaskResponse, taskIndex uint32, limitOperator bool) (uint8, error) {
+	agg.taskMutex.Lock()
+	taskCreatedBlock := agg.batchCreatedBlockByIdx[taskIndex]
+	taskCreatedAt := agg.batchStartTimeByIdx[taskIndex]
+	agg.taskMutex.Unlock()
+	if err := agg.validateOperatorSignature(signedTaskResponse, uint32(taskCreatedBlock)); err != nil {
+		return 1, agg.rejectSignature(signedTaskResponse, err)
+	}
+	if limitOperator {
+		if err := agg.rpcLimits.allowOperator(signedTaskResponse.OperatorId); err != nil {
+			return 1, err
+		}
 	}
+
 	agg.telemetry.LogOperatorResponse(signedTaskResponse.BatchMerkleRoot, signedTaskResponse.OperatorId)
 
 	// Don't wait infinitely if it can't answer
@@ -96,13 +148,15 @@ func (agg *Aggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *t
 			// todo shouldn't we here close the channel with a reply = 1?
 		} else {
 			agg.logger.Info("BLS process succeeded")
+			agg.taskInfos.signatureAdded(signedTaskResponse.BatchIdentifierHash, signedTaskResponse.OperatorId)
+			agg.operatorLedger.signed(signedTaskResponse.OperatorId, time.Since(taskCreatedAt))
 			done<- 0
 		}
 
 		close(done)
 	}()
 
-	*reply = 1
+	reply := uint8(1)
 	// Wait for either the context to be done or the task to complete
 	select {
 	case <-ctx.Done():
@@ -111,10 +165,32 @@ func (agg *Aggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *t
 	case res := <-done:
 		// The task completed successfully
 		agg.logger.Info("Bls context finished on time")
-		*reply = res
+		reply = res
 	}
 
-	return nil
+	return reply, nil
+}
+
+// rejectSignature logs and counts a signature that failed the early validation, and returns the error for the operator
+func (agg *Aggregator) rejectSignature(signedTaskResponse *types.SignedTaskResponse, err error) error {
+	reason := "unknown"
+	var rejection *SignatureRejection
+	if errors.As(err, &rejection) {
+		reason = rejection.Reason
+	}
+	agg.logger.Warn("Rejected operator signature",
+		"reason", reason,
+		"err", err,
+		"BatchMerkleRoot", "0x"+hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
+		"SenderAddress", "0x"+hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
+		"BatchIdentifierHash", "0x"+hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
+		"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]))
+	// Anyone can claim any operator id, so only registered operators are tracked by id, in the operator ledger
+	if reason == RejectionInvalidSignature || reason == RejectionMissingPubkey {
+		agg.operatorLedger.invalidSignature(signedTaskResponse.OperatorId)
+	}
+	agg.metrics.IncRejectedOperatorSignatures(reason)
+	return fmt.Errorf("invalid response: %w", err)
 }
 
 // Dummy method to check if the server is running
@@ -123,24 +199,3 @@ func (agg *Aggregator) ServerRunning(_ *struct{}, reply *int64) error {
 	*reply = 1
 	return nil
 }
-
-/*
-Checks Internal mapping for Signed Task Response, returns its TaskIndex.
-- All errors are considered Transient Errors
-- Retry times (3 retries): 1 sec, 2 sec, 4 sec
-TODO: We should refactor the retry duration considering extending it to a larger time or number of retries, at least somewhere between 1 and 2 blocks
-*/
-func (agg *Aggregator) GetTaskIndexRetryable(batchIdentifierHash [32]byte, config *retry.RetryParams) (uint32, error) {
-	getTaskIndex_func := func() (uint32, error) {
-		agg.taskMutex.Lock()
-		taskIndex, ok := agg.batchesIdxByIdentifierHash[batchIdentifierHash]
-		agg.taskMutex.Unlock()
-		if !ok {
-			return taskIndex, fmt.Errorf("Task not found in the internal map")
-		} else {
-			return taskIndex, nil
-		}
-	}
-
-	return retry.RetryWithData(getTaskIndex_func, config)
-}
@@ -0,0 +1,172 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// Reasons an operator signature is rejected before reaching the BLS aggregation service.
+// They are used as metric labels, so they must stay stable
+const (
+	RejectionNilSignature          = "nil_signature"
+	RejectionIdentifierMismatch    = "batch_identifier_mismatch"
+	RejectionOperatorNotRegistered = "operator_not_registered"
+	RejectionMissingPubkey         = "missing_pubkey"
+	RejectionInvalidSignature      = "invalid_signature"
+)
+
+// Operator states of the last blocks tasks were created at are kept, as every operator signs each task
+const operatorStateCacheSize = 64
+
+// Failed fetches are kept for this long, so a registry outage doesn't turn every response into a fetch
+const operatorStateErrorTtl = 5 * time.Second
+
+// SignatureRejection is returned when an operator signature fails the early validation
+type SignatureRejection struct {
+	Reason string
+	Detail string
+}
+
+func (r *SignatureRejection) Error() string {
+	return fmt.Sprintf("signature rejected (%s): %s", r.Reason, r.Detail)
+}
+
+// operatorStateCache keeps the registered operators at the blocks tasks were created at,
+// so validating a signature doesn't query the registry once per operator.
+// Each block is fetched once outside the mutex, concurrent lookups of the same block wait for that fetch
+type operatorStateCache struct {
+	entries map[uint32]*operatorStateEntry
+	mutex   sync.Mutex
+}
+
+type operatorStateEntry struct {
+	done      chan struct{}
+	state     map[eigentypes.OperatorId]eigentypes.OperatorAvsState
+	err       error
+	fetchedAt time.Time
+}
+
+func newOperatorStateCache() *operatorStateCache {
+	return &operatorStateCache{
+		entries: make(map[uint32]*operatorStateEntry),
+	}
+}
+
+// get returns the cached operators at block, fetching them with fetch on a miss.
+// A failed fetch is returned to every lookup for operatorStateErrorTtl before it's retried.
+// The oldest block is evicted once the cache is full
+func (c *operatorStateCache) get(block uint32, fetch func() (map[eigentypes.OperatorId]eigentypes.OperatorAvsState, error)) (map[eigentypes.OperatorId]eigentypes.OperatorAvsState, error) {
+	c.mutex.Lock()
+	entry, ok := c.entries[block]
+	if ok && entry.expired() {
+		ok = false
+	}
+	if ok {
+		c.mutex.Unlock()
+		<-entry.done
+		return entry.state, entry.err
+	}
+
+	entry = &operatorStateEntry{done: make(chan struct{})}
+	if _, cached := c.entries[block]; !cached && len(c.entries) >= operatorStateCacheSize {
+		oldest := block
+		for cachedBlock := range c.entries {
+			if cachedBlock < oldest {
+				oldest = cachedBlock
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	c.entries[block] = entry
+	c.mutex.Unlock()
+
+	entry.state, entry.err = fetch()
+	entry.fetchedAt = time.Now()
+	close(entry.done)
+	return entry.state, entry.err
+}
+
+// expired reports whether the entry is a failed fetch old enough to be retried. It must not block
+func (e *operatorStateEntry) expired() bool {
+	select {
+	case <-e.done:
+		return e.err != nil && time.Since(e.fetchedAt) > operatorStateErrorTtl
+	default:
+		return false
+	}
+}
+
+// checkBatchIdentifierHash checks that the signed identifier is keccak(batchMerkleRoot || senderAddress)
+func checkBatchIdentifierHash(signedTaskResponse *types.SignedTaskResponse) error {
+	batchIdentifier := append(signedTaskResponse.BatchMerkleRoot[:], signedTaskResponse.SenderAddress[:]...)
+	expectedHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+	if expectedHash != signedTaskResponse.BatchIdentifierHash {
+		return &SignatureRejection{
+			Reason: RejectionIdentifierMismatch,
+			Detail: "batch identifier hash is not keccak(batch merkle root || sender address)",
+		}
+	}
+	return nil
+}
+
+// checkOperatorSignature checks that the operator was registered at the block the task was created at,
+// and that the signature over the batch identifier hash matches its G2 public key.
+// Registry errors are not rejections, the signature is left for the BLS aggregation service to check
+func checkOperatorSignature(signedTaskResponse *types.SignedTaskResponse, operators map[eigentypes.OperatorId]eigentypes.OperatorAvsState) error {
+	operator, ok := operators[signedTaskResponse.OperatorId]
+	if !ok {
+		return &SignatureRejection{
+			Reason: RejectionOperatorNotRegistered,
+			Detail: "operator is not registered at the block the task was created at",
+		}
+	}
+	if operator.OperatorInfo.Pubkeys.G2Pubkey == nil {
+		return &SignatureRejection{
+			Reason: RejectionMissingPubkey,
+			Detail: "operator has no G2 public key",
+		}
+	}
+	valid, err := signedTaskResponse.BlsSignature.Verify(operator.OperatorInfo.Pubkeys.G2Pubkey, signedTaskResponse.BatchIdentifierHash)
+	if err != nil || !valid {
+		detail := "signature does not match the operator G2 public key"
+		if err != nil {
+			detail = err.Error()
+		}
+		return &SignatureRejection{
+			Reason: RejectionInvalidSignature,
+			Detail: detail,
+		}
+	}
+	return nil
+}
+
+// validateOperatorSignature checks the operator registration and signature for the task created at taskCreatedBlock
+func (agg *Aggregator) validateOperatorSignature(signedTaskResponse *types.SignedTaskResponse, taskCreatedBlock uint32) error {
+	quorumNums := agg.getQuorumConfig().QuorumNums
+	if taskInfo, ok := agg.taskInfos.get(signedTaskResponse.BatchIdentifierHash); ok {
+		quorumNums = taskInfo.QuorumConfig.QuorumNums
+	}
+
+	operators, err := agg.operatorStates.get(taskCreatedBlock, agg.fetchOperatorStates(taskCreatedBlock, quorumNums))
+	if err != nil {
+		agg.logger.Warn("Could not fetch operators to validate signature, leaving it to the BLS aggregation service",
+			"taskCreatedBlock", taskCreatedBlock, "err", err)
+		return nil
+	}
+	return checkOperatorSignature(signedTaskResponse, operators)
+}
+
+// fetchOperatorStates returns a function fetching the operators registered in quorumNums at block
+func (agg *Aggregator) fetchOperatorStates(block uint32, quorumNums eigentypes.QuorumNums) func() (map[eigentypes.OperatorId]eigentypes.OperatorAvsState, error) {
+	return func() (map[eigentypes.OperatorId]eigentypes.OperatorAvsState, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return agg.avsRegistryService.GetOperatorsAvsStateAtBlock(ctx, quorumNums, eigentypes.BlockNum(block))
+	}
+}
@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+func newSignedTaskResponse(t *testing.T, keyPair *bls.KeyPair) *types.SignedTaskResponse {
+	t.Helper()
+	batchMerkleRoot := [32]byte{1}
+	senderAddress := [20]byte{2}
+	batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(append(batchMerkleRoot[:], senderAddress[:]...)))
+	return &types.SignedTaskResponse{
+		BatchMerkleRoot:     batchMerkleRoot,
+		SenderAddress:       senderAddress,
+		BatchIdentifierHash: batchIdentifierHash,
+		BlsSignature:        *keyPair.SignMessage(batchIdentifierHash),
+		OperatorId:          eigentypes.OperatorIdFromKeyPair(keyPair),
+	}
+}
+
+func rejectionReason(err error) string {
+	var rejection *SignatureRejection
+	if errors.As(err, &rejection) {
+		return rejection.Reason
+	}
+	return ""
+}
+
+func TestSignatureValidationRejectionReasons(t *testing.T) {
+	keyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Could not generate BLS keys: %v", err)
+	}
+	otherKeyPair, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Could not generate BLS keys: %v", err)
+	}
+	operatorId := eigentypes.OperatorIdFromKeyPair(keyPair)
+	operators := map[eigentypes.OperatorId]eigentypes.OperatorAvsState{
+		operatorId: {
+			OperatorId:   operatorId,
+			OperatorInfo: eigentypes.OperatorInfo{Pubkeys: eigentypes.OperatorPubkeys{G2Pubkey: keyPair.GetPubKeyG2()}},
+		},
+	}
+
+	valid := newSignedTaskResponse(t, keyPair)
+	if err := checkBatchIdentifierHash(valid); err != nil {
+		t.Errorf("Valid identifier rejected: %v", err)
+	}
+	if err := checkOperatorSignature(valid, operators); err != nil {
+		t.Errorf("Valid signature rejected: %v", err)
+	}
+
+	mismatched := newSignedTaskResponse(t, keyPair)
+	mismatched.SenderAddress = [20]byte{3}
+	if reason := rejectionReason(checkBatchIdentifierHash(mismatched)); reason != RejectionIdentifierMismatch {
+		t.Errorf("Expected %s, got %q", RejectionIdentifierMismatch, reason)
+	}
+
+	unregistered := newSignedTaskResponse(t, otherKeyPair)
+	if reason := rejectionReason(checkOperatorSignature(unregistered, operators)); reason != RejectionOperatorNotRegistered {
+		t.Errorf("Expected %s, got %q", RejectionOperatorNotRegistered, reason)
+	}
+
+	forged := newSignedTaskResponse(t, otherKeyPair)
+	forged.OperatorId = operatorId
+	if reason := rejectionReason(checkOperatorSignature(forged, operators)); reason != RejectionInvalidSignature {
+		t.Errorf("Expected %s, got %q", RejectionInvalidSignature, reason)
+	}
+}
+
+func TestOperatorStateCacheFetchesEachBlockOnce(t *testing.T) {
+	cache := newOperatorStateCache()
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func() (map[eigentypes.OperatorId]eigentypes.OperatorAvsState, error) {
+		fetches.Add(1)
+		<-release
+		return nil, errors.New("registry unavailable")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.get(1, fetch); err == nil {
+				t.Errorf("Expected the fetch error")
+			}
+		}()
+	}
+	// Other blocks must not wait for the fetch in flight
+	if _, err := cache.get(2, func() (map[eigentypes.OperatorId]eigentypes.OperatorAvsState, error) { return nil, nil }); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// The failure is cached, it's only retried after operatorStateErrorTtl
+	if _, err := cache.get(1, fetch); err == nil {
+		t.Errorf("Expected the cached fetch error")
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("Expected a single fetch, got %d", fetches.Load())
+	}
+	cache.entries[1].fetchedAt = time.Now().Add(-2 * operatorStateErrorTtl)
+	cache.get(1, fetch)
+	if fetches.Load() != 2 {
+		t.Errorf("Expected the expired failure to be fetched again, got %d fetches", fetches.Load())
+	}
+}
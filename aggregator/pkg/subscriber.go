@@ -1,30 +1,57 @@
 package pkg
 
+// SubscribeToNewTasks listens to both NewBatchV2 and NewBatchV3, so the V2 batches created while the contract
+// is being upgraded are aggregated too
 func (agg *Aggregator) SubscribeToNewTasks() error {
-	err := agg.subscribeToNewTasks()
+	err := agg.subscribeToNewTasksV2()
+	if err != nil {
+		return err
+	}
+	err = agg.subscribeToNewTasksV3()
 	if err != nil {
 		return err
 	}
 
 	for {
 		select {
-		case err := <-agg.taskSubscriber:
-			agg.AggregatorConfig.BaseConfig.Logger.Info("Failed to subscribe to new tasks", "err", err)
-			err = agg.subscribeToNewTasks()
+		case err := <-agg.taskSubscriberV2:
+			agg.AggregatorConfig.BaseConfig.Logger.Info("Failed to subscribe to new V2 tasks", "err", err)
+			err = agg.subscribeToNewTasksV2()
+			if err != nil {
+				return err
+			}
+		case err := <-agg.taskSubscriberV3:
+			agg.AggregatorConfig.BaseConfig.Logger.Info("Failed to subscribe to new V3 tasks", "err", err)
+			err = agg.subscribeToNewTasksV3()
 			if err != nil {
 				return err
 			}
-		case newBatch := <-agg.NewBatchChan:
+		case newBatch := <-agg.NewBatchChanV2:
+			agg.AggregatorConfig.BaseConfig.Logger.Info("Adding new V2 task")
+			agg.AddNewTask(newBatchTaskFromV2(newBatch))
+		case newBatch := <-agg.NewBatchChanV3:
 			agg.AggregatorConfig.BaseConfig.Logger.Info("Adding new task")
-			agg.AddNewTask(newBatch.BatchMerkleRoot, newBatch.SenderAddress, newBatch.TaskCreatedBlock)
+			agg.AddNewTask(newBatchTaskFromV3(newBatch))
 		}
 	}
 }
 
-func (agg *Aggregator) subscribeToNewTasks() error {
+func (agg *Aggregator) subscribeToNewTasksV2() error {
+	var err error
+
+	agg.taskSubscriberV2, err = agg.avsSubscriber.SubscribeToNewTasksV2(agg.NewBatchChanV2)
+
+	if err != nil {
+		agg.AggregatorConfig.BaseConfig.Logger.Info("Failed to create V2 task subscriber", "err", err)
+	}
+
+	return err
+}
+
+func (agg *Aggregator) subscribeToNewTasksV3() error {
 	var err error
 
-	agg.taskSubscriber, err = agg.avsSubscriber.SubscribeToNewTasksV3(agg.NewBatchChan)
+	agg.taskSubscriberV3, err = agg.avsSubscriber.SubscribeToNewTasksV3(agg.NewBatchChanV3)
 
 	if err != nil {
 		agg.AggregatorConfig.BaseConfig.Logger.Info("Failed to create task subscriber", "err", err)
@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"sort"
+	"time"
+)
+
+// About 2 weeks of batches at one batch per block, each task only takes a few hundred bytes
+const DefaultMaxTrackedTasks = 100000
+
+// Reasons a task is evicted from memory. They are used as metric labels, so they must stay stable
+const (
+	EvictionReasonAge      = "age"
+	EvictionReasonCapacity = "capacity"
+)
+
+type evictionCandidate struct {
+	taskIndex           uint32
+	batchIdentifierHash [32]byte
+	taskCreatedBlock    uint64
+	completed           bool
+}
+
+func (agg *Aggregator) maxTrackedTasks() int {
+	if agg.AggregatorConfig.Aggregator.MaxTrackedTasks <= 0 {
+		return DefaultMaxTrackedTasks
+	}
+	return agg.AggregatorConfig.Aggregator.MaxTrackedTasks
+}
+
+// evictTasks removes the tasks created GarbageCollectorTasksAge blocks or more before the newest task, so no chain
+// query is needed. Pending tasks are kept until their BLS aggregation service timeout has also passed, as they
+// can still be responded. If more than capacity remain, the oldest are evicted, completed ones first.
+// Returns the number of evicted tasks. The taskMutex must be held
+func (agg *Aggregator) evictTasks(now time.Time, capacity int) int {
+	tasksAge := agg.AggregatorConfig.Aggregator.GarbageCollectorTasksAge
+	blsTimeout := agg.AggregatorConfig.Aggregator.BlsServiceTaskTimeout
+
+	remaining := make([]evictionCandidate, 0, len(agg.batchesIdentifierHashByIdx))
+	evictedByAge := 0
+	for taskIndex, batchIdentifierHash := range agg.batchesIdentifierHashByIdx {
+		candidate := evictionCandidate{
+			taskIndex:           taskIndex,
+			batchIdentifierHash: batchIdentifierHash,
+			taskCreatedBlock:    agg.batchCreatedBlockByIdx[taskIndex],
+			// Tasks without info can't be responded by this aggregator
+			completed: true,
+		}
+		if status, ok := agg.taskInfos.status(batchIdentifierHash); ok {
+			candidate.completed = status == TaskStatusResponded || status == TaskStatusConfirmed || status == TaskStatusFailed
+		}
+
+		oldEnough := candidate.taskCreatedBlock+tasksAge <= agg.latestTaskCreatedBlock
+		if oldEnough && (candidate.completed || now.Sub(agg.batchStartTimeByIdx[taskIndex]) > blsTimeout) {
+			agg.evictTask(candidate, EvictionReasonAge)
+			evictedByAge++
+			continue
+		}
+		remaining = append(remaining, candidate)
+	}
+
+	evictedByCapacity := 0
+	if overflow := len(remaining) - capacity; overflow > 0 {
+		sort.Slice(remaining, func(i, j int) bool {
+			if remaining[i].completed != remaining[j].completed {
+				return remaining[i].completed
+			}
+			if remaining[i].taskCreatedBlock != remaining[j].taskCreatedBlock {
+				return remaining[i].taskCreatedBlock < remaining[j].taskCreatedBlock
+			}
+			return remaining[i].taskIndex < remaining[j].taskIndex
+		})
+		for _, candidate := range remaining[:overflow] {
+			agg.evictTask(candidate, EvictionReasonCapacity)
+		}
+		evictedByCapacity = overflow
+		agg.logger.Warn("Too many tracked tasks, evicted the oldest ones",
+			"capacity", capacity, "evicted", evictedByCapacity)
+	}
+
+	agg.metrics.AddEvictedTasks(EvictionReasonAge, evictedByAge)
+	agg.metrics.AddEvictedTasks(EvictionReasonCapacity, evictedByCapacity)
+	return evictedByAge + evictedByCapacity
+}
+
+// evictTask removes every trace of the task kept in memory and in the task store. The taskMutex must be held
+func (agg *Aggregator) evictTask(candidate evictionCandidate, reason string) {
+	agg.logger.Info("Evicting task", "taskIndex", candidate.taskIndex,
+		"batchIdentifierHash", "0x"+hex.EncodeToString(candidate.batchIdentifierHash[:]),
+		"taskCreatedBlock", candidate.taskCreatedBlock, "completed", candidate.completed, "reason", reason)
+
+	delete(agg.batchesIdxByIdentifierHash, candidate.batchIdentifierHash)
+	delete(agg.batchCreatedBlockByIdx, candidate.taskIndex)
+	delete(agg.batchesIdentifierHashByIdx, candidate.taskIndex)
+	delete(agg.batchDataByIdentifierHash, candidate.batchIdentifierHash)
+	delete(agg.batchStartTimeByIdx, candidate.taskIndex)
+	agg.taskInfos.remove(candidate.batchIdentifierHash)
+	agg.standbyResponsesMutex.Lock()
+	delete(agg.standbyResponses, candidate.batchIdentifierHash)
+	agg.standbyResponsesMutex.Unlock()
+	if err := agg.taskStore.DeleteTask(candidate.batchIdentifierHash); err != nil {
+		agg.logger.Warn("Failed to remove task from task store", "taskIndex", candidate.taskIndex, "err", err)
+	}
+}
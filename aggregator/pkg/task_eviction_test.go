@@ -0,0 +1,151 @@
+package pkg
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+func newEvictionTestAggregator(tasksAge uint64, blsTimeout time.Duration, maxTrackedTasks int) *Aggregator {
+	logger := logging.NewTextSLogger(io.Discard, nil)
+	aggregatorConfig := config.AggregatorConfig{}
+	aggregatorConfig.Aggregator.GarbageCollectorTasksAge = tasksAge
+	aggregatorConfig.Aggregator.BlsServiceTaskTimeout = blsTimeout
+	aggregatorConfig.Aggregator.MaxTrackedTasks = maxTrackedTasks
+	return &Aggregator{
+		AggregatorConfig:           &aggregatorConfig,
+		batchesIdentifierHashByIdx: make(map[uint32][32]byte),
+		batchesIdxByIdentifierHash: make(map[[32]byte]uint32),
+		batchCreatedBlockByIdx:     make(map[uint32]uint64),
+		batchDataByIdentifierHash:  make(map[[32]byte]BatchData),
+		batchStartTimeByIdx:        make(map[uint32]time.Time),
+		taskMutex:                  &sync.Mutex{},
+		taskStore:                  NewInMemoryTaskStore(),
+		taskInfos:                  newTaskInfoTracker(),
+		standbyResponses:           make(map[[32]byte]AggregatedResponse),
+		standbyResponsesMutex:      &sync.Mutex{},
+		logger:                     logger,
+		metrics:                    metrics.NewMetrics("", prometheus.NewRegistry(), logger),
+	}
+}
+
+func evictionTestHash(n int) [32]byte {
+	var hash [32]byte
+	binary.BigEndian.PutUint64(hash[:], uint64(n))
+	return hash
+}
+
+func checkTrackedTasks(t *testing.T, agg *Aggregator, max int) {
+	t.Helper()
+	tracked := len(agg.batchesIdentifierHashByIdx)
+	storedTasks, _ := agg.taskStore.LoadTasks()
+	for name, size := range map[string]int{
+		"batchesIdentifierHashByIdx": tracked,
+		"batchesIdxByIdentifierHash": len(agg.batchesIdxByIdentifierHash),
+		"batchCreatedBlockByIdx":     len(agg.batchCreatedBlockByIdx),
+		"batchDataByIdentifierHash":  len(agg.batchDataByIdentifierHash),
+		"batchStartTimeByIdx":        len(agg.batchStartTimeByIdx),
+		"taskInfos":                  len(agg.taskInfos.tasks),
+		"taskStore":                  len(storedTasks),
+	} {
+		if size > max {
+			t.Fatalf("%s holds %d tasks, more than the %d allowed", name, size, max)
+		}
+		if size != tracked {
+			t.Fatalf("%s holds %d tasks, but %d are tracked", name, size, tracked)
+		}
+	}
+}
+
+func TestTrackedTasksStayBoundedUnderLoad(t *testing.T) {
+	const maxTrackedTasks = 500
+	agg := newEvictionTestAggregator(100, time.Hour, maxTrackedTasks)
+
+	// Several tasks per block, most of them responded, and the garbage collector running now and then
+	for i := 0; i < 20000; i++ {
+		hash := evictionTestHash(i)
+		agg.taskMutex.Lock()
+		if _, added := agg.trackTask(hash, NewBatchTask{BatchMerkleRoot: hash, TaskCreatedBlock: uint32(i / 3)}, QuorumConfig{}); !added {
+			t.Fatalf("Task %d should have been added", i)
+		}
+		if i%10 != 0 {
+			agg.taskInfos.responded(hash, "0x", "1")
+		}
+		if i%1000 == 0 {
+			agg.evictTasks(time.Now(), maxTrackedTasks)
+		}
+		checkTrackedTasks(t, agg, maxTrackedTasks)
+		agg.taskMutex.Unlock()
+	}
+
+	// The newest tasks are never evicted
+	newest := evictionTestHash(19999)
+	if _, ok := agg.batchesIdxByIdentifierHash[newest]; !ok {
+		t.Errorf("Newest task was evicted")
+	}
+}
+
+func TestEvictionKeepsPendingTasksWithinBlsTimeout(t *testing.T) {
+	agg := newEvictionTestAggregator(10, time.Hour, 100)
+	completed, pending, recent := evictionTestHash(1), evictionTestHash(2), evictionTestHash(3)
+
+	agg.taskMutex.Lock()
+	defer agg.taskMutex.Unlock()
+	agg.trackTask(completed, NewBatchTask{BatchMerkleRoot: completed, TaskCreatedBlock: 100}, QuorumConfig{})
+	agg.trackTask(pending, NewBatchTask{BatchMerkleRoot: pending, TaskCreatedBlock: 100}, QuorumConfig{})
+	agg.trackTask(recent, NewBatchTask{BatchMerkleRoot: recent, TaskCreatedBlock: 105}, QuorumConfig{})
+	agg.taskInfos.responded(completed, "0x", "1")
+	agg.taskInfos.responded(recent, "0x", "1")
+
+	// Not old enough yet
+	if evicted := agg.evictTasks(time.Now(), 100); evicted != 0 {
+		t.Fatalf("Expected no evictions, got %d", evicted)
+	}
+
+	agg.latestTaskCreatedBlock = 110
+	if evicted := agg.evictTasks(time.Now(), 100); evicted != 1 {
+		t.Fatalf("Expected only the completed task to be evicted, got %d", evicted)
+	}
+	if _, ok := agg.batchesIdxByIdentifierHash[completed]; ok {
+		t.Errorf("Completed task should have been evicted")
+	}
+
+	if evicted := agg.evictTasks(time.Now().Add(2*time.Hour), 100); evicted != 1 {
+		t.Fatalf("Expected the pending task to be evicted after the BLS timeout, got %d", evicted)
+	}
+	if _, ok := agg.batchesIdxByIdentifierHash[recent]; !ok {
+		t.Errorf("Recent task should not have been evicted")
+	}
+}
+
+func TestEvictionOverCapacityPrefersCompletedTasks(t *testing.T) {
+	agg := newEvictionTestAggregator(1000, time.Hour, 10)
+	pending := evictionTestHash(0)
+
+	agg.taskMutex.Lock()
+	defer agg.taskMutex.Unlock()
+	agg.trackTask(pending, NewBatchTask{BatchMerkleRoot: pending, TaskCreatedBlock: 1}, QuorumConfig{})
+	for i := 1; i <= 10; i++ {
+		completed := evictionTestHash(i)
+		agg.trackTask(completed, NewBatchTask{BatchMerkleRoot: completed, TaskCreatedBlock: uint32(i + 1)}, QuorumConfig{})
+		agg.taskInfos.responded(completed, "0x", "1")
+	}
+
+	// Going over the cap makes room for a few more tasks, evicting the oldest completed ones
+	if _, ok := agg.batchesIdxByIdentifierHash[pending]; !ok {
+		t.Errorf("Older pending task should have been kept")
+	}
+	for i := 1; i <= 2; i++ {
+		if _, ok := agg.batchesIdxByIdentifierHash[evictionTestHash(i)]; ok {
+			t.Errorf("Completed task %d should have been evicted", i)
+		}
+	}
+	checkTrackedTasks(t, agg, 9)
+}
@@ -0,0 +1,217 @@
+package pkg
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+)
+
+// Task statuses reported by the admin API
+const (
+	TaskStatusPending       = "pending"
+	TaskStatusQuorumReached = "quorum_reached"
+	TaskStatusDeferred      = "deferred"
+	TaskStatusResponded     = "responded"
+	TaskStatusConfirmed     = "confirmed"
+	TaskStatusFailed        = "failed"
+)
+
+// RespondToTaskAttempt is a gas price set while sending the aggregated response of a task.
+// TxHash is only set if the transaction was sent with that gas price
+type RespondToTaskAttempt struct {
+	GasPrice string    `json:"gas_price"`
+	TxHash   string    `json:"tx_hash,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// TaskInfo holds the lifecycle data of a task, as seen by the aggregator
+type TaskInfo struct {
+	BatchIdentifierHash [32]byte
+	BatchMerkleRoot     [32]byte
+	SenderAddress       [20]byte
+	TaskIndex           uint32
+	TaskCreatedBlock    uint32
+	// Version of the NewBatch event the task was created from
+	Version               string
+	BatchDataPointer      string
+	RespondToTaskFeeLimit *big.Int
+	QuorumConfig          QuorumConfig
+	Status                string
+	CreatedAt             time.Time
+	QuorumReachedAt       time.Time
+	RespondedAt           time.Time
+	ConfirmedAt           time.Time
+	// Operators whose signature was accepted by the BLS aggregation service, with the time it was received
+	Signers           map[eigentypes.OperatorId]time.Time
+	Attempts          []RespondToTaskAttempt
+	TxHash            string
+	EffectiveGasPrice string
+	Error             string
+	// Checked when the task is created, nil if it could not be checked
+	Feasibility *TaskFeasibility
+}
+
+// taskInfoTracker keeps the TaskInfo of every task tracked by the aggregator.
+// Entries are removed together with the rest of the task data by the garbage collector
+type taskInfoTracker struct {
+	tasks map[[32]byte]*TaskInfo
+	mutex sync.Mutex
+}
+
+func newTaskInfoTracker() *taskInfoTracker {
+	return &taskInfoTracker{
+		tasks: make(map[[32]byte]*TaskInfo),
+	}
+}
+
+func (t *taskInfoTracker) taskAdded(batchIdentifierHash [32]byte, task NewBatchTask, taskIndex uint32, quorumConfig QuorumConfig, createdAt time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.tasks[batchIdentifierHash] = &TaskInfo{
+		BatchIdentifierHash:   batchIdentifierHash,
+		BatchMerkleRoot:       task.BatchMerkleRoot,
+		SenderAddress:         task.SenderAddress,
+		TaskIndex:             taskIndex,
+		TaskCreatedBlock:      task.TaskCreatedBlock,
+		Version:               task.Version,
+		BatchDataPointer:      task.BatchDataPointer,
+		RespondToTaskFeeLimit: task.RespondToTaskFeeLimit,
+		QuorumConfig:          quorumConfig,
+		Status:                TaskStatusPending,
+		CreatedAt:             createdAt,
+		Signers:               make(map[eigentypes.OperatorId]time.Time),
+	}
+}
+
+func (t *taskInfoTracker) signatureAdded(batchIdentifierHash [32]byte, operatorId eigentypes.OperatorId) {
+	t.update(batchIdentifierHash, func(task *TaskInfo) {
+		task.Signers[operatorId] = time.Now()
+	})
+}
+
+func (t *taskInfoTracker) quorumReached(batchIdentifierHash [32]byte) {
+	t.update(batchIdentifierHash, func(task *TaskInfo) {
+		task.Status = TaskStatusQuorumReached
+		task.QuorumReachedAt = time.Now()
+	})
+}
+
+func (t *taskInfoTracker) feasibilityChecked(batchIdentifierHash [32]byte, feasibility TaskFeasibility) {
+	t.update(batchIdentifierHash, func(task *TaskInfo) {
+		task.Feasibility = &feasibility
+	})
+}
+
+func (t *taskInfoTracker) deferred(batchIdentifierHash [32]byte) {
+	t.update(batchIdentifierHash, func(task *TaskInfo) {
+		task.Status = TaskStatusDeferred
+	})
+}
+
+func (t *taskInfoTracker) gasPriceSet(batchIdentifierHash [32]byte, gasPrice string) {
+	t.update(batchIdentifierHash, func(task *TaskInfo) {
+		task.Attempts = append(task.Attempts, RespondToTaskAttempt{GasPrice: gasPrice, At: time.Now()})
+	})
+}
+
+// txSent sets the hash of the transaction sent with the last gas price
+func (t *taskInfoTracker) txSent(batchIdentifierHash [32]byte, txHash string) {
+	t.update(batchIdentifierHash, func(task *TaskInfo) {
+		if len(task.Attempts) > 0 {
+			task.Attempts[len(task.Attempts)-1].TxHash = txHash
+		}
+	})
+}
+
+func (t *taskInfoTracker) responded(batchIdentifierHash [32]byte, txHash string, effectiveGasPrice string) {
+	t.update(batchIdentifierHash, func(task *TaskInfo) {
+		task.Status = TaskStatusResponded
+		task.RespondedAt = time.Now()
+		task.TxHash = txHash
+		task.EffectiveGasPrice = effectiveGasPrice
+		task.Error = ""
+	})
+}
+
+// confirmed sets the transaction that verified the batch once it has enough confirmations
+func (t *taskInfoTracker) confirmed(batchIdentifierHash [32]byte, txHash string) {
+	t.update(batchIdentifierHash, func(task *TaskInfo) {
+		task.Status = TaskStatusConfirmed
+		task.ConfirmedAt = time.Now()
+		task.TxHash = txHash
+	})
+}
+
+func (t *taskInfoTracker) failed(batchIdentifierHash [32]byte, err error) {
+	t.update(batchIdentifierHash, func(task *TaskInfo) {
+		task.Status = TaskStatusFailed
+		task.Error = err.Error()
+	})
+}
+
+func (t *taskInfoTracker) remove(batchIdentifierHash [32]byte) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.tasks, batchIdentifierHash)
+}
+
+func (t *taskInfoTracker) update(batchIdentifierHash [32]byte, updateFunc func(task *TaskInfo)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	task, ok := t.tasks[batchIdentifierHash]
+	if !ok {
+		return
+	}
+	updateFunc(task)
+}
+
+// status returns the status of the task, without copying its TaskInfo
+func (t *taskInfoTracker) status(batchIdentifierHash [32]byte) (string, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	task, ok := t.tasks[batchIdentifierHash]
+	if !ok {
+		return "", false
+	}
+	return task.Status, true
+}
+
+// get returns a copy of the TaskInfo, safe to read without holding the mutex
+func (t *taskInfoTracker) get(batchIdentifierHash [32]byte) (TaskInfo, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	task, ok := t.tasks[batchIdentifierHash]
+	if !ok {
+		return TaskInfo{}, false
+	}
+	return copyTaskInfo(task), true
+}
+
+// list returns copies of the tracked tasks matching the filter, newest first
+func (t *taskInfoTracker) list(filter func(task *TaskInfo) bool) []TaskInfo {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	tasks := make([]TaskInfo, 0, len(t.tasks))
+	for _, task := range t.tasks {
+		if filter(task) {
+			tasks = append(tasks, copyTaskInfo(task))
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.After(tasks[j].CreatedAt)
+	})
+	return tasks
+}
+
+func copyTaskInfo(task *TaskInfo) TaskInfo {
+	taskCopy := *task
+	taskCopy.Signers = make(map[eigentypes.OperatorId]time.Time, len(task.Signers))
+	for operatorId, receivedAt := range task.Signers {
+		taskCopy.Signers[operatorId] = receivedAt
+	}
+	taskCopy.Attempts = append([]RespondToTaskAttempt(nil), task.Attempts...)
+	return taskCopy
+}
@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// DefaultTaskStoreFlushPeriod is how often the task store changes are written to disk
+const DefaultTaskStoreFlushPeriod = time.Second
+
+// StoredTask is the persisted form of a task tracked by the aggregator.
+// It holds everything needed to re-initialize the task in the BLS aggregation service after a restart.
+type StoredTask struct {
+	BatchIdentifierHash [32]byte  `json:"batch_identifier_hash"`
+	BatchMerkleRoot     [32]byte  `json:"batch_merkle_root"`
+	SenderAddress       [20]byte  `json:"sender_address"`
+	TaskCreatedBlock    uint32    `json:"task_created_block"`
+	CreatedAt           time.Time `json:"created_at"`
+	// Version of the NewBatch event, empty for tasks stored before V2 batches were tracked, which are all V3
+	Version               string   `json:"version,omitempty"`
+	BatchDataPointer      string   `json:"batch_data_pointer,omitempty"`
+	RespondToTaskFeeLimit *big.Int `json:"respond_to_task_fee_limit,omitempty"`
+}
+
+// newBatchTask returns the task to add back to the aggregator
+func (t StoredTask) newBatchTask() NewBatchTask {
+	version := t.Version
+	if version == "" {
+		version = NewBatchVersionV3
+	}
+	return NewBatchTask{
+		Version:               version,
+		BatchMerkleRoot:       t.BatchMerkleRoot,
+		SenderAddress:         t.SenderAddress,
+		TaskCreatedBlock:      t.TaskCreatedBlock,
+		BatchDataPointer:      t.BatchDataPointer,
+		RespondToTaskFeeLimit: t.RespondToTaskFeeLimit,
+	}
+}
+
+// TaskStore persists the tasks the aggregator is tracking, so pending tasks survive a restart
+type TaskStore interface {
+	SaveTask(task StoredTask) error
+	DeleteTask(batchIdentifierHash [32]byte) error
+	LoadTasks() ([]StoredTask, error)
+	// Flush persists the changes not written yet. Stores may write in the background, see FileTaskStore
+	Flush() error
+}
+
+// InMemoryTaskStore keeps tasks only in memory. Used when no task store path is configured, and in tests.
+type InMemoryTaskStore struct {
+	tasks map[[32]byte]StoredTask
+	mutex sync.Mutex
+}
+
+func NewInMemoryTaskStore() *InMemoryTaskStore {
+	return &InMemoryTaskStore{
+		tasks: make(map[[32]byte]StoredTask),
+	}
+}
+
+func (s *InMemoryTaskStore) SaveTask(task StoredTask) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tasks[task.BatchIdentifierHash] = task
+	return nil
+}
+
+func (s *InMemoryTaskStore) DeleteTask(batchIdentifierHash [32]byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.tasks, batchIdentifierHash)
+	return nil
+}
+
+func (s *InMemoryTaskStore) LoadTasks() ([]StoredTask, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	tasks := make([]StoredTask, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (s *InMemoryTaskStore) Flush() error {
+	return nil
+}
+
+// FileTaskStore keeps tasks in memory and mirrors them to a JSON file when flushed.
+// Saving and deleting don't touch the disk, as they run under the task mutex on every new task:
+// the aggregator flushes the store every DefaultTaskStoreFlushPeriod, so at most that much is lost on a crash
+type FileTaskStore struct {
+	tasks *jsonFileMap[StoredTask]
+}
+
+func NewFileTaskStore(path string) (*FileTaskStore, error) {
+	tasks, err := newJsonFileMap[StoredTask](path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task store: %w", err)
+	}
+	return &FileTaskStore{tasks: tasks}, nil
+}
+
+func (s *FileTaskStore) SaveTask(task StoredTask) error {
+	s.tasks.setDeferred(task.BatchIdentifierHash, task)
+	return nil
+}
+
+func (s *FileTaskStore) DeleteTask(batchIdentifierHash [32]byte) error {
+	s.tasks.deleteDeferred(batchIdentifierHash)
+	return nil
+}
+
+func (s *FileTaskStore) LoadTasks() ([]StoredTask, error) {
+	return s.tasks.values(), nil
+}
+
+func (s *FileTaskStore) Flush() error {
+	return s.tasks.flushIfDirty()
+}
+
+// Long-lived goroutine that periodically writes the task store changes to disk, and a last time on shutdown
+func (agg *Aggregator) FlushTaskStore(ctx context.Context) {
+	ticker := time.NewTicker(DefaultTaskStoreFlushPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := agg.taskStore.Flush(); err != nil {
+				agg.logger.Warn("Failed to flush task store on shutdown", "err", err)
+			}
+			return
+		case <-ticker.C:
+			if err := agg.taskStore.Flush(); err != nil {
+				agg.logger.Warn("Failed to flush task store, retrying on the next period", "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTaskStoreSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	store, err := NewFileTaskStore(path)
+	if err != nil {
+		t.Fatalf("Could not create task store: %v", err)
+	}
+
+	pendingTask := StoredTask{
+		BatchIdentifierHash: [32]byte{1},
+		BatchMerkleRoot:     [32]byte{2},
+		SenderAddress:       [20]byte{3},
+		TaskCreatedBlock:    100,
+		CreatedAt:           time.Now().UTC(),
+	}
+	respondedTask := StoredTask{
+		BatchIdentifierHash: [32]byte{4},
+		TaskCreatedBlock:    101,
+	}
+	if err := store.SaveTask(pendingTask); err != nil {
+		t.Fatalf("Could not save task: %v", err)
+	}
+	if err := store.SaveTask(respondedTask); err != nil {
+		t.Fatalf("Could not save task: %v", err)
+	}
+	if err := store.DeleteTask(respondedTask.BatchIdentifierHash); err != nil {
+		t.Fatalf("Could not delete task: %v", err)
+	}
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Could not flush task store: %v", err)
+	}
+
+	reopened, err := NewFileTaskStore(path)
+	if err != nil {
+		t.Fatalf("Could not reopen task store: %v", err)
+	}
+	tasks, err := reopened.LoadTasks()
+	if err != nil {
+		t.Fatalf("Could not load tasks: %v", err)
+	}
+
+	if len(tasks) != 1 {
+		t.Fatalf("Expected 1 task after reopening the store, got %d", len(tasks))
+	}
+	got := tasks[0]
+	if got.BatchIdentifierHash != pendingTask.BatchIdentifierHash ||
+		got.BatchMerkleRoot != pendingTask.BatchMerkleRoot ||
+		got.SenderAddress != pendingTask.SenderAddress ||
+		got.TaskCreatedBlock != pendingTask.TaskCreatedBlock ||
+		!got.CreatedAt.Equal(pendingTask.CreatedAt) {
+		t.Errorf("Recovered task does not match stored task, expected %+v, got %+v", pendingTask, got)
+	}
+}
+
+func TestFileTaskStoreWritesOnlyWhenFlushed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store, err := NewFileTaskStore(path)
+	if err != nil {
+		t.Fatalf("Could not create task store: %v", err)
+	}
+	if err := store.SaveTask(StoredTask{BatchIdentifierHash: [32]byte{1}}); err != nil {
+		t.Fatalf("Could not save task: %v", err)
+	}
+
+	// Saving must not write to disk, as it runs under the task mutex
+	reopened, _ := NewFileTaskStore(path)
+	if tasks, _ := reopened.LoadTasks(); len(tasks) != 0 {
+		t.Fatalf("Expected no task on disk before flushing, got %d", len(tasks))
+	}
+
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Could not flush task store: %v", err)
+	}
+	reopened, _ = NewFileTaskStore(path)
+	if tasks, _ := reopened.LoadTasks(); len(tasks) != 1 {
+		t.Fatalf("Expected 1 task on disk after flushing, got %d", len(tasks))
+	}
+}
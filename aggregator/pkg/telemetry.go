@@ -1,16 +1,17 @@
 package pkg
 
 import (
-	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
+	"strings"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/metrics"
 )
 
 type TraceMessage struct {
@@ -41,30 +42,43 @@ type TaskSentToEthereumMessage struct {
 	EffectiveGasPrice string `json:"effective_gas_price"`
 }
 
+// Telemetry reports the lifecycle of each task as OpenTelemetry spans, and as messages to its sinks:
+// the telemetry_api legacy HTTP endpoints, a JSON lines file or stdout.
+// No method blocks on the exporters
 type Telemetry struct {
-	client  http.Client
-	baseURL url.URL
-	logger  logging.Logger
+	sinks  []telemetrySink
+	tracer *taskTracer
+	logger logging.Logger
 }
 
-func NewTelemetry(serverAddress string, logger logging.Logger) *Telemetry {
-	client := http.Client{}
-
-	baseURL := url.URL{
-		Scheme: "http",
-		Host:   serverAddress,
+func NewTelemetry(aggregatorConfig *config.AggregatorConfig, aggregatorMetrics *metrics.Metrics, logger logging.Logger) (*Telemetry, error) {
+	sinks, err := newTelemetrySinks(aggregatorConfig, aggregatorMetrics, logger)
+	if err != nil {
+		return nil, err
+	}
+	telemetry := &Telemetry{
+		sinks:  sinks,
+		logger: logger,
 	}
-	logger.Info("[Telemetry] Starting Telemetry client.", "server_address",
-		serverAddress)
 
-	return &Telemetry{
-		client:  client,
-		baseURL: baseURL,
-		logger:  logger,
+	otelCollectorAddress := aggregatorConfig.Aggregator.OtelCollectorAddress
+	if otelCollectorAddress != "" {
+		tracer, err := newOtlpTaskTracer(otelCollectorAddress)
+		if err != nil {
+			return nil, fmt.Errorf("error creating OTLP exporter: %w", err)
+		}
+		telemetry.tracer = tracer
+		logger.Info("[Telemetry] Exporting traces to OpenTelemetry collector.", "otel_collector_address",
+			otelCollectorAddress)
 	}
+
+	return telemetry, nil
 }
 
-func (t *Telemetry) InitNewTrace(batchMerkleRoot [32]byte) {
+func (t *Telemetry) InitNewTrace(batchMerkleRoot [32]byte, batchIdentifierHash [32]byte) {
+	if t.tracer != nil {
+		t.tracer.taskCreated(batchMerkleRoot, batchIdentifierHash)
+	}
 	body := TraceMessage{
 		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
 	}
@@ -74,6 +88,9 @@ func (t *Telemetry) InitNewTrace(batchMerkleRoot [32]byte) {
 }
 
 func (t *Telemetry) LogOperatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte) {
+	if t.tracer != nil {
+		t.tracer.operatorResponse(batchMerkleRoot, operatorId)
+	}
 	body := OperatorResponseMessage{
 		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
 		OperatorId: fmt.Sprintf("0x%s", hex.EncodeToString(operatorId[:])),
@@ -84,6 +101,9 @@ func (t *Telemetry) LogOperatorResponse(batchMerkleRoot [32]byte, operatorId [32
 }
 
 func (t *Telemetry) LogQuorumReached(batchMerkleRoot [32]byte) {
+	if t.tracer != nil {
+		t.tracer.quorumReached(batchMerkleRoot)
+	}
 	body := QuorumReachedMessage{
 		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
 	}
@@ -93,6 +113,9 @@ func (t *Telemetry) LogQuorumReached(batchMerkleRoot [32]byte) {
 }
 
 func (t *Telemetry) LogTaskError(batchMerkleRoot [32]byte, taskError error) {
+	if t.tracer != nil {
+		t.tracer.taskError(batchMerkleRoot, taskError)
+	}
 	body := TaskErrorMessage{
 		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
 		TaskError:  taskError.Error(),
@@ -103,6 +126,9 @@ func (t *Telemetry) LogTaskError(batchMerkleRoot [32]byte, taskError error) {
 }
 
 func (t *Telemetry) TaskSetGasPrice(batchMerkleRoot [32]byte, gasPrice string) {
+	if t.tracer != nil {
+		t.tracer.gasPriceSet(batchMerkleRoot, gasPrice)
+	}
 	body := TaskSetGasPriceMessage{
 		MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
 		GasPrice:   gasPrice,
@@ -113,6 +139,9 @@ func (t *Telemetry) TaskSetGasPrice(batchMerkleRoot [32]byte, gasPrice string) {
 }
 
 func (t *Telemetry) TaskSentToEthereum(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string) {
+	if t.tracer != nil {
+		t.tracer.taskSent(batchMerkleRoot, txHash, effectiveGasPrice)
+	}
 	body := TaskSentToEthereumMessage{
 		MerkleRoot:        fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
 		TxHash:            txHash,
@@ -127,6 +156,9 @@ func (t *Telemetry) FinishTrace(batchMerkleRoot [32]byte) {
 	// In order to wait for all operator responses, even if the quorum is reached, this function has a delayed execution
 	go func() {
 		time.Sleep(10 * time.Second)
+		if t.tracer != nil {
+			t.tracer.finishTask(batchMerkleRoot)
+		}
 		body := TraceMessage{
 			MerkleRoot: fmt.Sprintf("0x%s", hex.EncodeToString(batchMerkleRoot[:])),
 		}
@@ -136,31 +168,39 @@ func (t *Telemetry) FinishTrace(batchMerkleRoot [32]byte) {
 	}()
 }
 
+// Shutdown sends the queued messages and exports the spans not sent yet
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	var err error
+	for _, sink := range t.sinks {
+		err = errors.Join(err, sink.close(ctx))
+	}
+	if t.tracer != nil {
+		err = errors.Join(err, t.tracer.shutdown(ctx))
+	}
+	return err
+}
+
+// sendTelemetryMessage hands the message to every sink, as sent to the telemetry_api endpoint
 func (t *Telemetry) sendTelemetryMessage(endpoint string, message interface{}) error {
+	if len(t.sinks) == 0 {
+		return nil
+	}
+
 	encodedBody, err := json.Marshal(message)
 	if err != nil {
 		t.logger.Warn("[Telemetry] Error marshalling JSON", "error", err)
 		return fmt.Errorf("error marshalling JSON: %w", err)
 	}
 
-	t.logger.Info("[Telemetry] Sending message.", "endpoint", endpoint, "message", message)
+	t.logger.Info("[Telemetry] Queueing message.", "endpoint", endpoint, "message", message)
 
-	fullURL := t.baseURL.ResolveReference(&url.URL{Path: endpoint})
-
-	resp, err := t.client.Post(fullURL.String(), "application/json", bytes.NewBuffer(encodedBody))
-	if err != nil {
-		t.logger.Warn("[Telemetry] Error sending POST request", "error", err)
-		return fmt.Errorf("error making POST request: %w", err)
+	event := TelemetryEvent{
+		Time:  time.Now(),
+		Event: strings.TrimPrefix(endpoint, "/api/"),
+		Data:  encodedBody,
 	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		t.logger.Warn("[Telemetry] Error reading response body", "error", err)
-		return fmt.Errorf("error reading response body: %w", err)
+	for _, sink := range t.sinks {
+		err = errors.Join(err, sink.send(event))
 	}
-
-	t.logger.Info("[Telemetry] Response received", "status", resp.Status, "response_body", string(respBody))
-
-	return nil
+	return err
 }
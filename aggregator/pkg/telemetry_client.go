@@ -0,0 +1,234 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+const (
+	DefaultTelemetryQueueSize      = 10000
+	DefaultTelemetryFlushSize      = 50
+	DefaultTelemetryFlushInterval  = 1 * time.Second
+	DefaultTelemetryRequestTimeout = 5 * time.Second
+)
+
+// TelemetryBatchEndpoint is the telemetry_api endpoint that applies a batch of aggregator events in order
+const TelemetryBatchEndpoint = "/api/aggregatorEvents"
+
+// telemetryMessage is an event of a batch, applied as if posted to /api/<event>
+type telemetryMessage struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type telemetryBatch struct {
+	Events []telemetryMessage `json:"events"`
+}
+
+// telemetryBatchResult is the telemetry_api response to a batch, with the index in the batch of the events it rejected
+type telemetryBatchResult struct {
+	Processed int `json:"processed"`
+	Errors    []struct {
+		Index int    `json:"index"`
+		Error string `json:"error"`
+	} `json:"errors"`
+}
+
+// telemetryHttpClient sends the telemetry messages to the telemetry_api in the background, so a slow or
+// unreachable server never blocks task handling. Messages are queued and dropped if the queue is full.
+// A single goroutine flushes them every flushInterval or once flushSize are queued, posting them in one request
+// to TelemetryBatchEndpoint, which applies them in order. The batch is retried with backoff, except on client errors
+type telemetryHttpClient struct {
+	client        *http.Client
+	baseURL       url.URL
+	queue         chan telemetryMessage
+	flushSize     int
+	flushInterval time.Duration
+	retryParams   *retry.RetryParams
+
+	// Protects queue from being written once closed
+	closed      bool
+	closedMutex sync.RWMutex
+	done        chan struct{}
+
+	metrics *metrics.Metrics
+	logger  logging.Logger
+}
+
+type telemetryHttpClientConfig struct {
+	QueueSize      int
+	FlushSize      int
+	FlushInterval  time.Duration
+	RequestTimeout time.Duration
+	RetryParams    *retry.RetryParams
+}
+
+func newTelemetryHttpClient(serverAddress string, clientConfig telemetryHttpClientConfig, aggregatorMetrics *metrics.Metrics, logger logging.Logger) *telemetryHttpClient {
+	if clientConfig.QueueSize <= 0 {
+		clientConfig.QueueSize = DefaultTelemetryQueueSize
+	}
+	if clientConfig.FlushSize <= 0 {
+		clientConfig.FlushSize = DefaultTelemetryFlushSize
+	}
+	if clientConfig.FlushInterval <= 0 {
+		clientConfig.FlushInterval = DefaultTelemetryFlushInterval
+	}
+	if clientConfig.RequestTimeout <= 0 {
+		clientConfig.RequestTimeout = DefaultTelemetryRequestTimeout
+	}
+	if clientConfig.RetryParams == nil {
+		clientConfig.RetryParams = retry.TelemetryRetryParams()
+	}
+
+	client := &telemetryHttpClient{
+		client: &http.Client{Timeout: clientConfig.RequestTimeout},
+		baseURL: url.URL{
+			Scheme: "http",
+			Host:   serverAddress,
+		},
+		queue:         make(chan telemetryMessage, clientConfig.QueueSize),
+		flushSize:     clientConfig.FlushSize,
+		flushInterval: clientConfig.FlushInterval,
+		retryParams:   clientConfig.RetryParams,
+		done:          make(chan struct{}),
+		metrics:       aggregatorMetrics,
+		logger:        logger,
+	}
+	go client.run()
+	return client
+}
+
+// enqueue queues the message without blocking. Returns false if it was dropped
+func (c *telemetryHttpClient) enqueue(event string, body []byte) bool {
+	c.closedMutex.RLock()
+	defer c.closedMutex.RUnlock()
+	if c.closed {
+		return false
+	}
+	select {
+	case c.queue <- telemetryMessage{Event: event, Data: body}:
+		c.metrics.SetTelemetryQueueSize(len(c.queue))
+		return true
+	default:
+		c.metrics.AddTelemetryMessages("dropped", 1)
+		return false
+	}
+}
+
+func (c *telemetryHttpClient) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	pending := make([]telemetryMessage, 0, c.flushSize)
+	for {
+		select {
+		case message, ok := <-c.queue:
+			if !ok {
+				c.flush(pending)
+				return
+			}
+			pending = append(pending, message)
+			if len(pending) < c.flushSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+		c.flush(pending)
+		pending = pending[:0]
+		c.metrics.SetTelemetryQueueSize(len(c.queue))
+	}
+}
+
+// flush posts the messages as one batch. Messages rejected by the server are counted as failed, and logged once per batch
+func (c *telemetryHttpClient) flush(messages []telemetryMessage) {
+	if len(messages) == 0 {
+		return
+	}
+	body, err := json.Marshal(telemetryBatch{Events: messages})
+	if err != nil {
+		c.metrics.AddTelemetryMessages("failed", len(messages))
+		c.logger.Warn("[Telemetry] Error marshalling batch", "error", err)
+		return
+	}
+
+	result, err := retry.RetryWithData(func() (*telemetryBatchResult, error) {
+		return c.post(body)
+	}, c.retryParams)
+	if err != nil {
+		c.metrics.AddTelemetryMessages("failed", len(messages))
+		c.logger.Warn("[Telemetry] Error sending batch", "messages", len(messages), "error", err)
+		return
+	}
+
+	c.metrics.AddTelemetryMessages("sent", len(messages)-len(result.Errors))
+	if len(result.Errors) > 0 {
+		firstError := result.Errors[0]
+		event := ""
+		if firstError.Index >= 0 && firstError.Index < len(messages) {
+			event = messages[firstError.Index].Event
+		}
+		c.metrics.AddTelemetryMessages("failed", len(result.Errors))
+		c.logger.Warn("[Telemetry] Messages rejected by the server", "rejected", len(result.Errors), "messages", len(messages),
+			"firstEvent", event, "firstError", firstError.Error)
+	}
+}
+
+func (c *telemetryHttpClient) post(body []byte) (*telemetryBatchResult, error) {
+	fullURL := c.baseURL.ResolveReference(&url.URL{Path: TelemetryBatchEndpoint})
+
+	resp, err := c.client.Post(fullURL.String(), "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("error making POST request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode >= 500 {
+		return nil, fmt.Errorf("telemetry server error %s: %s", resp.Status, string(respBody))
+	}
+	if resp.StatusCode >= 400 {
+		// The batch will not be accepted on a retry
+		return nil, retry.PermanentError{Inner: fmt.Errorf("telemetry request rejected %s: %s", resp.Status, string(respBody))}
+	}
+
+	var result telemetryBatchResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		// The events were applied, only the rejected ones are unknown
+		c.logger.Debug("[Telemetry] Could not decode batch response", "error", err)
+	}
+	c.logger.Debug("[Telemetry] Response received", "status", resp.Status, "processed", result.Processed)
+	return &result, nil
+}
+
+// close stops accepting messages and waits for the queued ones to be sent, until ctx is done
+func (c *telemetryHttpClient) close(ctx context.Context) error {
+	c.closedMutex.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.closedMutex.Unlock()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+func newTestTelemetryHttpClient(serverURL string, clientConfig telemetryHttpClientConfig) *telemetryHttpClient {
+	logger := logging.NewTextSLogger(io.Discard, nil)
+	clientConfig.RetryParams = &retry.RetryParams{
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     10 * time.Millisecond,
+		Multiplier:      2,
+		NumRetries:      3,
+	}
+	return newTelemetryHttpClient(strings.TrimPrefix(serverURL, "http://"), clientConfig,
+		metrics.NewMetrics("", prometheus.NewRegistry(), logger), logger)
+}
+
+func TestTelemetryClientDoesNotBlockOnSlowServer(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := newTestTelemetryHttpClient(server.URL, telemetryHttpClientConfig{
+		QueueSize:     2,
+		FlushSize:     1,
+		FlushInterval: time.Hour,
+	})
+
+	start := time.Now()
+	dropped := 0
+	for i := 0; i < 10; i++ {
+		if !client.enqueue("initTaskTrace", []byte("{}")) {
+			dropped++
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Enqueueing took %v, it should not wait for the server", elapsed)
+	}
+	// One message is being sent, two are queued
+	if dropped < 7 {
+		t.Errorf("Expected messages to be dropped once the queue is full, dropped %d", dropped)
+	}
+}
+
+func TestTelemetryClientSendsBatchesInOrder(t *testing.T) {
+	var requests int
+	var received []string
+	var mutex sync.Mutex
+	failures := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if r.URL.Path != TelemetryBatchEndpoint {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests++
+		if failures > 0 {
+			// The whole batch is retried
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var batch telemetryBatch
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		result := map[string]interface{}{"processed": len(batch.Events), "errors": []interface{}{}}
+		for i, event := range batch.Events {
+			if event.Event == "taskError" {
+				// Rejected events are reported, the others are applied
+				result["errors"] = []interface{}{map[string]interface{}{"index": i, "error": "Context not found"}}
+				continue
+			}
+			received = append(received, event.Event)
+		}
+		json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	client := newTestTelemetryHttpClient(server.URL, telemetryHttpClientConfig{
+		FlushSize:     4,
+		FlushInterval: time.Hour,
+	})
+	for _, event := range []string{"initTaskTrace", "quorumReached", "taskError", "finishTaskTrace"} {
+		client.enqueue(event, []byte("{}"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.close(ctx); err != nil {
+		t.Fatalf("Queued messages were not sent: %v", err)
+	}
+	if client.enqueue("initTaskTrace", []byte("{}")) {
+		t.Errorf("Closed client should not accept messages")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	// Two failed attempts and the one accepted, all four messages in each
+	if requests != 3 {
+		t.Errorf("Expected the four messages in one batch retried twice, got %d requests", requests)
+	}
+	expected := []string{"initTaskTrace", "quorumReached", "finishTaskTrace"}
+	if strings.Join(received, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, received)
+	}
+}
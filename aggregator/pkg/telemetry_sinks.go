@@ -0,0 +1,253 @@
+package pkg
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+// Telemetry sinks, set in the telemetry_sinks config
+const (
+	TelemetrySinkHttp   = "http"
+	TelemetrySinkFile   = "file"
+	TelemetrySinkStdout = "stdout"
+)
+
+const (
+	DefaultTelemetryFileMaxSize    = 100 * 1024 * 1024
+	DefaultTelemetryFileMaxBackups = 5
+)
+
+// TelemetryEvent is a telemetry message as written by the file and stdout sinks, one JSON object per line.
+// Event is the name of the telemetry_api endpoint the message is sent to, such as initTaskTrace
+type TelemetryEvent struct {
+	Time  time.Time       `json:"time"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// telemetrySink receives every telemetry message. send must not block on slow destinations
+type telemetrySink interface {
+	send(event TelemetryEvent) error
+	close(ctx context.Context) error
+}
+
+// newTelemetrySinks creates the sinks set in telemetry_sinks. If none is set, messages are sent to the
+// telemetry_api when telemetry_ip_port_address is set
+func newTelemetrySinks(aggregatorConfig *config.AggregatorConfig, aggregatorMetrics *metrics.Metrics, logger logging.Logger) ([]telemetrySink, error) {
+	sinkNames := aggregatorConfig.Aggregator.TelemetrySinks
+	if len(sinkNames) == 0 && aggregatorConfig.Aggregator.TelemetryIpPortAddress != "" {
+		sinkNames = []string{TelemetrySinkHttp}
+	}
+
+	sinks := make([]telemetrySink, 0, len(sinkNames))
+	for _, sinkName := range sinkNames {
+		switch sinkName {
+		case TelemetrySinkHttp:
+			serverAddress := aggregatorConfig.Aggregator.TelemetryIpPortAddress
+			if serverAddress == "" {
+				return nil, fmt.Errorf("telemetry_ip_port_address is required by the %s telemetry sink", sinkName)
+			}
+			sinks = append(sinks, newTelemetryHttpClient(serverAddress, telemetryHttpClientConfig{
+				QueueSize:      aggregatorConfig.Aggregator.TelemetryQueueSize,
+				FlushSize:      aggregatorConfig.Aggregator.TelemetryFlushSize,
+				FlushInterval:  aggregatorConfig.Aggregator.TelemetryFlushInterval,
+				RequestTimeout: aggregatorConfig.Aggregator.TelemetryRequestTimeout,
+			}, aggregatorMetrics, logger))
+			logger.Info("[Telemetry] Starting Telemetry client.", "server_address", serverAddress)
+		case TelemetrySinkFile:
+			filePath := aggregatorConfig.Aggregator.TelemetryFilePath
+			if filePath == "" {
+				return nil, fmt.Errorf("telemetry_file_path is required by the %s telemetry sink", sinkName)
+			}
+			sink, err := newFileTelemetrySink(filePath, aggregatorConfig.Aggregator.TelemetryFileMaxSize, aggregatorConfig.Aggregator.TelemetryFileMaxBackups)
+			if err != nil {
+				return nil, fmt.Errorf("error opening telemetry file: %w", err)
+			}
+			sinks = append(sinks, sink)
+			logger.Info("[Telemetry] Writing telemetry to file.", "telemetry_file_path", filePath)
+		case TelemetrySinkStdout:
+			sinks = append(sinks, newWriterTelemetrySink(os.Stdout))
+		default:
+			return nil, fmt.Errorf("unknown telemetry sink %q, expected %s, %s or %s", sinkName, TelemetrySinkHttp, TelemetrySinkFile, TelemetrySinkStdout)
+		}
+	}
+	return sinks, nil
+}
+
+// send queues the event. A full queue drops it without an error, the drop is counted by the telemetry messages metric
+func (c *telemetryHttpClient) send(event TelemetryEvent) error {
+	c.enqueue(event.Event, event.Data)
+	return nil
+}
+
+// writerTelemetrySink writes the events as JSON lines
+type writerTelemetrySink struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+func newWriterTelemetrySink(writer io.Writer) *writerTelemetrySink {
+	return &writerTelemetrySink{writer: writer}
+}
+
+func (s *writerTelemetrySink) send(event TelemetryEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err = s.writer.Write(append(line, '\n'))
+	return err
+}
+
+func (s *writerTelemetrySink) close(ctx context.Context) error {
+	return nil
+}
+
+// fileTelemetrySink writes the events as JSON lines to path. Once the file reaches maxSize it is rotated:
+// path is renamed to path.1, path.1 to path.2 and so on, keeping maxBackups rotated files
+type fileTelemetrySink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	mutex      sync.Mutex
+}
+
+func newFileTelemetrySink(path string, maxSize int64, maxBackups int) (*fileTelemetrySink, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultTelemetryFileMaxSize
+	}
+	if maxBackups <= 0 {
+		maxBackups = DefaultTelemetryFileMaxBackups
+	}
+	sink := &fileTelemetrySink{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// open opens path to append to it. The mutex must be held
+func (s *fileTelemetrySink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+// rotate shifts the rotated files and starts a new one. The mutex must be held.
+// If the files can't be shifted, the current file is reopened and kept growing, so events are not lost
+func (s *fileTelemetrySink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+	if err := s.shiftFiles(); err != nil {
+		return errors.Join(err, s.open())
+	}
+	return s.open()
+}
+
+// shiftFiles renames path to path.1 and each rotated file to the next one, dropping the oldest
+func (s *fileTelemetrySink) shiftFiles() error {
+	_ = os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxBackups))
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(s.path, s.path+".1")
+}
+
+func (s *fileTelemetrySink) send(event TelemetryEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return fmt.Errorf("telemetry file is closed")
+	}
+	var rotateErr error
+	if s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			rotateErr = fmt.Errorf("error rotating telemetry file: %w", err)
+			if s.file == nil {
+				return rotateErr
+			}
+		}
+	}
+	written, err := s.file.Write(line)
+	s.size += int64(written)
+	return errors.Join(rotateErr, err)
+}
+
+func (s *fileTelemetrySink) close(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// TelemetryFiles returns the files written by the file sink at path, oldest first
+func TelemetryFiles(path string) []string {
+	var files []string
+	for i := 1; ; i++ {
+		rotated := fmt.Sprintf("%s.%d", path, i)
+		if _, err := os.Stat(rotated); err != nil {
+			break
+		}
+		files = append([]string{rotated}, files...)
+	}
+	return append(files, path)
+}
+
+// ReadTelemetryEvents reads the events written by the file or stdout sinks. Lines that are not events,
+// such as the logs printed along with the stdout sink, are skipped
+func ReadTelemetryEvents(reader io.Reader) ([]TelemetryEvent, error) {
+	var events []TelemetryEvent
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event TelemetryEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.Event == "" {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
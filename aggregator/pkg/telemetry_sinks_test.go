@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func TestFileTelemetrySinkRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	sink, err := newFileTelemetrySink(path, 200, 2)
+	if err != nil {
+		t.Fatalf("Could not create file sink: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		if err := sink.send(TelemetryEvent{Event: "quorumReached", Data: []byte(`{"merkle_root":"0x01"}`)}); err != nil {
+			t.Fatalf("Could not write event: %v", err)
+		}
+	}
+	if err := sink.close(context.Background()); err != nil {
+		t.Fatalf("Could not close file sink: %v", err)
+	}
+
+	files := TelemetryFiles(path)
+	if len(files) != 3 {
+		t.Fatalf("Expected the file and 2 rotated files, got %v", files)
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Only 2 rotated files should be kept")
+	}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatalf("Could not stat %s: %v", file, err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("%s has %d bytes, more than the max size", file, info.Size())
+		}
+	}
+}
+
+func TestFileTelemetrySinkKeepsWritingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	// A non empty directory where the rotated file goes makes the rename fail
+	if err := os.MkdirAll(filepath.Join(path+".1", "blocked"), 0755); err != nil {
+		t.Fatalf("Could not block the rotated file: %v", err)
+	}
+	sink, err := newFileTelemetrySink(path, 50, 1)
+	if err != nil {
+		t.Fatalf("Could not create file sink: %v", err)
+	}
+	event := TelemetryEvent{Event: "quorumReached", Data: []byte(`{"merkle_root":"0x01"}`)}
+	if err := sink.send(event); err != nil {
+		t.Fatalf("Could not write event: %v", err)
+	}
+	if err := sink.send(event); err == nil {
+		t.Errorf("Expected the failed rotation to be reported")
+	}
+	if err := sink.close(context.Background()); err != nil {
+		t.Fatalf("Could not close file sink: %v", err)
+	}
+
+	// Both events are kept in the original file
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Could not open telemetry file: %v", err)
+	}
+	defer file.Close()
+	events, err := ReadTelemetryEvents(file)
+	if err != nil || len(events) != 2 {
+		t.Errorf("Expected both events in the telemetry file, got %d: %v", len(events), err)
+	}
+}
+
+func TestTelemetryTimelineFromSink(t *testing.T) {
+	var output bytes.Buffer
+	telemetry := &Telemetry{
+		sinks:  []telemetrySink{newWriterTelemetrySink(&output)},
+		logger: logging.NewTextSLogger(io.Discard, nil),
+	}
+	batchMerkleRoot, otherMerkleRoot := [32]byte{1}, [32]byte{2}
+
+	telemetry.InitNewTrace(batchMerkleRoot, [32]byte{})
+	telemetry.InitNewTrace(otherMerkleRoot, [32]byte{})
+	telemetry.LogOperatorResponse(batchMerkleRoot, [32]byte{3})
+	telemetry.LogQuorumReached(batchMerkleRoot)
+	telemetry.TaskSetGasPrice(batchMerkleRoot, "100")
+	telemetry.LogTaskError(otherMerkleRoot, errors.New("quorum not reached"))
+	telemetry.TaskSentToEthereum(batchMerkleRoot, "0xabc", "90")
+	// Lines that are not events, such as logs, are skipped
+	output.WriteString("not an event\n")
+
+	events, err := ReadTelemetryEvents(&output)
+	if err != nil {
+		t.Fatalf("Could not read events: %v", err)
+	}
+	timelines := BuildTaskTimelines(events)
+	if len(timelines) != 2 {
+		t.Fatalf("Expected 2 timelines, got %d", len(timelines))
+	}
+
+	expected := []string{
+		"task created",
+		"response of operator 0x0300000000000000000000000000000000000000000000000000000000000000",
+		"quorum reached",
+		"gas price set to 100 wei",
+		"respond to task tx 0xabc, effective gas price 90 wei",
+	}
+	timeline := timelines[0]
+	if timeline.BatchMerkleRoot != "0x0100000000000000000000000000000000000000000000000000000000000000" {
+		t.Fatalf("Unexpected first timeline %s", timeline.BatchMerkleRoot)
+	}
+	if len(timeline.Entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(timeline.Entries))
+	}
+	for i, entry := range timeline.Entries {
+		if entry.Description != expected[i] {
+			t.Errorf("Entry %d: expected %q, got %q", i, expected[i], entry.Description)
+		}
+	}
+	if last := timelines[1].Entries[len(timelines[1].Entries)-1]; last.Description != "error: quorum not reached" {
+		t.Errorf("Unexpected last entry of the failed task %q", last.Description)
+	}
+}
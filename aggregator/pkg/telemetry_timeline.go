@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TaskTimelineEntry is an event of a task, with a description of what happened
+type TaskTimelineEntry struct {
+	Time        time.Time
+	Event       string
+	Description string
+}
+
+// TaskTimeline is every event of a task, oldest first
+type TaskTimeline struct {
+	BatchMerkleRoot string
+	Entries         []TaskTimelineEntry
+}
+
+// Start is the time of the first event of the task
+func (t *TaskTimeline) Start() time.Time {
+	if len(t.Entries) == 0 {
+		return time.Time{}
+	}
+	return t.Entries[0].Time
+}
+
+// Fields of every telemetry message, only the ones of each event are set
+type telemetryEventData struct {
+	MerkleRoot        string `json:"merkle_root"`
+	OperatorId        string `json:"operator_id"`
+	GasPrice          string `json:"gas_price"`
+	TxHash            string `json:"tx_hash"`
+	EffectiveGasPrice string `json:"effective_gas_price"`
+	Error             string `json:"error"`
+}
+
+func describeTelemetryEvent(event string, data telemetryEventData) string {
+	switch event {
+	case "initTaskTrace":
+		return "task created"
+	case "operatorResponse":
+		return "response of operator " + data.OperatorId
+	case "quorumReached":
+		return "quorum reached"
+	case "aggregatorTaskSetGasPrice":
+		return fmt.Sprintf("gas price set to %s wei", data.GasPrice)
+	case "aggregatorTaskSent":
+		return fmt.Sprintf("respond to task tx %s, effective gas price %s wei", data.TxHash, data.EffectiveGasPrice)
+	case "taskError":
+		return "error: " + data.Error
+	case "finishTaskTrace":
+		return "trace finished"
+	default:
+		return event
+	}
+}
+
+// BuildTaskTimelines groups the events by batch merkle root. Timelines are sorted by their first event
+func BuildTaskTimelines(events []TelemetryEvent) []TaskTimeline {
+	timelinesByMerkleRoot := make(map[string]*TaskTimeline)
+	for _, event := range events {
+		var data telemetryEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil || data.MerkleRoot == "" {
+			continue
+		}
+		timeline, ok := timelinesByMerkleRoot[data.MerkleRoot]
+		if !ok {
+			timeline = &TaskTimeline{BatchMerkleRoot: data.MerkleRoot}
+			timelinesByMerkleRoot[data.MerkleRoot] = timeline
+		}
+		timeline.Entries = append(timeline.Entries, TaskTimelineEntry{
+			Time:        event.Time,
+			Event:       event.Event,
+			Description: describeTelemetryEvent(event.Event, data),
+		})
+	}
+
+	timelines := make([]TaskTimeline, 0, len(timelinesByMerkleRoot))
+	for _, timeline := range timelinesByMerkleRoot {
+		sort.SliceStable(timeline.Entries, func(i, j int) bool {
+			return timeline.Entries[i].Time.Before(timeline.Entries[j].Time)
+		})
+		timelines = append(timelines, *timeline)
+	}
+	sort.Slice(timelines, func(i, j int) bool {
+		if !timelines[i].Start().Equal(timelines[j].Start()) {
+			return timelines[i].Start().Before(timelines[j].Start())
+		}
+		return timelines[i].BatchMerkleRoot < timelines[j].BatchMerkleRoot
+	})
+	return timelines
+}
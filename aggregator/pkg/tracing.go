@@ -0,0 +1,221 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName         = "github.com/yetanotherco/aligned_layer/aggregator"
+	tracingServiceName = "aligned-aggregator"
+	// Tasks whose trace is never finished, such as evicted ones, must not keep their spans forever
+	maxOpenTaskTraces = DefaultMaxTrackedTasks
+)
+
+// Span attributes, shared by every span of a task
+const (
+	AttributeBatchMerkleRoot     = "aligned.batch_merkle_root"
+	AttributeBatchIdentifierHash = "aligned.batch_identifier_hash"
+	AttributeOperatorId          = "aligned.operator_id"
+	AttributeGasPrice            = "aligned.gas_price"
+	AttributeTxHash              = "aligned.tx_hash"
+	AttributeEffectiveGasPrice   = "aligned.effective_gas_price"
+)
+
+// taskTrace is the root span of a task, its children are the lifecycle events of the task
+type taskTrace struct {
+	span       trace.Span
+	ctx        context.Context
+	attributes []attribute.KeyValue
+	// Span of the gas price attempt being sent, ended by the next attempt or the final transaction
+	gasPriceSpan trace.Span
+	startedAt    time.Time
+}
+
+// taskTracer creates OpenTelemetry spans for the lifecycle of each task, keyed by batch merkle root
+// as the rest of the telemetry
+type taskTracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	traces   map[[32]byte]*taskTrace
+	mutex    sync.Mutex
+}
+
+// newOtlpTaskTracer exports the spans to the OTLP gRPC collector listening at collectorAddress
+func newOtlpTaskTracer(collectorAddress string) (*taskTracer, error) {
+	exporter, err := otlptracegrpc.New(context.Background(),
+		otlptracegrpc.WithEndpoint(collectorAddress),
+		otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	return newTaskTracer(sdktrace.NewBatchSpanProcessor(exporter)), nil
+}
+
+func newTaskTracer(spanProcessor sdktrace.SpanProcessor) *taskTracer {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(spanProcessor),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(tracingServiceName))),
+	)
+	return &taskTracer{
+		provider: provider,
+		tracer:   provider.Tracer(tracerName),
+		traces:   make(map[[32]byte]*taskTrace),
+	}
+}
+
+func taskAttributes(batchMerkleRoot [32]byte, batchIdentifierHash *[32]byte) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		attribute.String(AttributeBatchMerkleRoot, "0x"+hex.EncodeToString(batchMerkleRoot[:])),
+	}
+	if batchIdentifierHash != nil {
+		attributes = append(attributes, attribute.String(AttributeBatchIdentifierHash, "0x"+hex.EncodeToString(batchIdentifierHash[:])))
+	}
+	return attributes
+}
+
+// taskTrace returns the trace of the task. Tasks added before a restart have no trace,
+// their spans are created without parent. The mutex must be held
+func (t *taskTracer) taskTrace(batchMerkleRoot [32]byte) *taskTrace {
+	if task, ok := t.traces[batchMerkleRoot]; ok {
+		return task
+	}
+	return &taskTrace{
+		ctx:        context.Background(),
+		attributes: taskAttributes(batchMerkleRoot, nil),
+	}
+}
+
+// event records a lifecycle event of the task as a span of its own
+func (t *taskTracer) event(batchMerkleRoot [32]byte, name string, attributes ...attribute.KeyValue) {
+	t.mutex.Lock()
+	task := t.taskTrace(batchMerkleRoot)
+	t.mutex.Unlock()
+
+	_, span := t.tracer.Start(task.ctx, name, trace.WithAttributes(append(attributes, task.attributes...)...))
+	span.End()
+}
+
+func (t *taskTracer) taskCreated(batchMerkleRoot [32]byte, batchIdentifierHash [32]byte) {
+	attributes := taskAttributes(batchMerkleRoot, &batchIdentifierHash)
+	ctx, span := t.tracer.Start(context.Background(), "aggregator.task", trace.WithAttributes(attributes...))
+
+	t.mutex.Lock()
+	if len(t.traces) >= maxOpenTaskTraces {
+		t.dropOldestTrace()
+	}
+	if previous, ok := t.traces[batchMerkleRoot]; ok {
+		previous.end()
+	}
+	t.traces[batchMerkleRoot] = &taskTrace{
+		span:       span,
+		ctx:        ctx,
+		attributes: attributes,
+		startedAt:  time.Now(),
+	}
+	t.mutex.Unlock()
+
+	t.event(batchMerkleRoot, "aggregator.task_created")
+}
+
+// dropOldestTrace ends the oldest unfinished trace. The mutex must be held
+func (t *taskTracer) dropOldestTrace() {
+	var oldestMerkleRoot [32]byte
+	var oldest *taskTrace
+	for batchMerkleRoot, task := range t.traces {
+		if oldest == nil || task.startedAt.Before(oldest.startedAt) {
+			oldestMerkleRoot, oldest = batchMerkleRoot, task
+		}
+	}
+	if oldest != nil {
+		oldest.span.SetAttributes(attribute.Bool("aligned.trace_dropped", true))
+		oldest.end()
+		delete(t.traces, oldestMerkleRoot)
+	}
+}
+
+func (t *taskTracer) operatorResponse(batchMerkleRoot [32]byte, operatorId [32]byte) {
+	t.event(batchMerkleRoot, "aggregator.operator_response",
+		attribute.String(AttributeOperatorId, "0x"+hex.EncodeToString(operatorId[:])))
+}
+
+func (t *taskTracer) quorumReached(batchMerkleRoot [32]byte) {
+	t.event(batchMerkleRoot, "aggregator.quorum_reached")
+}
+
+// gasPriceSet starts the span of a new gas price attempt, ending the previous one
+func (t *taskTracer) gasPriceSet(batchMerkleRoot [32]byte, gasPrice string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	task := t.taskTrace(batchMerkleRoot)
+	if task.gasPriceSpan != nil {
+		task.gasPriceSpan.End()
+	}
+	_, task.gasPriceSpan = t.tracer.Start(task.ctx, "aggregator.gas_price_attempt",
+		trace.WithAttributes(append([]attribute.KeyValue{attribute.String(AttributeGasPrice, gasPrice)}, task.attributes...)...))
+	if task.span == nil {
+		// Not kept for tasks without trace, nothing would end it
+		task.gasPriceSpan.End()
+	}
+}
+
+func (t *taskTracer) taskSent(batchMerkleRoot [32]byte, txHash string, effectiveGasPrice string) {
+	t.mutex.Lock()
+	task := t.taskTrace(batchMerkleRoot)
+	if task.gasPriceSpan != nil {
+		task.gasPriceSpan.SetAttributes(attribute.String(AttributeTxHash, txHash))
+		task.gasPriceSpan.End()
+		task.gasPriceSpan = nil
+	}
+	t.mutex.Unlock()
+
+	t.event(batchMerkleRoot, "aggregator.respond_to_task_tx",
+		attribute.String(AttributeTxHash, txHash),
+		attribute.String(AttributeEffectiveGasPrice, effectiveGasPrice))
+}
+
+func (t *taskTracer) taskError(batchMerkleRoot [32]byte, taskError error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	task := t.taskTrace(batchMerkleRoot)
+	if task.gasPriceSpan != nil {
+		task.gasPriceSpan.SetStatus(codes.Error, taskError.Error())
+		task.gasPriceSpan.End()
+		task.gasPriceSpan = nil
+	}
+	if task.span != nil {
+		task.span.RecordError(taskError)
+		task.span.SetStatus(codes.Error, taskError.Error())
+	}
+}
+
+func (t *taskTracer) finishTask(batchMerkleRoot [32]byte) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if task, ok := t.traces[batchMerkleRoot]; ok {
+		task.end()
+		delete(t.traces, batchMerkleRoot)
+	}
+}
+
+func (task *taskTrace) end() {
+	if task.gasPriceSpan != nil {
+		task.gasPriceSpan.End()
+	}
+	task.span.End()
+}
+
+// shutdown exports the spans still buffered
+func (t *taskTracer) shutdown(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key string) (string, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == attribute.Key(key) {
+			return kv.Value.AsString(), true
+		}
+	}
+	return "", false
+}
+
+func TestTaskTracerSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := newTaskTracer(recorder)
+	batchMerkleRoot, batchIdentifierHash := [32]byte{1}, [32]byte{2}
+
+	tracer.taskCreated(batchMerkleRoot, batchIdentifierHash)
+	tracer.operatorResponse(batchMerkleRoot, [32]byte{3})
+	tracer.quorumReached(batchMerkleRoot)
+	tracer.gasPriceSet(batchMerkleRoot, "100")
+	tracer.gasPriceSet(batchMerkleRoot, "125")
+	tracer.taskSent(batchMerkleRoot, "0xabc", "120")
+	tracer.finishTask(batchMerkleRoot)
+
+	spans := recorder.Ended()
+	var root sdktrace.ReadOnlySpan
+	names := make(map[string]int)
+	for _, span := range spans {
+		names[span.Name()]++
+		if span.Name() == "aggregator.task" {
+			root = span
+		}
+	}
+	if root == nil {
+		t.Fatalf("Task span was not ended")
+	}
+	for name, count := range map[string]int{
+		"aggregator.task_created":       1,
+		"aggregator.operator_response":  1,
+		"aggregator.quorum_reached":     1,
+		"aggregator.gas_price_attempt":  2,
+		"aggregator.respond_to_task_tx": 1,
+	} {
+		if names[name] != count {
+			t.Errorf("Expected %d %s spans, got %d", count, name, names[name])
+		}
+	}
+
+	for _, span := range spans {
+		if span != root && span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("Span %s is not a child of the task span", span.Name())
+		}
+		if value, _ := spanAttribute(span, AttributeBatchMerkleRoot); value != "0x0100000000000000000000000000000000000000000000000000000000000000" {
+			t.Errorf("Span %s has batch merkle root %q", span.Name(), value)
+		}
+		if value, _ := spanAttribute(span, AttributeBatchIdentifierHash); value != "0x0200000000000000000000000000000000000000000000000000000000000000" {
+			t.Errorf("Span %s has batch identifier hash %q", span.Name(), value)
+		}
+	}
+	if len(tracer.traces) != 0 {
+		t.Errorf("Finished trace should have been removed")
+	}
+}
+
+func TestTaskTracerError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := newTaskTracer(recorder)
+	batchMerkleRoot := [32]byte{1}
+
+	tracer.taskCreated(batchMerkleRoot, [32]byte{2})
+	tracer.gasPriceSet(batchMerkleRoot, "100")
+	tracer.taskError(batchMerkleRoot, errors.New("receipt not found"))
+	tracer.finishTask(batchMerkleRoot)
+
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "aggregator.task", "aggregator.gas_price_attempt":
+			if span.Status().Code != codes.Error {
+				t.Errorf("Span %s should have an error status", span.Name())
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// DefaultWalletBalanceCheckPeriod is used when `wallet_balance_check_period` is not set
+const DefaultWalletBalanceCheckPeriod = time.Minute
+
+// setWalletMinBalance sets the balance under which the aggregator wallets are only used if no other one is available
+func (agg *Aggregator) setWalletMinBalance(aggregatorConfig *config.AggregatorConfig) {
+	if aggregatorConfig.Aggregator.WalletMinBalance <= 0 {
+		return
+	}
+	agg.avsWriter.Wallets.SetMinBalance(utils.EthToWei(aggregatorConfig.Aggregator.WalletMinBalance))
+}
+
+// checkWallets fails if any configured wallet is not accepted as sender by the service manager
+func (agg *Aggregator) checkWallets(ctx context.Context) error {
+	alignedAggregator, err := agg.avsWriter.AvsContractBindings.ServiceManager.AlignedAggregator(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		alignedAggregator, err = agg.avsWriter.AvsContractBindings.ServiceManagerFallback.AlignedAggregator(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return fmt.Errorf("failed to get the aggregator of the service manager: %w", err)
+		}
+	}
+	aggregatorSenders := make(map[common.Address]bool)
+	for _, wallet := range agg.avsWriter.Wallets.Wallets() {
+		if wallet.Address == alignedAggregator {
+			continue
+		}
+		allowed, err := agg.avsWriter.AvsContractBindings.ServiceManager.AggregatorSenders(&bind.CallOpts{Context: ctx}, wallet.Address)
+		if err != nil {
+			allowed, err = agg.avsWriter.AvsContractBindings.ServiceManagerFallback.AggregatorSenders(&bind.CallOpts{Context: ctx}, wallet.Address)
+			if err != nil {
+				return fmt.Errorf("failed to check if wallet %s is an aggregator sender: %w", wallet.Address.Hex(), err)
+			}
+		}
+		aggregatorSenders[wallet.Address] = allowed
+	}
+	if err := agg.avsWriter.Wallets.CheckWallets(alignedAggregator, aggregatorSenders); err != nil {
+		return err
+	}
+	for _, wallet := range agg.avsWriter.Wallets.Wallets() {
+		agg.logger.Info("Aggregator wallet", "address", wallet.Address.Hex())
+	}
+	return nil
+}
+
+// Long-lived goroutine that periodically fetches the balances of the aggregator wallets,
+// so the ones with low balance are skipped and reported
+func (agg *Aggregator) MonitorWalletBalances(ctx context.Context) {
+	checkPeriod := agg.AggregatorConfig.Aggregator.WalletBalanceCheckPeriod
+	if checkPeriod == 0 {
+		checkPeriod = DefaultWalletBalanceCheckPeriod
+	}
+
+	agg.avsWriter.RefreshWalletBalances(ctx)
+
+	ticker := time.NewTicker(checkPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			agg.avsWriter.RefreshWalletBalances(ctx)
+		}
+	}
+}
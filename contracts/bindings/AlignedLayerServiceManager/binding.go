@@ -83,7 +83,7 @@ type ISignatureUtilsSignatureWithSaltAndExpiry struct {
 
 // ContractAlignedLayerServiceManagerMetaData contains all meta data concerning the ContractAlignedLayerServiceManager contract.
 var ContractAlignedLayerServiceManagerMetaData = &bind.MetaData{
-	ABI: "[{\"type\":\"constructor\",\"inputs\":[{\"name\":\"__avsDirectory\",\"type\":\"address\",\"internalType\":\"contractIAVSDirectory\"},{\"name\":\"__rewardsCoordinator\",\"type\":\"address\",\"internalType\":\"contractIRewardsCoordinator\"},{\"name\":\"__registryCoordinator\",\"type\":\"address\",\"internalType\":\"contractIRegistryCoordinator\"},{\"name\":\"__stakeRegistry\",\"type\":\"address\",\"internalType\":\"contractIStakeRegistry\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"receive\",\"stateMutability\":\"payable\"},{\"type\":\"function\",\"name\":\"alignedAggregator\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"avsDirectory\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"balanceOf\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"batchersBalances\",\"inputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"batchesState\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"taskCreatedBlock\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"responded\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"respondToTaskFeeLimit\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"blsApkRegistry\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIBLSApkRegistry\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"checkPublicInput\",\"inputs\":[{\"name\":\"publicInput\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"hash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"pure\"},{\"type\":\"function\",\"name\":\"checkSignatures\",\"inputs\":[{\"name\":\"msgHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"referenceBlockNumber\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"params\",\"type\":\"tuple\",\"internalType\":\"structIBLSSignatureChecker.NonSignerStakesAndSignature\",\"components\":[{\"name\":\"nonSignerQuorumBitmapIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"nonSignerPubkeys\",\"type\":\"tuple[]\",\"internalType\":\"structBN254.G1Point[]\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"quorumApks\",\"type\":\"tuple[]\",\"internalType\":\"structBN254.G1Point[]\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"apkG2\",\"type\":\"tuple\",\"internalType\":\"structBN254.G2Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"},{\"name\":\"Y\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"}]},{\"name\":\"sigma\",\"type\":\"tuple\",\"internalType\":\"structBN254.G1Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"quorumApkIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"totalStakeIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"nonSignerStakeIndices\",\"type\":\"uint32[][]\",\"internalType\":\"uint32[][]\"}]}],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structIBLSSignatureChecker.QuorumStakeTotals\",\"components\":[{\"name\":\"signedStakeForQuorum\",\"type\":\"uint96[]\",\"internalType\":\"uint96[]\"},{\"name\":\"totalStakeForQuorum\",\"type\":\"uint96[]\",\"internalType\":\"uint96[]\"}]},{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"createAVSRewardsSubmission\",\"inputs\":[{\"name\":\"rewardsSubmissions\",\"type\":\"tuple[]\",\"internalType\":\"structIRewardsCoordinator.RewardsSubmission[]\",\"components\":[{\"name\":\"strategiesAndMultipliers\",\"type\":\"tuple[]\",\"internalType\":\"structIRewardsCoordinator.StrategyAndMultiplier[]\",\"components\":[{\"name\":\"strategy\",\"type\":\"address\",\"internalType\":\"contractIStrategy\"},{\"name\":\"multiplier\",\"type\":\"uint96\",\"internalType\":\"uint96\"}]},{\"name\":\"token\",\"type\":\"address\",\"internalType\":\"contractIERC20\"},{\"name\":\"amount\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"startTimestamp\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"duration\",\"type\":\"uint32\",\"internalType\":\"uint32\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createNewTask\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"batchDataPointer\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"respondToTaskFeeLimit\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"payable\"},{\"type\":\"function\",\"name\":\"delegation\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIDelegationManager\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"depositToBatcher\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"payable\"},{\"type\":\"function\",\"name\":\"deregisterOperatorFromAVS\",\"inputs\":[{\"name\":\"operator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"disableVerifier\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"disabledVerifiers\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"enableVerifier\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"getOperatorRestakedStrategies\",\"inputs\":[{\"name\":\"operator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"address[]\",\"internalType\":\"address[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getRestakeableStrategies\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address[]\",\"internalType\":\"address[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"initialize\",\"inputs\":[{\"name\":\"_initialOwner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"_rewardsInitiator\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"_alignedAggregator\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"_pauserRegistry\",\"type\":\"address\",\"internalType\":\"contractIPauserRegistry\"},{\"name\":\"_initialPausedStatus\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"initializeAggregator\",\"inputs\":[{\"name\":\"_alignedAggregator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"initializePauser\",\"inputs\":[{\"name\":\"_pauserRegistry\",\"type\":\"address\",\"internalType\":\"contractIPauserRegistry\"},{\"name\":\"_initialPausedStatus\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"isVerifierDisabled\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"owner\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pause\",\"inputs\":[{\"name\":\"newPausedStatus\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"pauseAll\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"paused\",\"inputs\":[{\"name\":\"index\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"paused\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pauserRegistry\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIPauserRegistry\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"registerOperatorToAVS\",\"inputs\":[{\"name\":\"operator\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"operatorSignature\",\"type\":\"tuple\",\"internalType\":\"structISignatureUtils.SignatureWithSaltAndExpiry\",\"components\":[{\"name\":\"signature\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"salt\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"expiry\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"registryCoordinator\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIRegistryCoordinator\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"renounceOwnership\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"respondToTaskV2\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"nonSignerStakesAndSignature\",\"type\":\"tuple\",\"internalType\":\"structIBLSSignatureChecker.NonSignerStakesAndSignature\",\"components\":[{\"name\":\"nonSignerQuorumBitmapIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"nonSignerPubkeys\",\"type\":\"tuple[]\",\"internalType\":\"structBN254.G1Point[]\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"quorumApks\",\"type\":\"tuple[]\",\"internalType\":\"structBN254.G1Point[]\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"apkG2\",\"type\":\"tuple\",\"internalType\":\"structBN254.G2Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"},{\"name\":\"Y\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"}]},{\"name\":\"sigma\",\"type\":\"tuple\",\"internalType\":\"structBN254.G1Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"quorumApkIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"totalStakeIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"nonSignerStakeIndices\",\"type\":\"uint32[][]\",\"internalType\":\"uint32[][]\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"rewardsInitiator\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"setAggregator\",\"inputs\":[{\"name\":\"_alignedAggregator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setDisabledVerifiers\",\"inputs\":[{\"name\":\"bitmap\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setPauserRegistry\",\"inputs\":[{\"name\":\"newPauserRegistry\",\"type\":\"address\",\"internalType\":\"contractIPauserRegistry\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setRewardsInitiator\",\"inputs\":[{\"name\":\"newRewardsInitiator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setStaleStakesForbidden\",\"inputs\":[{\"name\":\"value\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"stakeRegistry\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIStakeRegistry\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"staleStakesForbidden\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"transferOwnership\",\"inputs\":[{\"name\":\"newOwner\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"trySignatureAndApkVerification\",\"inputs\":[{\"name\":\"msgHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"apk\",\"type\":\"tuple\",\"internalType\":\"structBN254.G1Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"apkG2\",\"type\":\"tuple\",\"internalType\":\"structBN254.G2Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"},{\"name\":\"Y\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"}]},{\"name\":\"sigma\",\"type\":\"tuple\",\"internalType\":\"structBN254.G1Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]}],\"outputs\":[{\"name\":\"pairingSuccessful\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"siganatureIsValid\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"unpause\",\"inputs\":[{\"name\":\"newPausedStatus\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"updateAVSMetadataURI\",\"inputs\":[{\"name\":\"_metadataURI\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"verifyBatchInclusion\",\"inputs\":[{\"name\":\"proofCommitment\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"pubInputCommitment\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"provingSystemAuxDataCommitment\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"proofGeneratorAddr\",\"type\":\"bytes20\",\"internalType\":\"bytes20\"},{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"merkleProof\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"verificationDataBatchIndex\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"withdraw\",\"inputs\":[{\"name\":\"amount\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"BatchVerified\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"BatcherBalanceUpdated\",\"inputs\":[{\"name\":\"batcher\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newBalance\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Initialized\",\"inputs\":[{\"name\":\"version\",\"type\":\"uint8\",\"indexed\":false,\"internalType\":\"uint8\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"NewBatchV2\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"},{\"name\":\"taskCreatedBlock\",\"type\":\"uint32\",\"indexed\":false,\"internalType\":\"uint32\"},{\"name\":\"batchDataPointer\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"NewBatchV3\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"},{\"name\":\"taskCreatedBlock\",\"type\":\"uint32\",\"indexed\":false,\"internalType\":\"uint32\"},{\"name\":\"batchDataPointer\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"},{\"name\":\"respondToTaskFeeLimit\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"OwnershipTransferred\",\"inputs\":[{\"name\":\"previousOwner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newOwner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Paused\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newPausedStatus\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"PauserRegistrySet\",\"inputs\":[{\"name\":\"pauserRegistry\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"contractIPauserRegistry\"},{\"name\":\"newPauserRegistry\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"contractIPauserRegistry\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RewardsInitiatorUpdated\",\"inputs\":[{\"name\":\"prevRewardsInitiator\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"},{\"name\":\"newRewardsInitiator\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"StaleStakesForbiddenUpdate\",\"inputs\":[{\"name\":\"value\",\"type\":\"bool\",\"indexed\":false,\"internalType\":\"bool\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Unpaused\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newPausedStatus\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"VerifierDisabled\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"indexed\":true,\"internalType\":\"uint8\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"VerifierEnabled\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"indexed\":true,\"internalType\":\"uint8\"}],\"anonymous\":false},{\"type\":\"error\",\"name\":\"BatchAlreadyResponded\",\"inputs\":[{\"name\":\"batchIdentifierHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"type\":\"error\",\"name\":\"BatchAlreadySubmitted\",\"inputs\":[{\"name\":\"batchIdentifierHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"type\":\"error\",\"name\":\"BatchDoesNotExist\",\"inputs\":[{\"name\":\"batchIdentifierHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"type\":\"error\",\"name\":\"InsufficientFunds\",\"inputs\":[{\"name\":\"batcher\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"required\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"available\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"type\":\"error\",\"name\":\"InvalidAddress\",\"inputs\":[{\"name\":\"param\",\"type\":\"string\",\"internalType\":\"string\"}]},{\"type\":\"error\",\"name\":\"InvalidDepositAmount\",\"inputs\":[{\"name\":\"amount\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"type\":\"error\",\"name\":\"InvalidQuorumThreshold\",\"inputs\":[{\"name\":\"signedStake\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"requiredStake\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"type\":\"error\",\"name\":\"SenderIsNotAggregator\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"alignedAggregator\",\"type\":\"address\",\"internalType\":\"address\"}]}]",
+	ABI: "[{\"type\":\"constructor\",\"inputs\":[{\"name\":\"__avsDirectory\",\"type\":\"address\",\"internalType\":\"contractIAVSDirectory\"},{\"name\":\"__rewardsCoordinator\",\"type\":\"address\",\"internalType\":\"contractIRewardsCoordinator\"},{\"name\":\"__registryCoordinator\",\"type\":\"address\",\"internalType\":\"contractIRegistryCoordinator\"},{\"name\":\"__stakeRegistry\",\"type\":\"address\",\"internalType\":\"contractIStakeRegistry\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"receive\",\"stateMutability\":\"payable\"},{\"type\":\"function\",\"name\":\"QUORUM_THRESHOLD_PERCENTAGE\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"aggregatorSenders\",\"inputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"alignedAggregator\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"avsDirectory\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"balanceOf\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"batchersBalances\",\"inputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"batchesState\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"taskCreatedBlock\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"responded\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"respondToTaskFeeLimit\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"blsApkRegistry\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIBLSApkRegistry\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"checkPublicInput\",\"inputs\":[{\"name\":\"publicInput\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"hash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"pure\"},{\"type\":\"function\",\"name\":\"checkSignatures\",\"inputs\":[{\"name\":\"msgHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"referenceBlockNumber\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"params\",\"type\":\"tuple\",\"internalType\":\"structIBLSSignatureChecker.NonSignerStakesAndSignature\",\"components\":[{\"name\":\"nonSignerQuorumBitmapIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"nonSignerPubkeys\",\"type\":\"tuple[]\",\"internalType\":\"structBN254.G1Point[]\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"quorumApks\",\"type\":\"tuple[]\",\"internalType\":\"structBN254.G1Point[]\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"apkG2\",\"type\":\"tuple\",\"internalType\":\"structBN254.G2Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"},{\"name\":\"Y\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"}]},{\"name\":\"sigma\",\"type\":\"tuple\",\"internalType\":\"structBN254.G1Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"quorumApkIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"totalStakeIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"nonSignerStakeIndices\",\"type\":\"uint32[][]\",\"internalType\":\"uint32[][]\"}]}],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structIBLSSignatureChecker.QuorumStakeTotals\",\"components\":[{\"name\":\"signedStakeForQuorum\",\"type\":\"uint96[]\",\"internalType\":\"uint96[]\"},{\"name\":\"totalStakeForQuorum\",\"type\":\"uint96[]\",\"internalType\":\"uint96[]\"}]},{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"createAVSRewardsSubmission\",\"inputs\":[{\"name\":\"rewardsSubmissions\",\"type\":\"tuple[]\",\"internalType\":\"structIRewardsCoordinator.RewardsSubmission[]\",\"components\":[{\"name\":\"strategiesAndMultipliers\",\"type\":\"tuple[]\",\"internalType\":\"structIRewardsCoordinator.StrategyAndMultiplier[]\",\"components\":[{\"name\":\"strategy\",\"type\":\"address\",\"internalType\":\"contractIStrategy\"},{\"name\":\"multiplier\",\"type\":\"uint96\",\"internalType\":\"uint96\"}]},{\"name\":\"token\",\"type\":\"address\",\"internalType\":\"contractIERC20\"},{\"name\":\"amount\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"startTimestamp\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"duration\",\"type\":\"uint32\",\"internalType\":\"uint32\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"createNewTask\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"batchDataPointer\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"respondToTaskFeeLimit\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"payable\"},{\"type\":\"function\",\"name\":\"delegation\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIDelegationManager\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"depositToBatcher\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"payable\"},{\"type\":\"function\",\"name\":\"deregisterOperatorFromAVS\",\"inputs\":[{\"name\":\"operator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"disableVerifier\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"disabledVerifiers\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"enableVerifier\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"getOperatorRestakedStrategies\",\"inputs\":[{\"name\":\"operator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"address[]\",\"internalType\":\"address[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getRestakeableStrategies\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address[]\",\"internalType\":\"address[]\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"initialize\",\"inputs\":[{\"name\":\"_initialOwner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"_rewardsInitiator\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"_alignedAggregator\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"_pauserRegistry\",\"type\":\"address\",\"internalType\":\"contractIPauserRegistry\"},{\"name\":\"_initialPausedStatus\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"initializeAggregator\",\"inputs\":[{\"name\":\"_alignedAggregator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"initializePauser\",\"inputs\":[{\"name\":\"_pauserRegistry\",\"type\":\"address\",\"internalType\":\"contractIPauserRegistry\"},{\"name\":\"_initialPausedStatus\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"isVerifierDisabled\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"owner\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pause\",\"inputs\":[{\"name\":\"newPausedStatus\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"pauseAll\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"paused\",\"inputs\":[{\"name\":\"index\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"paused\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pauserRegistry\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIPauserRegistry\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"registerOperatorToAVS\",\"inputs\":[{\"name\":\"operator\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"operatorSignature\",\"type\":\"tuple\",\"internalType\":\"structISignatureUtils.SignatureWithSaltAndExpiry\",\"components\":[{\"name\":\"signature\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"salt\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"expiry\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"registryCoordinator\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIRegistryCoordinator\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"renounceOwnership\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"respondToTaskV2\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"nonSignerStakesAndSignature\",\"type\":\"tuple\",\"internalType\":\"structIBLSSignatureChecker.NonSignerStakesAndSignature\",\"components\":[{\"name\":\"nonSignerQuorumBitmapIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"nonSignerPubkeys\",\"type\":\"tuple[]\",\"internalType\":\"structBN254.G1Point[]\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"quorumApks\",\"type\":\"tuple[]\",\"internalType\":\"structBN254.G1Point[]\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"apkG2\",\"type\":\"tuple\",\"internalType\":\"structBN254.G2Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"},{\"name\":\"Y\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"}]},{\"name\":\"sigma\",\"type\":\"tuple\",\"internalType\":\"structBN254.G1Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"quorumApkIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"totalStakeIndices\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"nonSignerStakeIndices\",\"type\":\"uint32[][]\",\"internalType\":\"uint32[][]\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"rewardsInitiator\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"setAggregator\",\"inputs\":[{\"name\":\"_alignedAggregator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setAggregatorSender\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"allowed\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setDisabledVerifiers\",\"inputs\":[{\"name\":\"bitmap\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setPauserRegistry\",\"inputs\":[{\"name\":\"newPauserRegistry\",\"type\":\"address\",\"internalType\":\"contractIPauserRegistry\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setRewardsInitiator\",\"inputs\":[{\"name\":\"newRewardsInitiator\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setStaleStakesForbidden\",\"inputs\":[{\"name\":\"value\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"stakeRegistry\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIStakeRegistry\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"staleStakesForbidden\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"transferOwnership\",\"inputs\":[{\"name\":\"newOwner\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"trySignatureAndApkVerification\",\"inputs\":[{\"name\":\"msgHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"apk\",\"type\":\"tuple\",\"internalType\":\"structBN254.G1Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"name\":\"apkG2\",\"type\":\"tuple\",\"internalType\":\"structBN254.G2Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"},{\"name\":\"Y\",\"type\":\"uint256[2]\",\"internalType\":\"uint256[2]\"}]},{\"name\":\"sigma\",\"type\":\"tuple\",\"internalType\":\"structBN254.G1Point\",\"components\":[{\"name\":\"X\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"Y\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]}],\"outputs\":[{\"name\":\"pairingSuccessful\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"siganatureIsValid\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"unpause\",\"inputs\":[{\"name\":\"newPausedStatus\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"updateAVSMetadataURI\",\"inputs\":[{\"name\":\"_metadataURI\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"verifyBatchInclusion\",\"inputs\":[{\"name\":\"proofCommitment\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"pubInputCommitment\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"provingSystemAuxDataCommitment\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"proofGeneratorAddr\",\"type\":\"bytes20\",\"internalType\":\"bytes20\"},{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"},{\"name\":\"merkleProof\",\"type\":\"bytes\",\"internalType\":\"bytes\"},{\"name\":\"verificationDataBatchIndex\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"withdraw\",\"inputs\":[{\"name\":\"amount\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"AggregatorSenderUpdated\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"allowed\",\"type\":\"bool\",\"indexed\":false,\"internalType\":\"bool\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"BatchVerified\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"BatcherBalanceUpdated\",\"inputs\":[{\"name\":\"batcher\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newBalance\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Initialized\",\"inputs\":[{\"name\":\"version\",\"type\":\"uint8\",\"indexed\":false,\"internalType\":\"uint8\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"NewBatchV2\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"},{\"name\":\"taskCreatedBlock\",\"type\":\"uint32\",\"indexed\":false,\"internalType\":\"uint32\"},{\"name\":\"batchDataPointer\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"NewBatchV3\",\"inputs\":[{\"name\":\"batchMerkleRoot\",\"type\":\"bytes32\",\"indexed\":true,\"internalType\":\"bytes32\"},{\"name\":\"senderAddress\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"},{\"name\":\"taskCreatedBlock\",\"type\":\"uint32\",\"indexed\":false,\"internalType\":\"uint32\"},{\"name\":\"batchDataPointer\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"},{\"name\":\"respondToTaskFeeLimit\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"OwnershipTransferred\",\"inputs\":[{\"name\":\"previousOwner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newOwner\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Paused\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newPausedStatus\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"PauserRegistrySet\",\"inputs\":[{\"name\":\"pauserRegistry\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"contractIPauserRegistry\"},{\"name\":\"newPauserRegistry\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"contractIPauserRegistry\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RewardsInitiatorUpdated\",\"inputs\":[{\"name\":\"prevRewardsInitiator\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"},{\"name\":\"newRewardsInitiator\",\"type\":\"address\",\"indexed\":false,\"internalType\":\"address\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"StaleStakesForbiddenUpdate\",\"inputs\":[{\"name\":\"value\",\"type\":\"bool\",\"indexed\":false,\"internalType\":\"bool\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Unpaused\",\"inputs\":[{\"name\":\"account\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"newPausedStatus\",\"type\":\"uint256\",\"indexed\":false,\"internalType\":\"uint256\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"VerifierDisabled\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"indexed\":true,\"internalType\":\"uint8\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"VerifierEnabled\",\"inputs\":[{\"name\":\"verifierIdx\",\"type\":\"uint8\",\"indexed\":true,\"internalType\":\"uint8\"}],\"anonymous\":false},{\"type\":\"error\",\"name\":\"BatchAlreadyResponded\",\"inputs\":[{\"name\":\"batchIdentifierHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"type\":\"error\",\"name\":\"BatchAlreadySubmitted\",\"inputs\":[{\"name\":\"batchIdentifierHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"type\":\"error\",\"name\":\"BatchDoesNotExist\",\"inputs\":[{\"name\":\"batchIdentifierHash\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"type\":\"error\",\"name\":\"InsufficientFunds\",\"inputs\":[{\"name\":\"batcher\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"required\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"available\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"type\":\"error\",\"name\":\"InvalidAddress\",\"inputs\":[{\"name\":\"param\",\"type\":\"string\",\"internalType\":\"string\"}]},{\"type\":\"error\",\"name\":\"InvalidDepositAmount\",\"inputs\":[{\"name\":\"amount\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"type\":\"error\",\"name\":\"InvalidQuorumThreshold\",\"inputs\":[{\"name\":\"signedStake\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"requiredStake\",\"type\":\"uint256\",\"internalType\":\"uint256\"}]},{\"type\":\"error\",\"name\":\"SenderIsNotAggregator\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"alignedAggregator\",\"type\":\"address\",\"internalType\":\"address\"}]}]",
 	Bin: "0x6101806040523480156200001257600080fd5b506040516200622b3803806200622b833981016040819052620000359162000419565b6001600160a01b0380851660805280841660a05280831660c052811660e05281848482846200006362000341565b50505050806001600160a01b0316610100816001600160a01b031681525050806001600160a01b031663683048356040518163ffffffff1660e01b8152600401602060405180830381865afa158015620000c1573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190620000e7919062000481565b6001600160a01b0316610120816001600160a01b031681525050806001600160a01b0316635df459466040518163ffffffff1660e01b8152600401602060405180830381865afa15801562000140573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019062000166919062000481565b6001600160a01b0316610140816001600160a01b031681525050610120516001600160a01b031663df5cf7236040518163ffffffff1660e01b8152600401602060405180830381865afa158015620001c2573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190620001e8919062000481565b6001600160a01b0390811661016052851690506200023d57604051630b0f5aa160e11b815260206004820152600c60248201526b6176734469726563746f727960a01b60448201526064015b60405180910390fd5b6001600160a01b0383166200028b57604051630b0f5aa160e11b81526020600482015260126024820152713932bbb0b93239a1b7b7b93234b730ba37b960711b604482015260640162000234565b6001600160a01b038216620002e457604051630b0f5aa160e11b815260206004820152601360248201527f7265676973747279436f6f7264696e61746f7200000000000000000000000000604482015260640162000234565b6001600160a01b0381166200032d57604051630b0f5aa160e11b815260206004820152600d60248201526c7374616b65526567697374727960981b604482015260640162000234565b6200033762000341565b50505050620004a8565b600054610100900460ff1615620003ab5760405162461bcd60e51b815260206004820152602760248201527f496e697469616c697a61626c653a20636f6e747261637420697320696e697469604482015266616c697a696e6760c81b606482015260840162000234565b60005460ff9081161015620003fe576000805460ff191660ff9081179091556040519081527f7f26b83ff96e1f2b6a682f133852f6798a09c465da95921460cefb38474024989060200160405180910390a15b565b6001600160a01b03811681146200041657600080fd5b50565b600080600080608085870312156200043057600080fd5b84516200043d8162000400565b6020860151909450620004508162000400565b6040860151909350620004638162000400565b6060860151909250620004768162000400565b939692955090935050565b6000602082840312156200049457600080fd5b8151620004a18162000400565b9392505050565b60805160a05160c05160e05161010051610120516101405161016051615c75620005b66000396000818161082e0152611d6101526000818161055f0152611f740152600081816105930152818161216101526123510152600081816105fa0152818161156701528181611a2701528181611bce0152611e1501526000818161129f015281816113f0015281816114870152818161301a0152818161319301526132320152600081816110c601528181611155015281816111d5015281816127b10152818161287d01528181612f5501526130ee0152600081816138a9015281816139650152613a480152600081816105c401528181612805015281816128d901526129580152615c756000f3fe6080604052600436106102815760003560e01c8063800fb61f1161014f578063d66eaabd116100c1578063f7013ef61161007a578063f7013ef6146108c8578063f9120af6146108e8578063fabc1cbc14610908578063fc299dee14610928578063fce36c7d14610948578063fd4c3b7c1461096857600080fd5b8063d66eaabd14610809578063df5cf7231461081c578063e481af9d14610850578063ea5ca34b14610865578063f2fde38b1461087b578063f474b5201461089b57600080fd5b8063a364f4da11610113578063a364f4da14610705578063a98fb35514610725578063ab21739a14610745578063b099627e14610765578063b753645e146107cf578063b98d0908146107ef57600080fd5b8063800fb61f14610667578063886f1195146106875780638da5cb5b146106a757806395c6d604146106c55780639926ee7d146106e557600080fd5b80634223d551116101f35780635df45946116101ac5780635df459461461054d57806368304835146105815780636b3aa72e146105b55780636d14a987146105e857806370a082311461061c578063715018a61461065257600080fd5b80634223d551146104705780634a5bf632146104835780634ae07c37146104bb578063595c6a67146104e95780635ac86ab7146104fe5780635c975abb1461052e57600080fd5b806318daeeaf1161024557806318daeeaf146103a35780632585b25b146103c35780632e1a7d4d146103e357806333cfb7b7146104035780633bc28c8c14610430578063416c7e5e1461045057600080fd5b806306045a91146102c857806310d67a2f146102fd578063136439dd1461031d578063137122b51461033d578063171f1d5b1461036c57600080fd5b366102c35760fc546005906020908116036102b75760405162461bcd60e51b81526004016102ae90614aab565b60405180910390fd5b6102c13334610988565b005b600080fd5b3480156102d457600080fd5b506102e86102e3366004614c06565b610a18565b60405190151581526020015b60405180910390f35b34801561030957600080fd5b506102c1610318366004614ca7565b610b3a565b34801561032957600080fd5b506102c1610338366004614cc4565b610bed565b34801561034957600080fd5b506102e8610358366004614cec565b60cc54600160ff9092169190911b16151590565b34801561037857600080fd5b5061038c610387366004614dc9565b610d2c565b6040805192151583529015156020830152016102f4565b3480156103af57600080fd5b506102c16103be366004614cec565b610eb6565b3480156103cf57600080fd5b506102c16103de366004614e1a565b610efe565b3480156103ef57600080fd5b506102c16103fe366004614cc4565b610fa0565b34801561040f57600080fd5b5061042361041e366004614ca7565b6110a1565b6040516102f49190614e46565b34801561043c57600080fd5b506102c161044b366004614ca7565b611554565b34801561045c57600080fd5b506102c161046b366004614ea1565b611565565b6102c161047e366004614ca7565b61169c565b34801561048f57600080fd5b5060cb546104a3906001600160a01b031681565b6040516001600160a01b0390911681526020016102f4565b3480156104c757600080fd5b506104db6104d636600461517c565b6116d2565b6040516102f4929190615217565b3480156104f557600080fd5b506102c1612606565b34801561050a57600080fd5b506102e8610519366004614cec565b60fc54600160ff9092169190911b9081161490565b34801561053a57600080fd5b5060fc545b6040519081526020016102f4565b34801561055957600080fd5b506104a37f000000000000000000000000000000000000000000000000000000000000000081565b34801561058d57600080fd5b506104a37f000000000000000000000000000000000000000000000000000000000000000081565b3480156105c157600080fd5b507f00000000000000000000000000000000000000000000000000000000000000006104a3565b3480156105f457600080fd5b506104a37f000000000000000000000000000000000000000000000000000000000000000081565b34801561062857600080fd5b5061053f610637366004614ca7565b6001600160a01b0316600090815260ca602052604090205490565b34801561065e57600080fd5b506102c16126cd565b34801561067357600080fd5b506102c1610682366004614ca7565b6126e1565b34801561069357600080fd5b5060fb546104a3906001600160a01b031681565b3480156106b357600080fd5b506033546001600160a01b03166104a3565b3480156106d157600080fd5b506102e86106e03660046152a8565b612781565b3480156106f157600080fd5b506102c16107003660046152f3565b6127a6565b34801561071157600080fd5b506102c1610720366004614ca7565b612872565b34801561073157600080fd5b506102c161074036600461539e565b612939565b34801561075157600080fd5b506102c16107603660046153ee565b61298d565b34801561077157600080fd5b506107ad610780366004614cc4565b60c9602052600090815260409020805460019091015463ffffffff821691640100000000900460ff169083565b6040805163ffffffff90941684529115156020840152908201526060016102f4565b3480156107db57600080fd5b506102c16107ea366004614cc4565b612d5f565b3480156107fb57600080fd5b506097546102e89060ff1681565b6102c1610817366004615415565b612d6c565b34801561082857600080fd5b506104a37f000000000000000000000000000000000000000000000000000000000000000081565b34801561085c57600080fd5b50610423612f4f565b34801561087157600080fd5b5061053f60cc5481565b34801561088757600080fd5b506102c1610896366004614ca7565b6132fb565b3480156108a757600080fd5b5061053f6108b6366004614ca7565b60ca6020526000908152604090205481565b3480156108d457600080fd5b506102c16108e3366004615467565b613371565b3480156108f457600080fd5b506102c1610903366004614ca7565b61354a565b34801561091457600080fd5b506102c1610923366004614cc4565b613574565b34801561093457600080fd5b506065546104a3906001600160a01b031681565b34801561095457600080fd5b506102c16109633660046154cb565b6136d0565b34801561097457600080fd5b506102c1610983366004614cec565b613a7f565b806000036109ac57604051632097692160e11b8152600481018290526024016102ae565b6001600160a01b038216600090815260ca6020526040812080548392906109d4908490615555565b90915550506001600160a01b038216600081815260ca6020908152604091829020549151918252600080516020615c00833981519152910160405180910390a25050565b60fc54600090600290600490811603610a435760405162461bcd60e51b81526004016102ae90614aab565b60006001600160a01b038416610a5a575085610a86565b8684604051602001610a6d929190615568565b6040516020818303038152906040528051906020012090505b600081815260c9602052604081205463ffffffff169003610aab576000925050610b2d565b600081815260c96020526040902054640100000000900460ff16610ad3576000925050610b2d565b60408051602081018d90529081018b9052606081018a90526001600160601b03198916608082015260009060940160408051601f1981840301815291905280516020820120909150610b27888a838a613ac6565b94505050505b5098975050505050505050565b60fb60009054906101000a90046001600160a01b03166001600160a01b031663eab66d7a6040518163ffffffff1660e01b8152600401602060405180830381865afa158015610b8d573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610bb19190615583565b6001600160a01b0316336001600160a01b031614610be15760405162461bcd60e51b81526004016102ae906155a0565b610bea81613ade565b50565b60fb5460405163237dfb4760e11b81523360048201526001600160a01b03909116906346fbf68e90602401602060405180830381865afa158015610c35573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610c5991906155ea565b610c755760405162461bcd60e51b81526004016102ae90615607565b60fc5481811614610cee5760405162461bcd60e51b815260206004820152603860248201527f5061757361626c652e70617573653a20696e76616c696420617474656d70742060448201527f746f20756e70617573652066756e6374696f6e616c697479000000000000000060648201526084016102ae565b60fc81905560405181815233907fab40a374bc51de372200a8bc981af8c9ecdc08dfdaef0bb6e09f88f3c616ef3d906020015b60405180910390a250565b60008060007f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f000000187876000015188602001518860000151600060028110610d7457610d7461564f565b60200201518951600160200201518a60200151600060028110610d9957610d9961564f565b60200201518b60200151600160028110610db557610db561564f565b602090810291909101518c518d830151604051610e129a99989796959401988952602089019790975260408801959095526060870193909352608086019190915260a085015260c084015260e08301526101008201526101200190565b6040516020818303038152906040528051906020012060001c610e359190615665565b9050610ea8610e4e610e478884613bd5565b8690613c66565b610e56613cfb565b610e9e610e8f85610e89604080518082018252600080825260209182015281518083019092526001825260029082015290565b90613bd5565b610e988c613dbb565b90613c66565b886201d4c0613e4a565b909890975095505050505050565b610ebe614064565b60cc8054600160ff841690811b199091169091556040517f5f52704e8e0190647930ccde0e43e14e89902d7d8c49c5f9e2544029f45ec12a90600090a250565b600054600390610100900460ff16158015610f20575060005460ff8083169116105b610f3c5760405162461bcd60e51b81526004016102ae90615687565b6000805461ffff191660ff831617610100179055610f5a83836140be565b6000805461ff001916905560405160ff821681527f7f26b83ff96e1f2b6a682f133852f6798a09c465da95921460cefb38474024989060200160405180910390a1505050565b60fc54600390600890811603610fc85760405162461bcd60e51b81526004016102ae90614aab565b33600090815260ca60205260409020548211156110195733600081815260ca602052604090819020549051632e2a182f60e11b815260048101929092526024820184905260448201526064016102ae565b33600090815260ca6020526040812080548492906110389084906156d5565b909155505033600081815260ca6020908152604091829020549151918252600080516020615c00833981519152910160405180910390a2604051339083156108fc029084906000818181858888f1935050505015801561109c573d6000803e3d6000fd5b505050565b6040516309aa152760e11b81526001600160a01b0382811660048301526060916000917f000000000000000000000000000000000000000000000000000000000000000016906313542a4e90602401602060405180830381865afa15801561110d573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061113191906156e8565b60405163871ef04960e01b8152600481018290529091506000906001600160a01b037f0000000000000000000000000000000000000000000000000000000000000000169063871ef04990602401602060405180830381865afa15801561119c573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906111c09190615701565b90506001600160c01b038116158061125a57507f00000000000000000000000000000000000000000000000000000000000000006001600160a01b0316639aa1653d6040518163ffffffff1660e01b8152600401602060405180830381865afa158015611231573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190611255919061572a565b60ff16155b1561127a5760408051600080825260208201909252905b50949350505050565b600061128e826001600160c01b03166141a4565b90506000805b825181101561135a577f00000000000000000000000000000000000000000000000000000000000000006001600160a01b0316633ca5a5f58483815181106112de576112de61564f565b01602001516040516001600160e01b031960e084901b16815260f89190911c6004820152602401602060405180830381865afa158015611322573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061134691906156e8565b6113509083615555565b9150600101611294565b506000816001600160401b0381111561137557611375614ae2565b60405190808252806020026020018201604052801561139e578160200160208202803683370190505b5090506000805b84518110156115475760008582815181106113c2576113c261564f565b0160200151604051633ca5a5f560e01b815260f89190911c6004820181905291506000906001600160a01b037f00000000000000000000000000000000000000000000000000000000000000001690633ca5a5f590602401602060405180830381865afa158015611437573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061145b91906156e8565b905060005b8181101561153c576040516356e4026d60e11b815260ff84166004820152602481018290527f00000000000000000000000000000000000000000000000000000000000000006001600160a01b03169063adc804da906044016040805180830381865afa1580156114d5573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906114f9919061575c565b6000015186868151811061150f5761150f61564f565b6001600160a01b0390921660209283029190910190910152846115318161579d565b955050600101611460565b5050506001016113a5565b5090979650505050505050565b61155c614064565b610bea81614266565b7f00000000000000000000000000000000000000000000000000000000000000006001600160a01b0316638da5cb5b6040518163ffffffff1660e01b8152600401602060405180830381865afa1580156115c3573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906115e79190615583565b6001600160a01b0316336001600160a01b0316146116935760405162461bcd60e51b815260206004820152605c60248201527f424c535369676e6174757265436865636b65722e6f6e6c79436f6f7264696e6160448201527f746f724f776e65723a2063616c6c6572206973206e6f7420746865206f776e6560648201527f72206f6620746865207265676973747279436f6f7264696e61746f7200000000608482015260a4016102ae565b610bea816142cf565b60fc546004906010908116036116c45760405162461bcd60e51b81526004016102ae90614aab565b6116ce8234610988565b5050565b6040805180820190915260608082526020820152600082604001515160405180604001604052806001815260200160008152505114801561172e57508260a0015151604051806040016040528060018152602001600081525051145b801561175557508260c0015151604051806040016040528060018152602001600081525051145b801561177c57508260e0015151604051806040016040528060018152602001600081525051145b6117e65760405162461bcd60e51b81526020600482015260416024820152600080516020615c2083398151915260448201527f7265733a20696e7075742071756f72756d206c656e677468206d69736d6174636064820152600d60fb1b608482015260a4016102ae565b8251516020840151511461185e5760405162461bcd60e51b815260206004820152604460248201819052600080516020615c20833981519152908201527f7265733a20696e707574206e6f6e7369676e6572206c656e677468206d69736d6064820152630c2e8c6d60e31b608482015260a4016102ae565b4363ffffffff168463ffffffff16106118cd5760405162461bcd60e51b815260206004820152603c6024820152600080516020615c2083398151915260448201527f7265733a20696e76616c6964207265666572656e636520626c6f636b0000000060648201526084016102ae565b60408051808201825260008082526020808301829052835180850185526060808252818301528451808601865260018082529083019390935284518381528086019095529293919082810190803683370190505060208281019190915260408051808201825260018082526000919093015280518281528082019091529081602001602082028036833701905050815260408051808201909152606080825260208201528560200151516001600160401b0381111561198e5761198e614ae2565b6040519080825280602002602001820160405280156119b7578160200160208202803683370190505b5081526020860151516001600160401b038111156119d7576119d7614ae2565b604051908082528060200260200182016040528015611a00578160200160208202803683370190505b5081602001819052506000611aac60405180604001604052806001815260200160008152507f00000000000000000000000000000000000000000000000000000000000000006001600160a01b0316639aa1653d6040518163ffffffff1660e01b8152600401602060405180830381865afa158015611a83573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190611aa7919061572a565b614316565b905060005b876020015151811015611d3d57611af688602001518281518110611ad757611ad761564f565b6020026020010151805160009081526020918201519091526040902090565b83602001518281518110611b0c57611b0c61564f565b60209081029190910101528015611bcc576020830151611b2d6001836156d5565b81518110611b3d57611b3d61564f565b602002602001015160001c83602001518281518110611b5e57611b5e61564f565b602002602001015160001c11611bcc576040805162461bcd60e51b8152602060048201526024810191909152600080516020615c2083398151915260448201527f7265733a206e6f6e5369676e65725075626b657973206e6f7420736f7274656460648201526084016102ae565b7f00000000000000000000000000000000000000000000000000000000000000006001600160a01b03166304ec635184602001518381518110611c1157611c1161564f565b60200260200101518b8b600001518581518110611c3057611c3061564f565b60200260200101516040518463ffffffff1660e01b8152600401611c6d9392919092835263ffffffff918216602084015216604082015260600190565b602060405180830381865afa158015611c8a573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190611cae9190615701565b6001600160c01b031683600001518281518110611ccd57611ccd61564f565b602002602001018181525050611d33610e47611d078486600001518581518110611cf957611cf961564f565b6020026020010151166143a9565b8a602001518481518110611d1d57611d1d61564f565b60200260200101516143d490919063ffffffff16565b9450600101611ab1565b5050611d48836144b7565b60975490935060ff16600081611d5f576000611de1565b7f00000000000000000000000000000000000000000000000000000000000000006001600160a01b031663c448feb86040518163ffffffff1660e01b8152600401602060405180830381865afa158015611dbd573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190611de191906156e8565b905060005b6040518060400160405280600181526020016000815250518110156124d7578215611f72578963ffffffff16827f00000000000000000000000000000000000000000000000000000000000000006001600160a01b031663249a0c4260405180604001604052806001815260200160008152508581518110611e6a57611e6a61564f565b01602001516040516001600160e01b031960e084901b16815260f89190911c6004820152602401602060405180830381865afa158015611eae573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190611ed291906156e8565b611edc9190615555565b11611f725760405162461bcd60e51b81526020600482015260666024820152600080516020615c2083398151915260448201527f7265733a205374616b6552656769737472792075706461746573206d7573742060648201527f62652077697468696e207769746864726177616c44656c6179426c6f636b732060848201526577696e646f7760d01b60a482015260c4016102ae565b7f00000000000000000000000000000000000000000000000000000000000000006001600160a01b03166368bccaac60405180604001604052806001815260200160008152508381518110611fc957611fc961564f565b602001015160f81c60f81b60f81c8c8c60a001518581518110611fee57611fee61564f565b60209081029190910101516040516001600160e01b031960e086901b16815260ff909316600484015263ffffffff9182166024840152166044820152606401602060405180830381865afa15801561204a573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061206e91906157b6565b6001600160401b0319166120918a604001518381518110611ad757611ad761564f565b67ffffffffffffffff19161461212d5760405162461bcd60e51b81526020600482015260616024820152600080516020615c2083398151915260448201527f7265733a2071756f72756d41706b206861736820696e2073746f72616765206460648201527f6f6573206e6f74206d617463682070726f76696465642071756f72756d2061706084820152606b60f81b60a482015260c4016102ae565b61215d896040015182815181106121465761214661564f565b602002602001015187613c6690919063ffffffff16565b95507f00000000000000000000000000000000000000000000000000000000000000006001600160a01b031663c8294c56604051806040016040528060018152602001600081525083815181106121b6576121b661564f565b602001015160f81c60f81b60f81c8c8c60c0015185815181106121db576121db61564f565b60209081029190910101516040516001600160e01b031960e086901b16815260ff909316600484015263ffffffff9182166024840152166044820152606401602060405180830381865afa158015612237573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061225b91906157e1565b856020015182815181106122715761227161564f565b6001600160601b0390921660209283029190910182015285015180518290811061229d5761229d61564f565b6020026020010151856000015182815181106122bb576122bb61564f565b60200260200101906001600160601b031690816001600160601b0316815250506000805b8a60200151518110156124cd5761234a866000015182815181106123055761230561564f565b6020026020010151604051806040016040528060018152602001600081525085815181106123355761233561564f565b016020015160f81c60ff161c60019081161490565b156124c5577f00000000000000000000000000000000000000000000000000000000000000006001600160a01b031663f2be94ae604051806040016040528060018152602001600081525085815181106123a6576123a661564f565b602001015160f81c60f81b60f81c8e896020015185815181106123cb576123cb61564f565b60200260200101518f60e0015188815181106123e9576123e961564f565b602002602001015187815181106124025761240261564f565b60209081029190910101516040516001600160e01b031960e087901b16815260ff909416600485015263ffffffff92831660248501526044840191909152166064820152608401602060405180830381865afa158015612466573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061248a91906157e1565b875180518590811061249e5761249e61564f565b602002602001018181516124b291906157fe565b6001600160601b03169052506001909101905b6001016122df565b5050600101611de6565b5050506000806124f18a868a606001518b60800151610d2c565b91509150816125625760405162461bcd60e51b81526020600482015260436024820152600080516020615c2083398151915260448201527f7265733a2070616972696e6720707265636f6d70696c652063616c6c206661696064820152621b195960ea1b608482015260a4016102ae565b806125c35760405162461bcd60e51b81526020600482015260396024820152600080516020615c2083398151915260448201527f7265733a207369676e617475726520697320696e76616c69640000000000000060648201526084016102ae565b505060008782602001516040516020016125de929190615825565b60408051808303601f1901815291905280516020909101209299929850919650505050505050565b60fb5460405163237dfb4760e11b81523360048201526001600160a01b03909116906346fbf68e90602401602060405180830381865afa15801561264e573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061267291906155ea565b61268e5760405162461bcd60e51b81526004016102ae90615607565b60001960fc81905560405190815233907fab40a374bc51de372200a8bc981af8c9ecdc08dfdaef0bb6e09f88f3c616ef3d9060200160405180910390a2565b6126d5614064565b6126df6000614552565b565b600054600290610100900460ff16158015612703575060005460ff8083169116105b61271f5760405162461bcd60e51b81526004016102ae90615687565b6000805461ffff191660ff83161761010017905561273c8261354a565b6000805461ff001916905560405160ff821681527f7f26b83ff96e1f2b6a682f133852f6798a09c465da95921460cefb38474024989060200160405180910390a15050565b600081848460405161279492919061586d565b60405180910390201490509392505050565b336001600160a01b037f000000000000000000000000000000000000000000000000000000000000000016146127ee5760405162461bcd60e51b81526004016102ae9061587d565b604051639926ee7d60e01b81526001600160a01b037f00000000000000000000000000000000000000000000000000000000000000001690639926ee7d9061283c908590859060040161593b565b600060405180830381600087803b15801561285657600080fd5b505af115801561286a573d6000803e3d6000fd5b505050505050565b336001600160a01b037f000000000000000000000000000000000000000000000000000000000000000016146128ba5760405162461bcd60e51b81526004016102ae9061587d565b6040516351b27a6d60e11b81526001600160a01b0382811660048301527f0000000000000000000000000000000000000000000000000000000000000000169063a364f4da906024015b600060405180830381600087803b15801561291e57600080fd5b505af1158015612932573d6000803e3d6000fd5b5050505050565b612941614064565b60405163a98fb35560e01b81526001600160a01b037f0000000000000000000000000000000000000000000000000000000000000000169063a98fb35590612904908490600401615986565b60cb546001600160a01b031633146129cd5760cb54604051632cbe419560e01b81523360048201526001600160a01b0390911660248201526044016102ae565b60fc546001906002908116036129f55760405162461bcd60e51b81526004016102ae90614aab565b60005a905060008585604051602001612a0f929190615568565b60408051601f198184030181529181528151602092830120600081815260c990935290822080549193509163ffffffff9091169003612a64576040516311cb69a760e11b8152600481018390526024016102ae565b8054640100000000900460ff1615612a9257604051634e78d7f960e11b8152600481018390526024016102ae565b805464ff00000000191664010000000017815560018101546001600160a01b038716600090815260ca60205260409020541015612b155760018101546001600160a01b038716600081815260ca602052604090819020549051632e2a182f60e11b81526004810192909252602482019290925260448101919091526064016102ae565b8054600090612b2c90849063ffffffff16886116d2565b509050604360ff168160200151600081518110612b4b57612b4b61564f565b6020026020010151612b5d9190615999565b6001600160601b031660648260000151600081518110612b7f57612b7f61564f565b60200260200101516001600160601b0316612b9a91906159bc565b1015612c2d5760648160000151600081518110612bb957612bb961564f565b60200260200101516001600160601b0316612bd491906159bc565b604360ff168260200151600081518110612bf057612bf061564f565b6020026020010151612c029190615999565b60405163530f5c4560e11b815260048101929092526001600160601b031660248201526044016102ae565b6040516001600160a01b038816815288907f8511746b73275e06971968773119b9601fc501d7bdf3824d8754042d148940e29060200160405180910390a260003a5a612c7990876156d5565b612c869062011170615555565b612c9091906159bc565b9050600083600101548210612ca9578360010154612cab565b815b6001600160a01b038a16600090815260ca6020526040812080549293508392909190612cd89084906156d5565b90915550506001600160a01b038916600081815260ca6020908152604091829020549151918252600080516020615c00833981519152910160405180910390a260cb546040516001600160a01b039091169082156108fc029083906000818181858888f19350505050158015612d52573d6000803e3d6000fd5b5050505050505050505050565b612d67614064565b60cc55565b60fc54600090600190811603612d945760405162461bcd60e51b81526004016102ae90614aab565b60008533604051602001612da9929190615568565b60408051601f198184030181529181528151602092830120600081815260c990935291205490915063ffffffff1615612df857604051630c40bc4360e21b8152600481018290526024016102ae565b3415612e555733600090815260ca602052604081208054349290612e1d908490615555565b909155505033600081815260ca6020908152604091829020549151918252600080516020615c00833981519152910160405180910390a25b33600090815260ca6020526040902054831115612ea65733600081815260ca602052604090819020549051632e2a182f60e11b815260048101929092526024820185905260448201526064016102ae565b604080516060810182526000602080830182815263ffffffff4381811686528587018a815288865260c99094529386902085518154935115156401000000000264ffffffffff1990941692169190911791909117815590516001909101559151909188917f8801fc966deb2c8f563a103c35c9e80740585c292cd97518587e6e7927e6af5591612f3e913391908b908b908b906159d3565b60405180910390a250505050505050565b606060007f00000000000000000000000000000000000000000000000000000000000000006001600160a01b0316639aa1653d6040518163ffffffff1660e01b8152600401602060405180830381865afa158015612fb1573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190612fd5919061572a565b60ff16905080600003612ff657505060408051600081526020810190915290565b6000805b828110156130a157604051633ca5a5f560e01b815260ff821660048201527f00000000000000000000000000000000000000000000000000000000000000006001600160a01b031690633ca5a5f590602401602060405180830381865afa158015613069573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061308d91906156e8565b6130979083615555565b9150600101612ffa565b506000816001600160401b038111156130bc576130bc614ae2565b6040519080825280602002602001820160405280156130e5578160200160208202803683370190505b5090506000805b7f00000000000000000000000000000000000000000000000000000000000000006001600160a01b0316639aa1653d6040518163ffffffff1660e01b8152600401602060405180830381865afa15801561314a573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061316e919061572a565b60ff168110156132f157604051633ca5a5f560e01b815260ff821660048201526000907f00000000000000000000000000000000000000000000000000000000000000006001600160a01b031690633ca5a5f590602401602060405180830381865afa1580156131e2573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061320691906156e8565b905060005b818110156132e7576040516356e4026d60e11b815260ff84166004820152602481018290527f00000000000000000000000000000000000000000000000000000000000000006001600160a01b03169063adc804da906044016040805180830381865afa158015613280573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906132a4919061575c565b600001518585815181106132ba576132ba61564f565b6001600160a01b0390921660209283029190910190910152836132dc8161579d565b94505060010161320b565b50506001016130ec565b5090949350505050565b613303614064565b6001600160a01b0381166133685760405162461bcd60e51b815260206004820152602660248201527f4f776e61626c653a206e6577206f776e657220697320746865207a65726f206160448201526564647265737360d01b60648201526084016102ae565b610bea81614552565b600054610100900460ff16158080156133915750600054600160ff909116105b806133ab5750303b1580156133ab575060005460ff166001145b6133c75760405162461bcd60e51b81526004016102ae90615687565b6000805460ff1916600117905580156133ea576000805461ff0019166101001790555b6001600160a01b03861661343057604051630b0f5aa160e11b815260206004820152600c60248201526b34b734ba34b0b627bbb732b960a11b60448201526064016102ae565b6001600160a01b03851661347a57604051630b0f5aa160e11b815260206004820152601060248201526f3932bbb0b93239a4b734ba34b0ba37b960811b60448201526064016102ae565b6001600160a01b0384166134c557604051630b0f5aa160e11b815260206004820152601160248201527030b634b3b732b220b3b3b932b3b0ba37b960791b60448201526064016102ae565b6134cf86866145a4565b60cb80546001600160a01b0319166001600160a01b0386161790556134f386614552565b6134fd83836140be565b801561286a576000805461ff0019169055604051600181527f7f26b83ff96e1f2b6a682f133852f6798a09c465da95921460cefb38474024989060200160405180910390a1505050505050565b613552614064565b60cb80546001600160a01b0319166001600160a01b0392909216919091179055565b60fb60009054906101000a90046001600160a01b03166001600160a01b031663eab66d7a6040518163ffffffff1660e01b8152600401602060405180830381865afa1580156135c7573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906135eb9190615583565b6001600160a01b0316336001600160a01b03161461361b5760405162461bcd60e51b81526004016102ae906155a0565b60fc5419811960fc541916146136995760405162461bcd60e51b815260206004820152603860248201527f5061757361626c652e756e70617573653a20696e76616c696420617474656d7060448201527f7420746f2070617573652066756e6374696f6e616c697479000000000000000060648201526084016102ae565b60fc81905560405181815233907f3582d1828e26bf56bd801502bc021ac0bc8afb57c826e4986b45593c8fad389c90602001610d21565b6065546001600160a01b031633146137655760405162461bcd60e51b815260206004820152604c60248201527f536572766963654d616e61676572426173652e6f6e6c7952657761726473496e60448201527f69746961746f723a2063616c6c6572206973206e6f742074686520726577617260648201526b32399034b734ba34b0ba37b960a11b608482015260a4016102ae565b60005b81811015613a30578282828181106137825761378261564f565b90506020028101906137949190615a2a565b6137a5906040810190602001614ca7565b6001600160a01b03166323b872dd33308686868181106137c7576137c761564f565b90506020028101906137d99190615a2a565b604080516001600160e01b031960e087901b1681526001600160a01b039485166004820152939092166024840152013560448201526064016020604051808303816000875af1158015613830573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061385491906155ea565b5060008383838181106138695761386961564f565b905060200281019061387b9190615a2a565b61388c906040810190602001614ca7565b604051636eb1769f60e11b81523060048201526001600160a01b037f000000000000000000000000000000000000000000000000000000000000000081166024830152919091169063dd62ed3e90604401602060405180830381865afa1580156138fa573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061391e91906156e8565b90508383838181106139325761393261564f565b90506020028101906139449190615a2a565b613955906040810190602001614ca7565b6001600160a01b031663095ea7b37f0000000000000000000000000000000000000000000000000000000000000000838787878181106139975761399761564f565b90506020028101906139a99190615a2a565b604001356139b79190615555565b6040516001600160e01b031960e085901b1681526001600160a01b03909216600483015260248201526044016020604051808303816000875af1158015613a02573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190613a2691906155ea565b5050600101613768565b5060405163fce36c7d60e01b81526001600160a01b037f0000000000000000000000000000000000000000000000000000000000000000169063fce36c7d9061283c9085908590600401615ab0565b613a87614064565b60cc8054600160ff841690811b9091179091556040517fec54a85c01b5fc7fb41be0f33eabc56f2981110da8317b9817bc7c718f6d7bfe90600090a250565b600083613ad4868585614621565b1495945050505050565b6001600160a01b038116613b6c5760405162461bcd60e51b815260206004820152604960248201527f5061757361626c652e5f73657450617573657252656769737472793a206e657760448201527f50617573657252656769737472792063616e6e6f7420626520746865207a65726064820152686f206164647265737360b81b608482015260a4016102ae565b60fb54604080516001600160a01b03928316815291831660208301527f6e9fcd539896fca60e8b0f01dd580233e48a6b0f7df013b89ba7f565869acdb6910160405180910390a160fb80546001600160a01b0319166001600160a01b0392909216919091179055565b6040805180820190915260008082526020820152613bf16149d1565b835181526020808501519082015260408082018490526000908360608460076107d05a03fa90508080613c2057fe5b5080613c5e5760405162461bcd60e51b815260206004820152600d60248201526c1958cb5b5d5b0b59985a5b1959609a1b60448201526064016102ae565b505092915050565b6040805180820190915260008082526020820152613c826149ef565b835181526020808501518183015283516040808401919091529084015160608301526000908360808460066107d05a03fa90508080613cbd57fe5b5080613c5e5760405162461bcd60e51b815260206004820152600d60248201526c1958cb5859190b59985a5b1959609a1b60448201526064016102ae565b613d03614a0d565b50604080516080810182527f198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c28183019081527f1800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed6060830152815281518083019092527f275dc4a288d1afb3cbb1ac09187524c7db36395df7be3b99e673b13a075a65ec82527f1d9befcd05a5323e6da4d435f3b617cdb3af83285c2df711ef39c01571827f9d60208381019190915281019190915290565b604080518082019091526000808252602082015260008080613deb600080516020615be083398151915286615665565b90505b613df78161471e565b9093509150600080516020615be08339815191528283098303613e30576040805180820190915290815260208101919091529392505050565b600080516020615be0833981519152600182089050613dee565b604080518082018252868152602080820186905282518084019093528683528201849052600091829190613e7c614a32565b60005b6002811015614037576000613e958260066159bc565b9050848260028110613ea957613ea961564f565b60200201515183613ebb836000615555565b600c8110613ecb57613ecb61564f565b6020020152848260028110613ee257613ee261564f565b60200201516020015183826001613ef99190615555565b600c8110613f0957613f0961564f565b6020020152838260028110613f2057613f2061564f565b6020020151515183613f33836002615555565b600c8110613f4357613f4361564f565b6020020152838260028110613f5a57613f5a61564f565b6020020151516001602002015183613f73836003615555565b600c8110613f8357613f8361564f565b6020020152838260028110613f9a57613f9a61564f565b602002015160200151600060028110613fb557613fb561564f565b602002015183613fc6836004615555565b600c8110613fd657613fd661564f565b6020020152838260028110613fed57613fed61564f565b6020020151602001516001600281106140085761400861564f565b602002015183614019836005615555565b600c81106140295761402961564f565b602002015250600101613e7f565b50614040614a51565b60006020826101808560088cfa9151919c9115159b50909950505050505050505050565b6033546001600160a01b031633146126df5760405162461bcd60e51b815260206004820181905260248201527f4f776e61626c653a2063616c6c6572206973206e6f7420746865206f776e657260448201526064016102ae565b60fb546001600160a01b03161580156140df57506001600160a01b03821615155b6141615760405162461bcd60e51b815260206004820152604760248201527f5061757361626c652e5f696e697469616c697a655061757365723a205f696e6960448201527f7469616c697a6550617573657228292063616e206f6e6c792062652063616c6c6064820152666564206f6e636560c81b608482015260a4016102ae565b60fc81905560405181815233907fab40a374bc51de372200a8bc981af8c9ecdc08dfdaef0bb6e09f88f3c616ef3d9060200160405180910390a26116ce82613ade565b60606000806141b2846143a9565b61ffff166001600160401b038111156141cd576141cd614ae2565b6040519080825280601f01601f1916602001820160405280156141f7576020820181803683370190505b5090506000805b82518210801561420f575061010081105b156132f1576001811b935085841615614256578060f81b8383815181106142385761423861564f565b60200101906001600160f81b031916908160001a9053508160010191505b61425f8161579d565b90506141fe565b606554604080516001600160a01b03928316815291831660208301527fe11cddf1816a43318ca175bbc52cd0185436e9cbead7c83acc54a73e461717e3910160405180910390a1606580546001600160a01b0319166001600160a01b0392909216919091179055565b6097805460ff19168215159081179091556040519081527f40e4ed880a29e0f6ddce307457fb75cddf4feef7d3ecb0301bfdf4976a0e2dfc9060200160405180910390a150565b600080614322846147a0565b9050808360ff166001901b116143a05760405162461bcd60e51b815260206004820152603f60248201527f4269746d61705574696c732e6f72646572656442797465734172726179546f4260448201527f69746d61703a206269746d61702065786365656473206d61782076616c75650060648201526084016102ae565b90505b92915050565b6000805b82156143a3576143be6001846156d5565b90921691806143cc81615bbe565b9150506143ad565b60408051808201909152600080825260208201526102008261ffff16106144305760405162461bcd60e51b815260206004820152601060248201526f7363616c61722d746f6f2d6c6172676560801b60448201526064016102ae565b8161ffff166001036144435750816143a3565b6040805180820190915260008082526020820181905284906001905b8161ffff168661ffff16106144ac57600161ffff871660ff83161c8116900361448f5761448c8484613c66565b93505b6144998384613c66565b92506201fffe600192831b16910161445f565b509195945050505050565b604080518082019091526000808252602082015281511580156144dc57506020820151155b156144fa575050604080518082019091526000808252602082015290565b604051806040016040528083600001518152602001600080516020615be0833981519152846020015161452d9190615665565b61454590600080516020615be08339815191526156d5565b905292915050565b919050565b603380546001600160a01b038381166001600160a01b0319831681179093556040519116919082907f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e090600090a35050565b600054610100900460ff1661460f5760405162461bcd60e51b815260206004820152602b60248201527f496e697469616c697a61626c653a20636f6e7472616374206973206e6f74206960448201526a6e697469616c697a696e6760a81b60648201526084016102ae565b61461882614552565b6116ce81614266565b6000602084516146319190615665565b156146b85760405162461bcd60e51b815260206004820152604b60248201527f4d65726b6c652e70726f63657373496e636c7573696f6e50726f6f664b65636360448201527f616b3a2070726f6f66206c656e6774682073686f756c642062652061206d756c60648201526a3a34b836329037b310199960a91b608482015260a4016102ae565b8260205b85518111611271576146cf600285615665565b6000036146f35781600052808601516020526040600020915060028404935061470c565b8086015160005281602052604060002091506002840493505b614717602082615555565b90506146bc565b60008080600080516020615be08339815191526003600080516020615be083398151915286600080516020615be0833981519152888909090890506000614794827f0c19139cb84c680a6e14116da060561765e05aa45a1c72a34f082305b61f3f52600080516020615be0833981519152614928565b91959194509092505050565b6000610100825111156148295760405162461bcd60e51b8152602060048201526044602482018190527f4269746d61705574696c732e6f72646572656442797465734172726179546f42908201527f69746d61703a206f7264657265644279746573417272617920697320746f6f206064820152636c6f6e6760e01b608482015260a4016102ae565b815160000361483a57506000919050565b600080836000815181106148505761485061564f565b0160200151600160f89190911c81901b92505b845181101561491f5784818151811061487e5761487e61564f565b0160200151600160f89190911c1b91508282116149135760405162461bcd60e51b815260206004820152604760248201527f4269746d61705574696c732e6f72646572656442797465734172726179546f4260448201527f69746d61703a206f72646572656442797465734172726179206973206e6f74206064820152661bdc99195c995960ca1b608482015260a4016102ae565b91811791600101614863565b50909392505050565b600080614933614a51565b61493b614a6f565b602080825281810181905260408201819052606082018890526080820187905260a082018690528260c08360056107d05a03fa9250828061497857fe5b50826149c65760405162461bcd60e51b815260206004820152601a60248201527f424e3235342e6578704d6f643a2063616c6c206661696c75726500000000000060448201526064016102ae565b505195945050505050565b60405180606001604052806003906020820280368337509192915050565b60405180608001604052806004906020820280368337509192915050565b6040518060400160405280614a20614a8d565b8152602001614a2d614a8d565b905290565b604051806101800160405280600c906020820280368337509192915050565b60405180602001604052806001906020820280368337509192915050565b6040518060c001604052806006906020820280368337509192915050565b60405180604001604052806002906020820280368337509192915050565b60208082526019908201527f5061757361626c653a20696e6465782069732070617573656400000000000000604082015260600190565b634e487b7160e01b600052604160045260246000fd5b604080519081016001600160401b0381118282101715614b1a57614b1a614ae2565b60405290565b60405161010081016001600160401b0381118282101715614b1a57614b1a614ae2565b604051601f8201601f191681016001600160401b0381118282101715614b6b57614b6b614ae2565b604052919050565b60006001600160401b03831115614b8c57614b8c614ae2565b614b9f601f8401601f1916602001614b43565b9050828152838383011115614bb357600080fd5b828260208301376000602084830101529392505050565b600082601f830112614bdb57600080fd5b614bea83833560208501614b73565b9392505050565b6001600160a01b0381168114610bea57600080fd5b600080600080600080600080610100898b031215614c2357600080fd5b88359750602089013596506040890135955060608901356001600160601b031981168114614c5057600080fd5b94506080890135935060a08901356001600160401b03811115614c7257600080fd5b614c7e8b828c01614bca565b93505060c0890135915060e0890135614c9681614bf1565b809150509295985092959890939650565b600060208284031215614cb957600080fd5b81356143a081614bf1565b600060208284031215614cd657600080fd5b5035919050565b60ff81168114610bea57600080fd5b600060208284031215614cfe57600080fd5b81356143a081614cdd565b600060408284031215614d1b57600080fd5b614d23614af8565b9050813581526020820135602082015292915050565b600082601f830112614d4a57600080fd5b614d52614af8565b806040840185811115614d6457600080fd5b845b81811015614d7e578035845260209384019301614d66565b509095945050505050565b600060808284031215614d9b57600080fd5b614da3614af8565b9050614daf8383614d39565b8152614dbe8360408401614d39565b602082015292915050565b6000806000806101208587031215614de057600080fd5b84359350614df18660208701614d09565b9250614e008660608701614d89565b9150614e0f8660e08701614d09565b905092959194509250565b60008060408385031215614e2d57600080fd5b8235614e3881614bf1565b946020939093013593505050565b6020808252825182820181905260009190848201906040850190845b81811015614e875783516001600160a01b031683529284019291840191600101614e62565b50909695505050505050565b8015158114610bea57600080fd5b600060208284031215614eb357600080fd5b81356143a081614e93565b803563ffffffff8116811461454d57600080fd5b60006001600160401b03821115614eeb57614eeb614ae2565b5060051b60200190565b600082601f830112614f0657600080fd5b81356020614f1b614f1683614ed2565b614b43565b8083825260208201915060208460051b870101935086841115614f3d57600080fd5b602086015b84811015614f6057614f5381614ebe565b8352918301918301614f42565b509695505050505050565b600082601f830112614f7c57600080fd5b81356020614f8c614f1683614ed2565b8083825260208201915060208460061b870101935086841115614fae57600080fd5b602086015b84811015614f6057614fc58882614d09565b835291830191604001614fb3565b600082601f830112614fe457600080fd5b81356020614ff4614f1683614ed2565b82815260059290921b8401810191818101908684111561501357600080fd5b8286015b84811015614f605780356001600160401b038111156150365760008081fd5b6150448986838b0101614ef5565b845250918301918301615017565b6000610180828403121561506557600080fd5b61506d614b20565b905081356001600160401b038082111561508657600080fd5b61509285838601614ef5565b835260208401359150808211156150a857600080fd5b6150b485838601614f6b565b602084015260408401359150808211156150cd57600080fd5b6150d985838601614f6b565b60408401526150eb8560608601614d89565b60608401526150fd8560e08601614d09565b608084015261012084013591508082111561511757600080fd5b61512385838601614ef5565b60a084015261014084013591508082111561513d57600080fd5b61514985838601614ef5565b60c084015261016084013591508082111561516357600080fd5b5061517084828501614fd3565b60e08301525092915050565b60008060006060848603121561519157600080fd5b833592506151a160208501614ebe565b915060408401356001600160401b038111156151bc57600080fd5b6151c886828701615052565b9150509250925092565b60008151808452602080850194506020840160005b8381101561520c5781516001600160601b0316875295820195908201906001016151e7565b509495945050505050565b604081526000835160408084015261523260808401826151d2565b90506020850151603f1984830301606085015261524f82826151d2565b925050508260208301529392505050565b60008083601f84011261527257600080fd5b5081356001600160401b0381111561528957600080fd5b6020830191508360208285010111156152a157600080fd5b9250929050565b6000806000604084860312156152bd57600080fd5b83356001600160401b038111156152d357600080fd5b6152df86828701615260565b909790965060209590950135949350505050565b6000806040838503121561530657600080fd5b823561531181614bf1565b915060208301356001600160401b038082111561532d57600080fd5b908401906060828703121561534157600080fd5b60405160608101818110838211171561535c5761535c614ae2565b60405282358281111561536e57600080fd5b61537a88828601614bca565b82525060208301356020820152604083013560408201528093505050509250929050565b6000602082840312156153b057600080fd5b81356001600160401b038111156153c657600080fd5b8201601f810184136153d757600080fd5b6153e684823560208401614b73565b949350505050565b60008060006060848603121561540357600080fd5b8335925060208401356151a181614bf1565b6000806000806060858703121561542b57600080fd5b8435935060208501356001600160401b0381111561544857600080fd5b61545487828801615260565b9598909750949560400135949350505050565b600080600080600060a0868803121561547f57600080fd5b853561548a81614bf1565b9450602086013561549a81614bf1565b935060408601356154aa81614bf1565b925060608601356154ba81614bf1565b949793965091946080013592915050565b600080602083850312156154de57600080fd5b82356001600160401b03808211156154f557600080fd5b818501915085601f83011261550957600080fd5b81358181111561551857600080fd5b8660208260051b850101111561552d57600080fd5b60209290920196919550909350505050565b634e487b7160e01b600052601160045260246000fd5b808201808211156143a3576143a361553f565b91825260601b6001600160601b031916602082015260340190565b60006020828403121561559557600080fd5b81516143a081614bf1565b6020808252602a908201527f6d73672e73656e646572206973206e6f74207065726d697373696f6e6564206160408201526939903ab73830bab9b2b960b11b606082015260800190565b6000602082840312156155fc57600080fd5b81516143a081614e93565b60208082526028908201527f6d73672e73656e646572206973206e6f74207065726d697373696f6e6564206160408201526739903830bab9b2b960c11b606082015260800190565b634e487b7160e01b600052603260045260246000fd5b60008261568257634e487b7160e01b600052601260045260246000fd5b500690565b6020808252602e908201527f496e697469616c697a61626c653a20636f6e747261637420697320616c72656160408201526d191e481a5b9a5d1a585b1a5e995960921b606082015260800190565b818103818111156143a3576143a361553f565b6000602082840312156156fa57600080fd5b5051919050565b60006020828403121561571357600080fd5b81516001600160c01b03811681146143a057600080fd5b60006020828403121561573c57600080fd5b81516143a081614cdd565b6001600160601b0381168114610bea57600080fd5b60006040828403121561576e57600080fd5b615776614af8565b825161578181614bf1565b8152602083015161579181615747565b60208201529392505050565b6000600182016157af576157af61553f565b5060010190565b6000602082840312156157c857600080fd5b815167ffffffffffffffff19811681146143a057600080fd5b6000602082840312156157f357600080fd5b81516143a081615747565b6001600160601b0382811682821603908082111561581e5761581e61553f565b5092915050565b63ffffffff60e01b8360e01b1681526000600482018351602080860160005b8381101561586057815185529382019390820190600101615844565b5092979650505050505050565b8183823760009101908152919050565b60208082526052908201527f536572766963654d616e61676572426173652e6f6e6c7952656769737472794360408201527f6f6f7264696e61746f723a2063616c6c6572206973206e6f742074686520726560608201527133b4b9ba393c9031b7b7b93234b730ba37b960711b608082015260a00190565b6000815180845260005b8181101561591b576020818501810151868301820152016158ff565b506000602082860101526020601f19601f83011685010191505092915050565b60018060a01b038316815260406020820152600082516060604084015261596560a08401826158f5565b90506020840151606084015260408401516080840152809150509392505050565b602081526000614bea60208301846158f5565b6001600160601b03818116838216028082169190828114613c5e57613c5e61553f565b80820281158282048414176143a3576143a361553f565b6001600160a01b038616815263ffffffff851660208201526080604082018190528101839052828460a0830137600060a08483010152600060a0601f19601f86011683010190508260608301529695505050505050565b60008235609e19833603018112615a4057600080fd5b9190910192915050565b803561454d81614bf1565b8183526000602080850194508260005b8581101561520c578135615a7881614bf1565b6001600160a01b0316875281830135615a9081615747565b6001600160601b0316878401526040968701969190910190600101615a65565b60208082528181018390526000906040808401600586901b8501820187855b88811015615bb057878303603f190184528135368b9003609e19018112615af557600080fd5b8a0160a0813536839003601e19018112615b0e57600080fd5b820188810190356001600160401b03811115615b2957600080fd5b8060061b3603821315615b3b57600080fd5b828752615b4b8388018284615a55565b92505050615b5a888301615a4a565b6001600160a01b03168886015281870135878601526060615b7c818401614ebe565b63ffffffff16908601526080615b93838201614ebe565b63ffffffff16950194909452509285019290850190600101615acf565b509098975050505050505050565b600061ffff808316818103615bd557615bd561553f565b600101939250505056fe30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd470ea46f246ccfc58f7a93aa09bc6245a6818e97b1a160d186afe78993a3b194a0424c535369676e6174757265436865636b65722e636865636b5369676e617475a26469706673582212200c7e3ca27257a5f506f4283cc648dd61a84c8cf96cbe37916bc2c69c27ba758564736f6c63430008180033",
 }
 
@@ -254,6 +254,68 @@ func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerTra
 	return _ContractAlignedLayerServiceManager.Contract.contract.Transact(opts, method, params...)
 }
 
+// QUORUMTHRESHOLDPERCENTAGE is a free data retrieval call binding the contract method 0x2d9ff8bd.
+//
+// Solidity: function QUORUM_THRESHOLD_PERCENTAGE() view returns(uint8)
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerCaller) QUORUMTHRESHOLDPERCENTAGE(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	err := _ContractAlignedLayerServiceManager.contract.Call(opts, &out, "QUORUM_THRESHOLD_PERCENTAGE")
+
+	if err != nil {
+		return *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	return out0, err
+
+}
+
+// QUORUMTHRESHOLDPERCENTAGE is a free data retrieval call binding the contract method 0x2d9ff8bd.
+//
+// Solidity: function QUORUM_THRESHOLD_PERCENTAGE() view returns(uint8)
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerSession) QUORUMTHRESHOLDPERCENTAGE() (uint8, error) {
+	return _ContractAlignedLayerServiceManager.Contract.QUORUMTHRESHOLDPERCENTAGE(&_ContractAlignedLayerServiceManager.CallOpts)
+}
+
+// QUORUMTHRESHOLDPERCENTAGE is a free data retrieval call binding the contract method 0x2d9ff8bd.
+//
+// Solidity: function QUORUM_THRESHOLD_PERCENTAGE() view returns(uint8)
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerCallerSession) QUORUMTHRESHOLDPERCENTAGE() (uint8, error) {
+	return _ContractAlignedLayerServiceManager.Contract.QUORUMTHRESHOLDPERCENTAGE(&_ContractAlignedLayerServiceManager.CallOpts)
+}
+
+// AggregatorSenders is a free data retrieval call binding the contract method 0x61e3e858.
+//
+// Solidity: function aggregatorSenders(address ) view returns(bool)
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerCaller) AggregatorSenders(opts *bind.CallOpts, arg0 common.Address) (bool, error) {
+	var out []interface{}
+	err := _ContractAlignedLayerServiceManager.contract.Call(opts, &out, "aggregatorSenders", arg0)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// AggregatorSenders is a free data retrieval call binding the contract method 0x61e3e858.
+//
+// Solidity: function aggregatorSenders(address ) view returns(bool)
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerSession) AggregatorSenders(arg0 common.Address) (bool, error) {
+	return _ContractAlignedLayerServiceManager.Contract.AggregatorSenders(&_ContractAlignedLayerServiceManager.CallOpts, arg0)
+}
+
+// AggregatorSenders is a free data retrieval call binding the contract method 0x61e3e858.
+//
+// Solidity: function aggregatorSenders(address ) view returns(bool)
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerCallerSession) AggregatorSenders(arg0 common.Address) (bool, error) {
+	return _ContractAlignedLayerServiceManager.Contract.AggregatorSenders(&_ContractAlignedLayerServiceManager.CallOpts, arg0)
+}
+
 // AlignedAggregator is a free data retrieval call binding the contract method 0x4a5bf632.
 //
 // Solidity: function alignedAggregator() view returns(address)
@@ -1316,6 +1378,27 @@ func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerTra
 	return _ContractAlignedLayerServiceManager.Contract.SetAggregator(&_ContractAlignedLayerServiceManager.TransactOpts, _alignedAggregator)
 }
 
+// SetAggregatorSender is a paid mutator transaction binding the contract method 0x635a1d25.
+//
+// Solidity: function setAggregatorSender(address sender, bool allowed) returns()
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerTransactor) SetAggregatorSender(opts *bind.TransactOpts, sender common.Address, allowed bool) (*types.Transaction, error) {
+	return _ContractAlignedLayerServiceManager.contract.Transact(opts, "setAggregatorSender", sender, allowed)
+}
+
+// SetAggregatorSender is a paid mutator transaction binding the contract method 0x635a1d25.
+//
+// Solidity: function setAggregatorSender(address sender, bool allowed) returns()
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerSession) SetAggregatorSender(sender common.Address, allowed bool) (*types.Transaction, error) {
+	return _ContractAlignedLayerServiceManager.Contract.SetAggregatorSender(&_ContractAlignedLayerServiceManager.TransactOpts, sender, allowed)
+}
+
+// SetAggregatorSender is a paid mutator transaction binding the contract method 0x635a1d25.
+//
+// Solidity: function setAggregatorSender(address sender, bool allowed) returns()
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerTransactorSession) SetAggregatorSender(sender common.Address, allowed bool) (*types.Transaction, error) {
+	return _ContractAlignedLayerServiceManager.Contract.SetAggregatorSender(&_ContractAlignedLayerServiceManager.TransactOpts, sender, allowed)
+}
+
 // SetDisabledVerifiers is a paid mutator transaction binding the contract method 0xb753645e.
 //
 // Solidity: function setDisabledVerifiers(uint256 bitmap) returns()
@@ -1505,6 +1588,151 @@ func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerTra
 	return _ContractAlignedLayerServiceManager.Contract.Receive(&_ContractAlignedLayerServiceManager.TransactOpts)
 }
 
+// ContractAlignedLayerServiceManagerAggregatorSenderUpdatedIterator is returned from FilterAggregatorSenderUpdated and is used to iterate over the raw logs and unpacked data for AggregatorSenderUpdated events raised by the ContractAlignedLayerServiceManager contract.
+type ContractAlignedLayerServiceManagerAggregatorSenderUpdatedIterator struct {
+	Event *ContractAlignedLayerServiceManagerAggregatorSenderUpdated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractAlignedLayerServiceManagerAggregatorSenderUpdatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractAlignedLayerServiceManagerAggregatorSenderUpdated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractAlignedLayerServiceManagerAggregatorSenderUpdated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractAlignedLayerServiceManagerAggregatorSenderUpdatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractAlignedLayerServiceManagerAggregatorSenderUpdatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractAlignedLayerServiceManagerAggregatorSenderUpdated represents a AggregatorSenderUpdated event raised by the ContractAlignedLayerServiceManager contract.
+type ContractAlignedLayerServiceManagerAggregatorSenderUpdated struct {
+	Sender  common.Address
+	Allowed bool
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterAggregatorSenderUpdated is a free log retrieval operation binding the contract event 0x7ac9bd84211366be3aacae2c637c0c1eacd10daeabbb641b295bdbe16d4c2491.
+//
+// Solidity: event AggregatorSenderUpdated(address indexed sender, bool allowed)
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerFilterer) FilterAggregatorSenderUpdated(opts *bind.FilterOpts, sender []common.Address) (*ContractAlignedLayerServiceManagerAggregatorSenderUpdatedIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _ContractAlignedLayerServiceManager.contract.FilterLogs(opts, "AggregatorSenderUpdated", senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractAlignedLayerServiceManagerAggregatorSenderUpdatedIterator{contract: _ContractAlignedLayerServiceManager.contract, event: "AggregatorSenderUpdated", logs: logs, sub: sub}, nil
+}
+
+// WatchAggregatorSenderUpdated is a free log subscription operation binding the contract event 0x7ac9bd84211366be3aacae2c637c0c1eacd10daeabbb641b295bdbe16d4c2491.
+//
+// Solidity: event AggregatorSenderUpdated(address indexed sender, bool allowed)
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerFilterer) WatchAggregatorSenderUpdated(opts *bind.WatchOpts, sink chan<- *ContractAlignedLayerServiceManagerAggregatorSenderUpdated, sender []common.Address) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _ContractAlignedLayerServiceManager.contract.WatchLogs(opts, "AggregatorSenderUpdated", senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractAlignedLayerServiceManagerAggregatorSenderUpdated)
+				if err := _ContractAlignedLayerServiceManager.contract.UnpackLog(event, "AggregatorSenderUpdated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAggregatorSenderUpdated is a log parse operation binding the contract event 0x7ac9bd84211366be3aacae2c637c0c1eacd10daeabbb641b295bdbe16d4c2491.
+//
+// Solidity: event AggregatorSenderUpdated(address indexed sender, bool allowed)
+func (_ContractAlignedLayerServiceManager *ContractAlignedLayerServiceManagerFilterer) ParseAggregatorSenderUpdated(log types.Log) (*ContractAlignedLayerServiceManagerAggregatorSenderUpdated, error) {
+	event := new(ContractAlignedLayerServiceManagerAggregatorSenderUpdated)
+	if err := _ContractAlignedLayerServiceManager.contract.UnpackLog(event, "AggregatorSenderUpdated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
 // ContractAlignedLayerServiceManagerBatchVerifiedIterator is returned from FilterBatchVerified and is used to iterate over the raw logs and unpacked data for BatchVerified events raised by the ContractAlignedLayerServiceManager contract.
 type ContractAlignedLayerServiceManagerBatchVerifiedIterator struct {
 	Event *ContractAlignedLayerServiceManagerBatchVerified // Event containing the contract specifics and raw log
@@ -23,7 +23,8 @@ contract AlignedLayerServiceManager is
     Pausable
 {
     uint256 internal constant THRESHOLD_DENOMINATOR = 100;
-    uint8 internal constant QUORUM_THRESHOLD_PERCENTAGE = 67;
+    // Public so the aggregator can read the threshold it has to reach
+    uint8 public constant QUORUM_THRESHOLD_PERCENTAGE = 67;
 
     constructor(
         IAVSDirectory __avsDirectory,
@@ -228,7 +229,8 @@ contract AlignedLayerServiceManager is
             batchersBalances[senderAddress]
         );
 
-        payable(alignedAggregator).transfer(transferAmount);
+        // Paid back to the wallet that sent the response, which may be one of the aggregatorSenders
+        payable(msg.sender).transfer(transferAmount);
     }
 
     function isVerifierDisabled(
@@ -327,6 +329,14 @@ contract AlignedLayerServiceManager is
         alignedAggregator = _alignedAggregator;
     }
 
+    function setAggregatorSender(address sender, bool allowed) external onlyOwner {
+        if (sender == address(0)) {
+            revert InvalidAddress("sender");
+        }
+        aggregatorSenders[sender] = allowed;
+        emit AggregatorSenderUpdated(sender, allowed);
+    }
+
     function withdraw(uint256 amount) external onlyWhenNotPaused(3) {
         if (batchersBalances[msg.sender] < amount) {
             revert InsufficientFunds(
@@ -370,7 +380,7 @@ contract AlignedLayerServiceManager is
     }
 
     modifier onlyAggregator() {
-        if (msg.sender != alignedAggregator) {
+        if (msg.sender != alignedAggregator && !aggregatorSenders[msg.sender]) {
             revert SenderIsNotAggregator(msg.sender, alignedAggregator);
         }
         _;
@@ -21,7 +21,11 @@ abstract contract AlignedLayerServiceManagerStorage {
     // The verifier index follows its corresponding value in the `ProvingSystemId` enum being 0 the first verifier.
     uint256 public disabledVerifiers;
 
+    // Extra accounts allowed to respond to tasks along with alignedAggregator,
+    // so the aggregator can send its responses from several wallets
+    mapping(address => bool) public aggregatorSenders;
+
     // storage gap for upgradeability
     // solhint-disable-next-line var-name-mixedcase
-    uint256[46] private __GAP;
+    uint256[45] private __GAP;
 }
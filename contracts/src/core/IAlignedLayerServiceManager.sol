@@ -22,6 +22,7 @@ interface IAlignedLayerServiceManager {
     event BatcherBalanceUpdated(address indexed batcher, uint256 newBalance);
     event VerifierDisabled(uint8 indexed verifierIdx);
     event VerifierEnabled(uint8 indexed verifierIdx);
+    event AggregatorSenderUpdated(address indexed sender, bool allowed);
 
     // ERRORS
     error BatchAlreadySubmitted(bytes32 batchIdentifierHash); // 3102f10c
@@ -75,6 +76,8 @@ interface IAlignedLayerServiceManager {
 
     function setAggregator(address _aggregator) external;
 
+    function setAggregatorSender(address sender, bool allowed) external;
+
     function isVerifierDisabled(
         uint8 verifierIdx
     ) external view returns (bool);
@@ -156,4 +156,28 @@ contract AlignedLayerServiceManagerTest is Test, BLSMockAVSDeployer {
         vm.expectRevert("Ownable: caller is not the owner");
         alignedLayerServiceManager.enableVerifier(newBitmap);
     }
+
+    /* =============== Aggregator senders tests =============== */
+
+    function test_SetAggregatorSender_WorksAsExpected() public {
+        address sender = address(0x1234);
+        assertEq(alignedLayerServiceManager.aggregatorSenders(sender), false);
+
+        vm.expectEmit(true, true, true, true);
+        emit IAlignedLayerServiceManager.AggregatorSenderUpdated(sender, true);
+        vm.prank(address(0));
+        alignedLayerServiceManager.setAggregatorSender(sender, true);
+        assertEq(alignedLayerServiceManager.aggregatorSenders(sender), true);
+
+        vm.expectEmit(true, true, true, true);
+        emit IAlignedLayerServiceManager.AggregatorSenderUpdated(sender, false);
+        vm.prank(address(0));
+        alignedLayerServiceManager.setAggregatorSender(sender, false);
+        assertEq(alignedLayerServiceManager.aggregatorSenders(sender), false);
+    }
+
+    function test_SetAggregatorSender_FailsWhenNotOwner() public {
+        vm.expectRevert("Ownable: caller is not the owner");
+        alignedLayerServiceManager.setAggregatorSender(address(0x1234), true);
+    }
 }
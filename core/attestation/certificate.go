@@ -0,0 +1,274 @@
+// Package attestation holds the certificates the aggregator writes once a task reaches quorum,
+// and the verifiers that check them without waiting for the response to be included on-chain
+package attestation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+// CertificateVersion is the version of the certificates written by this package.
+// It must be bumped on any change of the certificate fields or of how they are verified
+const CertificateVersion = 2
+
+// G1Point is a BN254 G1 point, with its coordinates as hex strings
+type G1Point struct {
+	X *hexutil.Big `json:"x"`
+	Y *hexutil.Big `json:"y"`
+}
+
+// G2Point is a BN254 G2 point, with its coordinates as hex strings.
+// Each coordinate is in the order the contracts expect: the imaginary part first
+type G2Point struct {
+	X [2]*hexutil.Big `json:"x"`
+	Y [2]*hexutil.Big `json:"y"`
+}
+
+// Certificate is a self-contained proof that the operators reached quorum on a task.
+// It holds everything respondToTaskV2 sends on-chain, so it can be checked with the same signature checks
+type Certificate struct {
+	Version             uint32         `json:"version"`
+	BatchIdentifierHash common.Hash    `json:"batch_identifier_hash"`
+	BatchMerkleRoot     common.Hash    `json:"batch_merkle_root"`
+	SenderAddress       common.Address `json:"sender_address"`
+	// Block the task was created at, the operators and stakes are the ones registered at this block
+	ReferenceBlock             uint32   `json:"reference_block"`
+	QuorumNumbers              []uint32 `json:"quorum_numbers"`
+	QuorumThresholdPercentages []uint32 `json:"quorum_threshold_percentages"`
+	// Operator ids, sorted
+	Signers    []common.Hash `json:"signers"`
+	NonSigners []common.Hash `json:"non_signers"`
+	// In the same order as NonSigners
+	NonSignerPubkeys []G1Point `json:"non_signer_pubkeys"`
+	QuorumApks       []G1Point `json:"quorum_apks"`
+	// Aggregated G2 public key of the signers
+	ApkG2 G2Point `json:"apk_g2"`
+	// Aggregated signature of the signers over the batch identifier hash
+	Sigma                        G1Point    `json:"sigma"`
+	NonSignerQuorumBitmapIndices []uint32   `json:"non_signer_quorum_bitmap_indices"`
+	QuorumApkIndices             []uint32   `json:"quorum_apk_indices"`
+	TotalStakeIndices            []uint32   `json:"total_stake_indices"`
+	NonSignerStakeIndices        [][]uint32 `json:"non_signer_stake_indices"`
+	CreatedAt                    time.Time  `json:"created_at"`
+}
+
+// NewCertificate builds the certificate of a task from the aggregated response sent to respondToTaskV2.
+// The non signers are the operators of the non signer public keys, in the same order
+func NewCertificate(batchMerkleRoot [32]byte, senderAddress [20]byte, referenceBlock uint32, quorumNumbers eigentypes.QuorumNums,
+	quorumThresholdPercentages eigentypes.QuorumThresholdPercentages, signers []eigentypes.OperatorId,
+	nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature, createdAt time.Time) *Certificate {
+	certificate := &Certificate{
+		Version:                      CertificateVersion,
+		BatchIdentifierHash:          BatchIdentifierHash(batchMerkleRoot, senderAddress),
+		BatchMerkleRoot:              batchMerkleRoot,
+		SenderAddress:                senderAddress,
+		ReferenceBlock:               referenceBlock,
+		QuorumNumbers:                make([]uint32, 0, len(quorumNumbers)),
+		QuorumThresholdPercentages:   make([]uint32, 0, len(quorumThresholdPercentages)),
+		Signers:                      make([]common.Hash, 0, len(signers)),
+		NonSigners:                   make([]common.Hash, 0, len(nonSignerStakesAndSignature.NonSignerPubkeys)),
+		NonSignerPubkeys:             make([]G1Point, 0, len(nonSignerStakesAndSignature.NonSignerPubkeys)),
+		QuorumApks:                   make([]G1Point, 0, len(nonSignerStakesAndSignature.QuorumApks)),
+		ApkG2:                        newG2Point(nonSignerStakesAndSignature.ApkG2),
+		Sigma:                        newG1Point(nonSignerStakesAndSignature.Sigma),
+		NonSignerQuorumBitmapIndices: nonSignerStakesAndSignature.NonSignerQuorumBitmapIndices,
+		QuorumApkIndices:             nonSignerStakesAndSignature.QuorumApkIndices,
+		TotalStakeIndices:            nonSignerStakesAndSignature.TotalStakeIndices,
+		NonSignerStakeIndices:        nonSignerStakesAndSignature.NonSignerStakeIndices,
+		CreatedAt:                    createdAt,
+	}
+	for _, quorumNumber := range quorumNumbers {
+		certificate.QuorumNumbers = append(certificate.QuorumNumbers, uint32(quorumNumber))
+	}
+	for _, threshold := range quorumThresholdPercentages {
+		certificate.QuorumThresholdPercentages = append(certificate.QuorumThresholdPercentages, uint32(threshold))
+	}
+	for _, signer := range signers {
+		certificate.Signers = append(certificate.Signers, common.Hash(signer))
+	}
+	sort.Slice(certificate.Signers, func(i, j int) bool {
+		return certificate.Signers[i].Cmp(certificate.Signers[j]) < 0
+	})
+	for _, nonSignerPubkey := range nonSignerStakesAndSignature.NonSignerPubkeys {
+		pubkey := newG1Point(nonSignerPubkey)
+		certificate.NonSigners = append(certificate.NonSigners, common.Hash(OperatorId(pubkey)))
+		certificate.NonSignerPubkeys = append(certificate.NonSignerPubkeys, pubkey)
+	}
+	for _, quorumApk := range nonSignerStakesAndSignature.QuorumApks {
+		certificate.QuorumApks = append(certificate.QuorumApks, newG1Point(quorumApk))
+	}
+	return certificate
+}
+
+// NonSignerStakesAndSignature returns the certificate in the form the signature checker of the service manager takes
+func (c *Certificate) NonSignerStakesAndSignature() servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature {
+	params := servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature{
+		NonSignerQuorumBitmapIndices: c.NonSignerQuorumBitmapIndices,
+		NonSignerPubkeys:             make([]servicemanager.BN254G1Point, 0, len(c.NonSignerPubkeys)),
+		QuorumApks:                   make([]servicemanager.BN254G1Point, 0, len(c.QuorumApks)),
+		ApkG2:                        c.ApkG2.contractPoint(),
+		Sigma:                        c.Sigma.contractPoint(),
+		QuorumApkIndices:             c.QuorumApkIndices,
+		TotalStakeIndices:            c.TotalStakeIndices,
+		NonSignerStakeIndices:        c.NonSignerStakeIndices,
+	}
+	for _, nonSignerPubkey := range c.NonSignerPubkeys {
+		params.NonSignerPubkeys = append(params.NonSignerPubkeys, nonSignerPubkey.contractPoint())
+	}
+	for _, quorumApk := range c.QuorumApks {
+		params.QuorumApks = append(params.QuorumApks, quorumApk.contractPoint())
+	}
+	return params
+}
+
+// CertificateFileName is the name of the certificate of a task in the certificates directory
+func CertificateFileName(batchIdentifierHash [32]byte) string {
+	return hexutil.Encode(batchIdentifierHash[:]) + ".json"
+}
+
+// WriteCertificate writes the certificate to dir, named after its batch identifier hash, and returns its path.
+// It is written to a temporary file and then renamed, so readers never see a half written certificate
+func WriteCertificate(dir string, certificate *Certificate) (string, error) {
+	encoded, err := json.MarshalIndent(certificate, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, CertificateFileName(certificate.BatchIdentifierHash))
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, encoded, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", fmt.Errorf("failed to rename %s: %w", tmpPath, err)
+	}
+	return path, nil
+}
+
+// ReadCertificate reads a certificate written by WriteCertificate
+func ReadCertificate(path string) (*Certificate, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var certificate Certificate
+	if err := json.Unmarshal(file, &certificate); err != nil {
+		return nil, fmt.Errorf("failed to decode certificate %s: %w", path, err)
+	}
+	return &certificate, nil
+}
+
+// OperatorSetFileName is the name of the operator set at a reference block in the certificates directory
+func OperatorSetFileName(referenceBlock uint32) string {
+	return fmt.Sprintf("operators_%d.json", referenceBlock)
+}
+
+// WriteOperatorSet writes the G1 public keys of the operators registered in the quorum at the reference block,
+// the set the certificates of that block are verified against, and returns its path.
+// Like certificates, it is written to a temporary file and then renamed
+func WriteOperatorSet(dir string, referenceBlock uint32, operatorSet map[common.Hash]G1Point) (string, error) {
+	encodedKeys := make(map[string]G1Point, len(operatorSet))
+	for operatorId, pubkey := range operatorSet {
+		encodedKeys[operatorId.Hex()] = pubkey
+	}
+	encoded, err := json.MarshalIndent(encodedKeys, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, OperatorSetFileName(referenceBlock))
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, encoded, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", fmt.Errorf("failed to rename %s: %w", tmpPath, err)
+	}
+	return path, nil
+}
+
+// ReadOperatorSet reads an operator set file, a JSON object of operator id to G1 public key, as written by
+// WriteOperatorSet. Every key is checked to be the one of its operator id, as operator ids are the hash
+// of the G1 public key, so a set built from any source, as the registry at the reference block, can be used
+func ReadOperatorSet(path string) (map[common.Hash]G1Point, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var encodedKeys map[string]G1Point
+	if err := json.Unmarshal(file, &encodedKeys); err != nil {
+		return nil, fmt.Errorf("failed to decode operator set %s: %w", path, err)
+	}
+
+	keys := make(map[common.Hash]G1Point, len(encodedKeys))
+	for encodedOperatorId, pubkey := range encodedKeys {
+		operatorId := common.HexToHash(encodedOperatorId)
+		if pubkey.X == nil || pubkey.Y == nil {
+			return nil, fmt.Errorf("operator %s has an incomplete public key", operatorId)
+		}
+		if OperatorId(pubkey) != eigentypes.OperatorId(operatorId) {
+			return nil, fmt.Errorf("public key of operator %s does not match its operator id", operatorId)
+		}
+		keys[operatorId] = pubkey
+	}
+	return keys, nil
+}
+
+// BatchIdentifierHash returns keccak(batchMerkleRoot || senderAddress), the message the operators sign
+func BatchIdentifierHash(batchMerkleRoot [32]byte, senderAddress [20]byte) common.Hash {
+	return crypto.Keccak256Hash(batchMerkleRoot[:], senderAddress[:])
+}
+
+// OperatorId returns the id of the operator with the G1 public key
+func OperatorId(pubkey G1Point) eigentypes.OperatorId {
+	return eigentypes.OperatorIdFromG1Pubkey(pubkey.blsPoint())
+}
+
+// NewG1PointFromBls converts a G1 point of the BLS library, as the operator public keys in the registry
+func NewG1PointFromBls(point *bls.G1Point) G1Point {
+	return G1Point{
+		X: (*hexutil.Big)(point.X.BigInt(new(big.Int))),
+		Y: (*hexutil.Big)(point.Y.BigInt(new(big.Int))),
+	}
+}
+
+func newG1Point(point servicemanager.BN254G1Point) G1Point {
+	return G1Point{X: (*hexutil.Big)(point.X), Y: (*hexutil.Big)(point.Y)}
+}
+
+func newG2Point(point servicemanager.BN254G2Point) G2Point {
+	return G2Point{
+		X: [2]*hexutil.Big{(*hexutil.Big)(point.X[0]), (*hexutil.Big)(point.X[1])},
+		Y: [2]*hexutil.Big{(*hexutil.Big)(point.Y[0]), (*hexutil.Big)(point.Y[1])},
+	}
+}
+
+func (p G1Point) contractPoint() servicemanager.BN254G1Point {
+	return servicemanager.BN254G1Point{X: p.X.ToInt(), Y: p.Y.ToInt()}
+}
+
+func (p G2Point) contractPoint() servicemanager.BN254G2Point {
+	return servicemanager.BN254G2Point{
+		X: [2]*big.Int{p.X[0].ToInt(), p.X[1].ToInt()},
+		Y: [2]*big.Int{p.Y[0].ToInt(), p.Y[1].ToInt()},
+	}
+}
+
+func (p G1Point) blsPoint() *bls.G1Point {
+	return bls.NewG1Point(p.X.ToInt(), p.Y.ToInt())
+}
+
+func (p G2Point) blsPoint() *bls.G2Point {
+	// NewG2Point takes the coordinates in the contract order as well
+	return bls.NewG2Point([2]*big.Int{p.X[0].ToInt(), p.X[1].ToInt()}, [2]*big.Int{p.Y[0].ToInt(), p.Y[1].ToInt()})
+}
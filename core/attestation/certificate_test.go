@@ -0,0 +1,218 @@
+package attestation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// testCertificate returns the certificate of a task signed by two of three operators, and the operator set of all of them
+func testCertificate(t *testing.T) (*Certificate, map[common.Hash]G1Point) {
+	batchMerkleRoot, senderAddress := [32]byte{1}, [20]byte{2}
+	message := BatchIdentifierHash(batchMerkleRoot, senderAddress)
+
+	quorumApk, apkG2, sigma := bls.NewZeroG1Point(), bls.NewZeroG2Point(), bls.NewZeroSignature()
+	keys := make(map[common.Hash]G1Point)
+	var signers []eigentypes.OperatorId
+	var nonSignerPubkeys []servicemanager.BN254G1Point
+	for i := 0; i < 3; i++ {
+		keyPair, err := bls.GenRandomBlsKeys()
+		if err != nil {
+			t.Fatalf("Could not generate BLS keys: %v", err)
+		}
+		operatorId := eigentypes.OperatorIdFromG1Pubkey(keyPair.GetPubKeyG1())
+		keys[common.Hash(operatorId)] = NewG1PointFromBls(keyPair.GetPubKeyG1())
+		quorumApk.Add(keyPair.GetPubKeyG1())
+		if i == 2 {
+			nonSignerPubkeys = append(nonSignerPubkeys, utils.ConvertToBN254G1Point(keyPair.GetPubKeyG1()))
+			continue
+		}
+		signers = append(signers, operatorId)
+		apkG2.Add(keyPair.GetPubKeyG2())
+		sigma.Add(keyPair.SignMessage(message))
+	}
+
+	certificate := NewCertificate(batchMerkleRoot, senderAddress, 100, eigentypes.QuorumNums{0}, eigentypes.QuorumThresholdPercentages{67}, signers,
+		servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature{
+			NonSignerPubkeys:             nonSignerPubkeys,
+			QuorumApks:                   []servicemanager.BN254G1Point{utils.ConvertToBN254G1Point(quorumApk)},
+			ApkG2:                        utils.ConvertToBN254G2Point(apkG2),
+			Sigma:                        utils.ConvertToBN254G1Point(sigma.G1Point),
+			NonSignerQuorumBitmapIndices: []uint32{3},
+			QuorumApkIndices:             []uint32{4},
+			TotalStakeIndices:            []uint32{5},
+			NonSignerStakeIndices:        [][]uint32{{6}},
+		}, time.Now())
+	return certificate, keys
+}
+
+func TestVerifyOfflineAcceptsValidCertificate(t *testing.T) {
+	certificate, keys := testCertificate(t)
+	if err := VerifyOffline(certificate, keys); err != nil {
+		t.Fatalf("Expected a valid certificate, got %v", err)
+	}
+
+	// The certificate must survive being written and read back
+	path, err := WriteCertificate(t.TempDir(), certificate)
+	if err != nil {
+		t.Fatalf("Could not write certificate: %v", err)
+	}
+	read, err := ReadCertificate(path)
+	if err != nil {
+		t.Fatalf("Could not read certificate: %v", err)
+	}
+	if err := VerifyOffline(read, keys); err != nil {
+		t.Fatalf("Expected the read certificate to be valid, got %v", err)
+	}
+	if read.NonSignerStakesAndSignature().NonSignerStakeIndices[0][0] != 6 {
+		t.Errorf("Expected the stake indices to be kept")
+	}
+}
+
+func TestVerifyOfflineRejectsTamperedCertificates(t *testing.T) {
+	tests := map[string]func(certificate *Certificate, keys map[common.Hash]G1Point){
+		"other batch": func(certificate *Certificate, keys map[common.Hash]G1Point) {
+			certificate.BatchMerkleRoot = common.Hash{3}
+			certificate.BatchIdentifierHash = BatchIdentifierHash(certificate.BatchMerkleRoot, certificate.SenderAddress)
+		},
+		"identifier mismatch": func(certificate *Certificate, keys map[common.Hash]G1Point) {
+			certificate.BatchMerkleRoot = common.Hash{3}
+		},
+		"unknown signer": func(certificate *Certificate, keys map[common.Hash]G1Point) {
+			delete(keys, certificate.Signers[0])
+		},
+		"missing signer": func(certificate *Certificate, keys map[common.Hash]G1Point) {
+			certificate.Signers = certificate.Signers[1:]
+		},
+		"hidden non signer": func(certificate *Certificate, keys map[common.Hash]G1Point) {
+			certificate.NonSigners = nil
+			certificate.NonSignerPubkeys = nil
+		},
+		"forged single signer": func(certificate *Certificate, keys map[common.Hash]G1Point) {
+			// A registered operator signing alone, with its own key as the quorum apk
+			keyPair, _ := bls.GenRandomBlsKeys()
+			keys[common.Hash(eigentypes.OperatorIdFromG1Pubkey(keyPair.GetPubKeyG1()))] = NewG1PointFromBls(keyPair.GetPubKeyG1())
+			certificate.Signers = []common.Hash{common.Hash(eigentypes.OperatorIdFromG1Pubkey(keyPair.GetPubKeyG1()))}
+			certificate.NonSigners = nil
+			certificate.NonSignerPubkeys = nil
+			certificate.QuorumApks = []G1Point{NewG1PointFromBls(keyPair.GetPubKeyG1())}
+			certificate.ApkG2 = newG2Point(utils.ConvertToBN254G2Point(keyPair.GetPubKeyG2()))
+			certificate.Sigma = NewG1PointFromBls(keyPair.SignMessage(certificate.BatchIdentifierHash).G1Point)
+		},
+		"uncovered operator": func(certificate *Certificate, keys map[common.Hash]G1Point) {
+			keyPair, _ := bls.GenRandomBlsKeys()
+			keys[common.Hash(eigentypes.OperatorIdFromG1Pubkey(keyPair.GetPubKeyG1()))] = NewG1PointFromBls(keyPair.GetPubKeyG1())
+		},
+		"wrong quorum apk": func(certificate *Certificate, keys map[common.Hash]G1Point) {
+			certificate.QuorumApks = []G1Point{certificate.Sigma}
+		},
+		"unsupported version": func(certificate *Certificate, keys map[common.Hash]G1Point) {
+			certificate.Version = CertificateVersion + 1
+		},
+	}
+	for name, tamper := range tests {
+		t.Run(name, func(t *testing.T) {
+			certificate, keys := testCertificate(t)
+			tamper(certificate, keys)
+			if err := VerifyOffline(certificate, keys); !errors.Is(err, ErrInvalidCertificate) {
+				t.Errorf("Expected the certificate to be invalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReadOperatorSetChecksOperatorIds(t *testing.T) {
+	certificate, keys := testCertificate(t)
+	dir := t.TempDir()
+	path, err := WriteOperatorSet(dir, certificate.ReferenceBlock, keys)
+	if err != nil {
+		t.Fatalf("Could not write operator set: %v", err)
+	}
+	if path != filepath.Join(dir, OperatorSetFileName(certificate.ReferenceBlock)) {
+		t.Errorf("Expected the operator set of block %d, got %s", certificate.ReferenceBlock, path)
+	}
+	read, err := ReadOperatorSet(path)
+	if err != nil || len(read) != len(keys) {
+		t.Fatalf("Expected %d operators, got %d: %v", len(keys), len(read), err)
+	}
+	if err := VerifyOffline(certificate, read); err != nil {
+		t.Errorf("Expected the certificate to be valid with the read operator set, got %v", err)
+	}
+
+	// A key under the id of another operator must be rejected
+	encodedKeys := make(map[string]G1Point)
+	for operatorId, pubkey := range keys {
+		encodedKeys[operatorId.Hex()] = pubkey
+		encodedKeys[common.Hash{9}.Hex()] = pubkey
+	}
+	encoded, _ := json.Marshal(encodedKeys)
+	if err := os.WriteFile(path, encoded, 0644); err != nil {
+		t.Fatalf("Could not write operator set: %v", err)
+	}
+	if _, err := ReadOperatorSet(path); err == nil {
+		t.Errorf("Expected a key not matching its operator id to be rejected")
+	}
+}
+
+type fakeSignatureChecker struct {
+	signedStake, totalStake int64
+	threshold               uint8
+}
+
+func (c fakeSignatureChecker) QUORUMTHRESHOLDPERCENTAGE(opts *bind.CallOpts) (uint8, error) {
+	return c.threshold, nil
+}
+
+func (c fakeSignatureChecker) CheckSignatures(opts *bind.CallOpts, msgHash [32]byte, referenceBlockNumber uint32,
+	params servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature) (servicemanager.IBLSSignatureCheckerQuorumStakeTotals, [32]byte, error) {
+	return servicemanager.IBLSSignatureCheckerQuorumStakeTotals{
+		SignedStakeForQuorum: []*big.Int{big.NewInt(c.signedStake)},
+		TotalStakeForQuorum:  []*big.Int{big.NewInt(c.totalStake)},
+	}, [32]byte{}, nil
+}
+
+func (c fakeSignatureChecker) TrySignatureAndApkVerification(opts *bind.CallOpts, msgHash [32]byte, apk servicemanager.BN254G1Point,
+	apkG2 servicemanager.BN254G2Point, sigma servicemanager.BN254G1Point) (struct {
+	PairingSuccessful bool
+	SiganatureIsValid bool
+}, error) {
+	valid, err := (&bls.Signature{G1Point: bls.NewG1Point(sigma.X, sigma.Y)}).Verify(bls.NewG2Point(apkG2.X, apkG2.Y), msgHash)
+	return struct {
+		PairingSuccessful bool
+		SiganatureIsValid bool
+	}{err == nil, valid}, nil
+}
+
+func TestVerifyOnChainChecksThreshold(t *testing.T) {
+	certificate, _ := testCertificate(t)
+	if _, err := VerifyOnChain(context.Background(), fakeSignatureChecker{signedStake: 67, totalStake: 100}, certificate); err != nil {
+		t.Errorf("Expected the threshold to be reached, got %v", err)
+	}
+	if _, err := VerifyOnChain(context.Background(), fakeSignatureChecker{signedStake: 66, totalStake: 100}, certificate); !errors.Is(err, ErrInvalidCertificate) {
+		t.Errorf("Expected the certificate to be below the threshold, got %v", err)
+	}
+	// The threshold of the service manager applies when it is higher than the one of the certificate
+	if _, err := VerifyOnChain(context.Background(), fakeSignatureChecker{signedStake: 67, totalStake: 100, threshold: 70}, certificate); !errors.Is(err, ErrInvalidCertificate) {
+		t.Errorf("Expected the certificate to be below the service manager threshold, got %v", err)
+	}
+
+	if err := VerifySignatureOnChain(context.Background(), fakeSignatureChecker{}, certificate); err != nil {
+		t.Errorf("Expected the signature to be valid, got %v", err)
+	}
+	certificate.Sigma = certificate.QuorumApks[0]
+	if err := VerifySignatureOnChain(context.Background(), fakeSignatureChecker{}, certificate); !errors.Is(err, ErrInvalidCertificate) {
+		t.Errorf("Expected the signature to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package attestation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+// ErrInvalidCertificate is wrapped by every error returned when a certificate does not prove quorum.
+// Other errors, as failing to reach the RPC, mean the certificate could not be checked
+var ErrInvalidCertificate = errors.New("invalid certificate")
+
+// SignatureChecker is the part of the service manager binding the on-chain verification calls
+type SignatureChecker interface {
+	// QUORUMTHRESHOLDPERCENTAGE is the threshold respondToTaskV2 requires of the signed stake
+	QUORUMTHRESHOLDPERCENTAGE(opts *bind.CallOpts) (uint8, error)
+	CheckSignatures(opts *bind.CallOpts, msgHash [32]byte, referenceBlockNumber uint32,
+		params servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature) (servicemanager.IBLSSignatureCheckerQuorumStakeTotals, [32]byte, error)
+	TrySignatureAndApkVerification(opts *bind.CallOpts, msgHash [32]byte, apk servicemanager.BN254G1Point,
+		apkG2 servicemanager.BN254G2Point, sigma servicemanager.BN254G1Point) (struct {
+		PairingSuccessful bool
+		SiganatureIsValid bool
+	}, error)
+}
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidCertificate, fmt.Sprintf(format, args...))
+}
+
+// checkStructure checks the version, that every point is set, that the batch identifier hash matches
+// the merkle root and the sender, and that the non signers are the operators of the non signer public keys
+func (c *Certificate) checkStructure() error {
+	if c.Version != CertificateVersion {
+		return invalid("unsupported version %d, expected %d", c.Version, CertificateVersion)
+	}
+	if BatchIdentifierHash(c.BatchMerkleRoot, c.SenderAddress) != c.BatchIdentifierHash {
+		return invalid("batch identifier hash is not keccak(batch merkle root || sender address)")
+	}
+	if len(c.QuorumNumbers) == 0 || len(c.QuorumApks) != len(c.QuorumNumbers) || len(c.QuorumThresholdPercentages) != len(c.QuorumNumbers) {
+		return invalid("expected one quorum apk and one threshold per quorum")
+	}
+	if len(c.NonSignerPubkeys) != len(c.NonSigners) {
+		return invalid("expected one public key per non signer")
+	}
+
+	points := append([]G1Point{c.Sigma}, c.QuorumApks...)
+	points = append(points, c.NonSignerPubkeys...)
+	for _, point := range points {
+		if point.X == nil || point.Y == nil {
+			return invalid("incomplete G1 point")
+		}
+	}
+	for _, coordinate := range append(c.ApkG2.X[:], c.ApkG2.Y[:]...) {
+		if coordinate == nil {
+			return invalid("incomplete G2 point")
+		}
+	}
+
+	for i, pubkey := range c.NonSignerPubkeys {
+		if OperatorId(pubkey) != eigentypes.OperatorId(c.NonSigners[i]) {
+			return invalid("public key of non signer %s does not match its operator id", c.NonSigners[i])
+		}
+	}
+	return nil
+}
+
+// signersApkG1 returns the aggregated G1 public key of the signers, the quorum apk without the non signers.
+// Only single quorum certificates are supported: with several quorums, which quorums each non signer
+// is in is only known on-chain
+func (c *Certificate) signersApkG1() (*bls.G1Point, error) {
+	if len(c.QuorumApks) != 1 {
+		return nil, fmt.Errorf("the signers apk can only be derived from single quorum certificates, got %d quorums", len(c.QuorumApks))
+	}
+	apk := bls.NewZeroG1Point().Add(c.QuorumApks[0].blsPoint())
+	for _, nonSignerPubkey := range c.NonSignerPubkeys {
+		apk.Sub(nonSignerPubkey.blsPoint())
+	}
+	return apk, nil
+}
+
+// VerifyOffline checks the certificate against the operator set at its reference block, the G1 public keys of every
+// operator registered in the quorum then, as read by ReadOperatorSet. It checks that the signers and non signers are
+// exactly that set, that the quorum apk is the aggregation of all their keys, that the keys of the signers aggregate
+// to the G2 apk, and that sigma is their signature over the batch identifier hash.
+// Stakes are not known offline, so whether the signers reach the quorum threshold is only checked by VerifyOnChain
+func VerifyOffline(certificate *Certificate, operatorSet map[common.Hash]G1Point) error {
+	if err := certificate.checkStructure(); err != nil {
+		return err
+	}
+	if len(certificate.QuorumApks) != 1 {
+		return invalid("only single quorum certificates can be verified offline, got %d quorums", len(certificate.QuorumApks))
+	}
+	if len(certificate.Signers) == 0 {
+		return invalid("no signers")
+	}
+
+	covered := make(map[common.Hash]struct{}, len(operatorSet))
+	signersApk := bls.NewZeroG1Point()
+	for _, signer := range certificate.Signers {
+		pubkey, ok := operatorSet[signer]
+		if !ok {
+			return invalid("signer %s is not an operator at block %d", signer, certificate.ReferenceBlock)
+		}
+		if _, ok := covered[signer]; ok {
+			return invalid("operator %s is listed twice", signer)
+		}
+		covered[signer] = struct{}{}
+		signersApk.Add(pubkey.blsPoint())
+	}
+	for _, nonSigner := range certificate.NonSigners {
+		if _, ok := operatorSet[nonSigner]; !ok {
+			return invalid("non signer %s is not an operator at block %d", nonSigner, certificate.ReferenceBlock)
+		}
+		if _, ok := covered[nonSigner]; ok {
+			return invalid("operator %s is listed twice", nonSigner)
+		}
+		covered[nonSigner] = struct{}{}
+	}
+	if len(covered) != len(operatorSet) {
+		return invalid("%d operators at block %d are neither signers nor non signers", len(operatorSet)-len(covered), certificate.ReferenceBlock)
+	}
+
+	// The quorum apk is taken from the operator set, not from the certificate, which could carry any key
+	quorumApk := bls.NewZeroG1Point()
+	for _, pubkey := range operatorSet {
+		quorumApk.Add(pubkey.blsPoint())
+	}
+	if !quorumApk.G1Affine.Equal(certificate.QuorumApks[0].blsPoint().G1Affine) {
+		return invalid("the quorum apk is not the aggregated key of the operators at block %d", certificate.ReferenceBlock)
+	}
+
+	apkG2 := certificate.ApkG2.blsPoint()
+	equivalent, err := signersApk.VerifyEquivalence(apkG2)
+	if err != nil || !equivalent {
+		return invalid("the signers public keys do not aggregate to the G2 apk")
+	}
+
+	valid, err := (&bls.Signature{G1Point: certificate.Sigma.blsPoint()}).Verify(apkG2, certificate.BatchIdentifierHash)
+	if err != nil || !valid {
+		return invalid("sigma is not the signature of the signers over the batch identifier hash")
+	}
+	return nil
+}
+
+// OnChainVerification is the result of checking a certificate with checkSignatures
+type OnChainVerification struct {
+	SignedStakeForQuorum []*big.Int
+	TotalStakeForQuorum  []*big.Int
+}
+
+// VerifyOnChain checks the certificate with an eth_call to checkSignatures of the service manager, which runs
+// the same checks as respondToTaskV2 with the stakes at the reference block, and then checks that the signed
+// stake of every quorum reaches both its threshold in the certificate and the one of the service manager.
+// checkSignatures only accepts recent reference blocks, see VerifySignatureOnChain for older certificates
+func VerifyOnChain(ctx context.Context, checker SignatureChecker, certificate *Certificate) (*OnChainVerification, error) {
+	if err := certificate.checkStructure(); err != nil {
+		return nil, err
+	}
+
+	stakeTotals, _, err := checker.CheckSignatures(&bind.CallOpts{Context: ctx}, certificate.BatchIdentifierHash,
+		certificate.ReferenceBlock, certificate.NonSignerStakesAndSignature())
+	if err != nil {
+		return nil, fmt.Errorf("checkSignatures call failed: %w", err)
+	}
+	verification := &OnChainVerification{
+		SignedStakeForQuorum: stakeTotals.SignedStakeForQuorum,
+		TotalStakeForQuorum:  stakeTotals.TotalStakeForQuorum,
+	}
+	if len(stakeTotals.SignedStakeForQuorum) != len(certificate.QuorumNumbers) || len(stakeTotals.TotalStakeForQuorum) != len(certificate.QuorumNumbers) {
+		return verification, invalid("checkSignatures returned stakes for %d quorums, expected %d", len(stakeTotals.SignedStakeForQuorum), len(certificate.QuorumNumbers))
+	}
+
+	serviceManagerThreshold, err := checker.QUORUMTHRESHOLDPERCENTAGE(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return verification, fmt.Errorf("QUORUM_THRESHOLD_PERCENTAGE call failed: %w", err)
+	}
+
+	// Same check as respondToTaskV2: signedStake * 100 >= totalStake * threshold
+	for i, threshold := range certificate.QuorumThresholdPercentages {
+		threshold = max(threshold, uint32(serviceManagerThreshold))
+		signed := new(big.Int).Mul(stakeTotals.SignedStakeForQuorum[i], big.NewInt(100))
+		required := new(big.Int).Mul(stakeTotals.TotalStakeForQuorum[i], big.NewInt(int64(threshold)))
+		if signed.Cmp(required) < 0 {
+			return verification, invalid("signed stake of quorum %d is below its %d%% threshold", certificate.QuorumNumbers[i], threshold)
+		}
+	}
+	return verification, nil
+}
+
+// VerifySignatureOnChain checks only the signature with an eth_call to trySignatureAndApkVerification, taking
+// the signers apk from the quorum apk and the non signers. Unlike VerifyOnChain it works for any reference block,
+// but it does not check the apks and stakes against the registry
+func VerifySignatureOnChain(ctx context.Context, checker SignatureChecker, certificate *Certificate) error {
+	if err := certificate.checkStructure(); err != nil {
+		return err
+	}
+	apk, err := certificate.signersApkG1()
+	if err != nil {
+		return invalid("%v", err)
+	}
+
+	result, err := checker.TrySignatureAndApkVerification(&bind.CallOpts{Context: ctx}, certificate.BatchIdentifierHash,
+		NewG1PointFromBls(apk).contractPoint(), certificate.ApkG2.contractPoint(), certificate.Sigma.contractPoint())
+	if err != nil {
+		return fmt.Errorf("trySignatureAndApkVerification call failed: %w", err)
+	}
+	if !result.PairingSuccessful {
+		return invalid("pairing precompile failed")
+	}
+	if !result.SiganatureIsValid {
+		return invalid("sigma is not the signature of the signers over the batch identifier hash")
+	}
+	return nil
+}
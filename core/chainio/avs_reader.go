@@ -2,7 +2,6 @@ package chainio
 
 import (
 	"context"
-	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -109,44 +108,36 @@ func (r *AvsReader) GetNotRespondedTasksFrom(fromBlock uint64) ([]servicemanager
 	return tasks, nil
 }
 
-// This function is a helper to get a task hash of aproximately nBlocksOld blocks ago
-func (r *AvsReader) GetOldTaskHash(nBlocksOld uint64, interval uint64) (*[32]byte, error) {
-	latestBlock, err := r.AvsContractBindings.ethClient.BlockNumber(context.Background())
+// Returns all the "NewBatchV2" logs that have not been responded starting from the given block number
+func (r *AvsReader) GetNotRespondedTasksV2From(fromBlock uint64) ([]servicemanager.ContractAlignedLayerServiceManagerNewBatchV2, error) {
+	logs, err := r.AvsContractBindings.ServiceManager.FilterNewBatchV2(&bind.FilterOpts{Start: fromBlock, End: nil, Context: context.Background()}, nil)
+
 	if err != nil {
-		latestBlock, err = r.AvsContractBindings.ethClientFallback.BlockNumber(context.Background())
-		if err != nil {
-			return nil, fmt.Errorf("failed to get latest block number: %w", err)
-		}
+		return nil, err
 	}
 
-	if latestBlock < nBlocksOld {
-		return nil, fmt.Errorf("latest block is less than nBlocksOld")
-	}
+	var tasks []servicemanager.ContractAlignedLayerServiceManagerNewBatchV2
 
-	// Define block number limits to query the rpc
-	var fromBlock uint64
+	for logs.Next() {
+		task, err := r.AvsContractBindings.ServiceManager.ParseNewBatchV2(logs.Event.Raw)
+		if err != nil {
+			return nil, err
+		}
 
-	toBlock := latestBlock - nBlocksOld
-	fromBlock = toBlock - interval
+		// now check if its finalized or not before appending
+		batchIdentifier := append(task.BatchMerkleRoot[:], task.SenderAddress[:]...)
+		batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+		state, err := r.AvsContractBindings.ServiceManager.ContractAlignedLayerServiceManagerCaller.BatchesState(nil, batchIdentifierHash)
 
-	logs, err := r.AvsContractBindings.ServiceManager.FilterNewBatchV3(&bind.FilterOpts{Start: fromBlock, End: &toBlock, Context: context.Background()}, nil)
-	if err != nil {
-		return nil, err
-	}
-	if err := logs.Error(); err != nil {
-		return nil, err
-	}
-	if !logs.Next() {
-		return nil, nil //not an error, but no tasks found
-	}
+		if err != nil {
+			return nil, err
+		}
 
-	// Any log from the list is good enough.
-	task, err := r.AvsContractBindings.ServiceManager.ParseNewBatchV3(logs.Event.Raw)
-	if err != nil {
-		return nil, err
+		// append the task if not responded yet
+		if !state.Responded {
+			tasks = append(tasks, *task)
+		}
 	}
 
-	batchIdentifier := append(task.BatchMerkleRoot[:], task.SenderAddress[:]...)
-	batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
-	return &batchIdentifierHash, nil
+	return tasks, nil
 }
@@ -3,6 +3,7 @@ package chainio
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
 	retry "github.com/yetanotherco/aligned_layer/core"
 	"github.com/yetanotherco/aligned_layer/core/config"
@@ -29,6 +31,9 @@ type AvsWriter struct {
 	Signer              signer.Signer
 	Client              eth.InstrumentedClient
 	ClientFallback      eth.InstrumentedClient
+	Wallets             *WalletPool    // Accounts the responses are sent from, the first one is the Signer account
+	GasPolicy           *GasPolicy     // Limits the gas spent in responses, nil to send them regardless of the cost
+	Broadcaster         *TxBroadcaster // Sends the responses to several endpoints, nil to send them through Client only
 	metrics             *metrics.Metrics
 }
 
@@ -65,7 +70,7 @@ func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.E
 
 	chainWriter := clients.AvsRegistryChainWriter
 
-	return &AvsWriter{
+	avsWriter := &AvsWriter{
 		ChainWriter:         chainWriter,
 		AvsContractBindings: avsServiceBindings,
 		logger:              baseConfig.Logger,
@@ -73,75 +78,167 @@ func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.E
 		Client:              baseConfig.EthRpcClient,
 		ClientFallback:      baseConfig.EthRpcClientFallback,
 		metrics:             metrics,
-	}, nil
+	}
+	wallets := []*SenderWallet{{
+		Signer:       privateKeySigner,
+		Address:      privateKeySigner.GetTxOpts().From,
+		NonceManager: NewNonceManager(privateKeySigner.GetTxOpts().From, &avsWriter.Client, &avsWriter.ClientFallback),
+	}}
+	for _, privateKey := range ecdsaConfig.AdditionalPrivateKeys {
+		additionalSigner, err := signer.NewPrivateKeySigner(privateKey, baseConfig.ChainId)
+		if err != nil {
+			baseConfig.Logger.Error("Cannot create signer of additional wallet", "err", err)
+			return nil, err
+		}
+		address := additionalSigner.GetTxOpts().From
+		wallets = append(wallets, &SenderWallet{
+			Signer:       additionalSigner,
+			Address:      address,
+			NonceManager: NewNonceManager(address, &avsWriter.Client, &avsWriter.ClientFallback),
+		})
+	}
+	avsWriter.Wallets = NewWalletPool(wallets)
+	return avsWriter, nil
 }
 
 // SendAggregatedResponse continuously sends a RespondToTask transaction until it is included in the blockchain.
+// Several responses can be sent concurrently, each from the wallet picked by the WalletPool with its own nonce.
 // This function:
-//  1. Simulates the transaction to calculate the nonce and initial gas price without broadcasting it.
+//  1. Simulates the transaction from the wallet picked, without broadcasting it.
+//     Wallets rejected by the service manager as sender are skipped.
 //  2. Repeatedly attempts to send the transaction, bumping the gas price after `timeToWaitBeforeBump` has passed.
+//     Transactions are sent through the Broadcaster, if set.
 //  3. Monitors for the receipt of previously sent transactions or checks the state to confirm if the response
 //     has already been processed (e.g., by another transaction).
 //  4. Validates that the aggregator and batcher have sufficient balance to cover transaction costs before sending.
+//  5. Checks the transaction against the GasPolicy, if set, which may defer the response.
+//
+// If dynamicFeeParams is nil, legacy transactions are sent with a gas price. Otherwise EIP-1559 transactions are sent,
+// with fees estimated from the fee history, and onSetGasPrice receives the fee cap.
 //
 // Returns:
 //   - A transaction receipt if the transaction is successfully included in the blockchain.
-//   - If no receipt is found, but the batch state indicates the response has already been processed, it exits
-//     without an error (returning `nil, nil`).
+//   - If no receipt is found, but the batch state indicates the response has already been processed, or the contract
+//     reverts with BatchAlreadyResponded, it exits without an error (returning `nil, nil`).
 //   - An error if the process encounters a fatal issue (e.g., permanent failure in verifying balances or state).
-func (w *AvsWriter) SendAggregatedResponse(batchIdentifierHash [32]byte, batchMerkleRoot [32]byte, senderAddress [20]byte, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature, gasBumpPercentage uint, gasBumpIncrementalPercentage uint, gasBumpPercentageLimit uint, timeToWaitBeforeBump time.Duration, metrics *metrics.Metrics, onSetGasPrice func(*big.Int)) (*types.Receipt, error) {
-	txOpts := *w.Signer.GetTxOpts()
-	txOpts.NoSend = true // simulate the transaction
-	simTx, err := w.RespondToTaskV2Retryable(&txOpts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature, retry.SendToChainRetryParams())
-	if err != nil {
-		return nil, err
+//     Reverts are returned as a *RevertError, which can be matched with errors.Is, e.g. against ErrInvalidSignature.
+//     Responses deferred by the GasPolicy return an error matching ErrGasPolicyDeferred.
+func (w *AvsWriter) SendAggregatedResponse(batchIdentifierHash [32]byte, batchMerkleRoot [32]byte, senderAddress [20]byte, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature, gasBumpPercentage uint, gasBumpIncrementalPercentage uint, gasBumpPercentageLimit uint, timeToWaitBeforeBump time.Duration, dynamicFeeParams *utils.DynamicFeeParams, metrics *metrics.Metrics, onSetGasPrice func(*big.Int), onSentTx func(*types.Transaction)) (*types.Receipt, error) {
+	var wallet *SenderWallet
+	var nonce uint64
+	nonceHeld := false
+	// Whether a transaction was broadcast with the held nonce
+	nonceBroadcast := false
+	var sentTxs []*types.Transaction
+	// The nonce is used if the receipt is of its transaction. A nonce broadcast without a receipt may still be mined,
+	// so it's abandoned for the node to tell. A nonce never broadcast is given back, and if later nonces were
+	// already handed out it's cancelled, as their transactions are stuck until it's used
+	var receipt *types.Receipt
+	defer func() {
+		if !nonceHeld {
+			return
+		}
+		switch {
+		case receipt != nil && isTxOfNonce(sentTxs, receipt.TxHash, nonce):
+			wallet.NonceManager.Done(nonce)
+		case nonceBroadcast:
+			wallet.NonceManager.Abandon(nonce)
+		case wallet.NonceManager.HasLaterNonces(nonce):
+			if err := w.cancelNonce(wallet, nonce); err != nil {
+				w.logger.Warnf("Failed to cancel unused nonce %d, err %v", nonce, err)
+				wallet.NonceManager.Release(nonce)
+				return
+			}
+			wallet.NonceManager.Abandon(nonce)
+		default:
+			wallet.NonceManager.Release(nonce)
+		}
+	}()
+
+	// The nonce is only taken right before sending, the simulation uses the pending nonce of the node
+	var err error
+	var txOpts bind.TransactOpts
+	var simTx *types.Transaction
+	for attempt := 1; ; attempt++ {
+		wallet, err = w.Wallets.Pick()
+		if err != nil {
+			return nil, err
+		}
+		txOpts = *wallet.Signer.GetTxOpts()
+		txOpts.NoSend = true // simulate the transaction
+		simTx, err = w.RespondToTaskV2Retryable(&txOpts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature, retry.SendToChainRetryParams())
+		if errors.Is(err, ErrSenderIsNotAggregator) {
+			w.logger.Errorf("Wallet %s is not allowed to respond to tasks by the service manager, skipping it", wallet.Address.Hex())
+			w.Wallets.Disallow(wallet)
+			if attempt < len(w.Wallets.Wallets()) {
+				continue
+			}
+		}
+		if errors.Is(err, ErrBatchAlreadyResponded) {
+			w.logger.Infof("Batch already responded, not sending the response", "merkle root", hex.EncodeToString(batchMerkleRoot[:]))
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
 	}
 
-	// Set the nonce, as we might have to replace the transaction with a higher gas price
-	txNonce := big.NewInt(int64(simTx.Nonce()))
-	txOpts.Nonce = txNonce
 	txOpts.GasPrice = nil
 	txOpts.NoSend = false
 	i := 0
 
-	var sentTxs []*types.Transaction
+	// The fee limit does not change, so it is only fetched once for the gas policy
+	var respondToTaskFeeLimit *big.Int
+	if w.GasPolicy != nil {
+		defer w.GasPolicy.release(batchIdentifierHash)
+		batchState, err := w.BatchesStateRetryable(&bind.CallOpts{}, batchIdentifierHash, retry.NetworkRetryParams())
+		if err == nil {
+			respondToTaskFeeLimit = batchState.RespondToTaskFeeLimit
+		}
+	}
+
+	var previousFees *utils.DynamicFees
 
 	batchMerkleRootHashString := hex.EncodeToString(batchMerkleRoot[:])
 
 	respondToTaskV2Func := func() (*types.Receipt, error) {
-		gasPrice, err := utils.GetGasPriceRetryable(w.Client, w.ClientFallback, retry.NetworkRetryParams())
-		if err != nil {
-			return nil, err
-		}
-
-		// if txOpts.GasPrice wasn't previously set use the fetched gasPrice
-		// this should happen on the first iteration only
-		var previousTxGasPrice *big.Int
-		if txOpts.GasPrice == nil {
-			previousTxGasPrice = gasPrice
+		if dynamicFeeParams != nil {
+			fees, err := utils.GetDynamicFeesRetryable(w.Client, w.ClientFallback, *dynamicFeeParams, retry.NetworkRetryParams())
+			if err != nil {
+				return nil, err
+			}
+			bumpedFees := utils.CalculateDynamicFeesBumpBasedOnRetry(*fees, previousFees, gasBumpPercentage, gasBumpIncrementalPercentage, gasBumpPercentageLimit, i)
+			previousFees = &bumpedFees
+			txOpts.GasFeeCap = bumpedFees.GasFeeCap
+			txOpts.GasTipCap = bumpedFees.GasTipCap
+			onSetGasPrice(txOpts.GasFeeCap)
 		} else {
-			previousTxGasPrice = txOpts.GasPrice
-		}
+			gasPrice, err := utils.GetGasPriceRetryable(w.Client, w.ClientFallback, retry.NetworkRetryParams())
+			if err != nil {
+				return nil, err
+			}
 
-		// in order to avoid replacement transaction underpriced
-		// the bumped gas price has to be at least 10% higher than the previous one.
-		minimumGasPriceBump := utils.CalculateGasPriceBumpBasedOnRetry(previousTxGasPrice, 10, 0, gasBumpPercentageLimit, 0)
-		suggestedBumpedGasPrice := utils.CalculateGasPriceBumpBasedOnRetry(
-			gasPrice,
-			gasBumpPercentage,
-			gasBumpIncrementalPercentage,
-			gasBumpPercentageLimit,
-			i,
-		)
-		// check the new gas price is sufficiently bumped.
-		// if the suggested bump does not meet the minimum threshold, use a fallback calculation to slightly increment the previous gas price.
-		if suggestedBumpedGasPrice.Cmp(minimumGasPriceBump) > 0 {
-			txOpts.GasPrice = suggestedBumpedGasPrice
-		} else {
-			txOpts.GasPrice = minimumGasPriceBump
-		}
+			// txOpts.GasPrice is only set if a transaction was sent before
+			previousTxGasPrice := txOpts.GasPrice
+			txOpts.GasPrice = utils.CalculateGasPriceBumpBasedOnRetry(
+				gasPrice,
+				gasBumpPercentage,
+				gasBumpIncrementalPercentage,
+				gasBumpPercentageLimit,
+				i,
+			)
+			// in order to avoid replacement transaction underpriced,
+			// the bumped gas price has to be sufficiently higher than the previous one.
+			if previousTxGasPrice != nil {
+				minimumGasPriceBump := utils.CalculateReplacementFee(previousTxGasPrice)
+				if txOpts.GasPrice.Cmp(minimumGasPriceBump) < 0 {
+					txOpts.GasPrice = minimumGasPriceBump
+				}
+			}
 
-		onSetGasPrice(txOpts.GasPrice)
+			onSetGasPrice(txOpts.GasPrice)
+		}
 
 		if i > 0 {
 			w.logger.Infof("Trying to get old sent transaction receipt before sending a new transaction", "merkle root", batchMerkleRootHashString)
@@ -149,10 +246,11 @@ func (w *AvsWriter) SendAggregatedResponse(batchIdentifierHash [32]byte, batchMe
 				receipt, _ := w.Client.TransactionReceipt(context.Background(), tx.Hash())
 				if receipt == nil {
 					receipt, _ = w.ClientFallback.TransactionReceipt(context.Background(), tx.Hash())
-					if receipt != nil {
-						w.updateAggregatorGasCostMetrics(receipt, batchIdentifierHash)
-						return receipt, nil
-					}
+				}
+				if receipt != nil {
+					w.updateAggregatorGasCostMetrics(receipt, batchIdentifierHash)
+					w.broadcastIncluded(receipt)
+					return receipt, nil
 				}
 			}
 			w.logger.Infof("Receipts for old transactions not found, will check if the batch state has been responded", "merkle root", batchMerkleRootHashString)
@@ -168,24 +266,84 @@ func (w *AvsWriter) SendAggregatedResponse(batchIdentifierHash [32]byte, batchMe
 
 		// We compare both Aggregator funds and Batcher balance in Aligned against respondToTaskFeeLimit
 		// Both are required to have some balance, more details inside the function
-		err = w.checkAggAndBatcherHaveEnoughBalance(simTx, txOpts, batchIdentifierHash, senderAddress)
+		err := w.checkAggAndBatcherHaveEnoughBalance(simTx, txOpts, batchIdentifierHash, senderAddress)
 		if err != nil {
 			w.logger.Errorf("Permanent error when checking aggregator and batcher balances, err %v", err, "merkle root", batchMerkleRootHashString)
 			return nil, retry.PermanentError{Inner: err}
 		}
 
-		w.logger.Infof("Sending RespondToTask transaction with a gas price of %v", txOpts.GasPrice, "merkle root", batchMerkleRootHashString)
-		realTx, err := w.RespondToTaskV2Retryable(&txOpts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature, retry.SendToChainRetryParams())
+		err = w.applyGasPolicy(simTx, txOpts, respondToTaskFeeLimit, len(sentTxs) > 0, batchIdentifierHash, batchMerkleRootHashString)
+		if err != nil {
+			return nil, retry.PermanentError{Inner: err}
+		}
+
+		// The nonce is taken once no old transaction was mined and the transaction passed every check,
+		// so a response that is not sent doesn't leave a gap. It's kept for the bumps of the transaction
+		if !nonceHeld {
+			newNonce, err := wallet.NonceManager.Acquire()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get nonce: %w", err)
+			}
+			nonce = newNonce
+			nonceHeld = true
+			nonceBroadcast = false
+			txOpts.Nonce = new(big.Int).SetUint64(nonce)
+		}
+
+		if dynamicFeeParams != nil {
+			w.logger.Infof("Sending RespondToTask transaction with a fee cap of %v and a priority fee of %v", txOpts.GasFeeCap, txOpts.GasTipCap, "merkle root", batchMerkleRootHashString)
+		} else {
+			w.logger.Infof("Sending RespondToTask transaction with a gas price of %v", txOpts.GasPrice, "merkle root", batchMerkleRootHashString)
+		}
+		realTx, err := w.sendRespondToTask(&txOpts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature)
+		if realTx != nil {
+			// Tracked even if an error is returned along, as it may still be mined
+			sentTxs = append(sentTxs, realTx)
+			nonceBroadcast = true
+			onSentTx(realTx)
+			if w.metrics != nil {
+				w.metrics.IncWalletTransactions(txOpts.From.Hex())
+			}
+		}
+		if errors.Is(err, ErrBatchAlreadyResponded) {
+			w.logger.Infof("Batch already responded, not sending a new tx", "merkle root", batchMerkleRootHashString)
+			return nil, nil
+		}
+		if isReplacementUnderpriced(err) {
+			// A transaction with this nonce is pending, it's replaced with fees bumped one more time
+			w.logger.Warnf("Replacement of nonce %d underpriced, bumping the fees again, err %v", nonce, err, "merkle root", batchMerkleRootHashString)
+			i++
+			return nil, err
+		}
+		if isNonceError(err) {
+			// The nonce was taken by a transaction sent outside the NonceManager, or the node dropped the previous ones
+			w.logger.Warnf("Nonce %d rejected, syncing nonces with the node, err %v", nonce, err, "merkle root", batchMerkleRootHashString)
+			if nonceBroadcast {
+				wallet.NonceManager.Abandon(nonce)
+			} else {
+				wallet.NonceManager.Release(nonce)
+				wallet.NonceManager.Resync()
+			}
+			nonceHeld = false
+			if w.metrics != nil {
+				w.metrics.IncNonceResyncs()
+			}
+			return nil, err
+		}
 		if err != nil {
 			w.logger.Errorf("Respond to task transaction err, %v", err, "merkle root", batchMerkleRootHashString)
+			// Sending it again with a higher gas price would revert again
+			if revertErr := DecodeRevertError(err); revertErr != nil && revertErr.Permanent() {
+				return nil, retry.PermanentError{Inner: err}
+			}
 			return nil, err
 		}
-		sentTxs = append(sentTxs, realTx)
 
 		w.logger.Infof("Transaction sent, waiting for receipt", "merkle root", batchMerkleRootHashString)
 		receipt, err := utils.WaitForTransactionReceiptRetryable(w.Client, w.ClientFallback, realTx.Hash(), retry.WaitForTxRetryParams(timeToWaitBeforeBump))
 		if receipt != nil {
 			w.updateAggregatorGasCostMetrics(receipt, batchIdentifierHash)
+			w.broadcastIncluded(receipt)
 			return receipt, nil
 		}
 
@@ -203,21 +361,141 @@ func (w *AvsWriter) SendAggregatedResponse(batchIdentifierHash [32]byte, batchMe
 	// This just retries the bump of a fee in case of a timeout
 	// The wait is done before on WaitForTransactionReceiptRetryable, and all the functions are retriable,
 	// so this retry doesn't need to wait more time
-	return retry.RetryWithData(respondToTaskV2Func, retry.RespondToTaskV2())
+	receipt, err = retry.RetryWithData(respondToTaskV2Func, retry.RespondToTaskV2())
+	return receipt, err
+}
+
+// isTxOfNonce reports whether txHash is one of the sent transactions with the nonce
+func isTxOfNonce(sentTxs []*types.Transaction, txHash common.Hash, nonce uint64) bool {
+	for _, tx := range sentTxs {
+		if tx.Hash() == txHash {
+			return tx.Nonce() == nonce
+		}
+	}
+	return false
+}
+
+// cancelNonce sends an empty transfer from the wallet to itself with the nonce, so the transactions sent
+// with later nonces are not stuck behind a nonce that is no longer used
+func (w *AvsWriter) cancelNonce(wallet *SenderWallet, nonce uint64) error {
+	txOpts := wallet.Signer.GetTxOpts()
+	gasPrice, err := utils.GetGasPriceRetryable(w.Client, w.ClientFallback, retry.NetworkRetryParams())
+	if err != nil {
+		return err
+	}
+	tx, err := txOpts.Signer(txOpts.From, types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      params.TxGas,
+		To:       &txOpts.From,
+		Value:    big.NewInt(0),
+	}))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := w.Client.SendTransaction(ctx, tx); err != nil {
+		if fallbackErr := w.ClientFallback.SendTransaction(ctx, tx); fallbackErr != nil {
+			return fallbackErr
+		}
+	}
+	w.logger.Infof("Cancelled unused nonce %d", nonce, "txHash", tx.Hash().String())
+	return nil
 }
 
-// Calculates the transaction cost from the receipt and updates the total amount paid by the aggregator metric
+// sendRespondToTask sends the RespondToTask transaction through the Broadcaster if set, otherwise through Client.
+// With the Broadcaster, the transaction is only signed by the contract binding and then broadcast as a raw transaction,
+// retrying while no endpoint accepts it.
+// The transaction is returned whenever it may have been sent, even along with an error,
+// e.g. when the aggregator RPC rejected its nonce but a private relay accepted it
+func (w *AvsWriter) sendRespondToTask(txOpts *bind.TransactOpts, batchMerkleRoot [32]byte, senderAddress [20]byte, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature) (*types.Transaction, error) {
+	if w.Broadcaster == nil {
+		return w.RespondToTaskV2Retryable(txOpts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature, retry.SendToChainRetryParams())
+	}
+	signOpts := *txOpts
+	signOpts.NoSend = true
+	tx, err := w.RespondToTaskV2Retryable(&signOpts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature, retry.SendToChainRetryParams())
+	if err != nil {
+		return nil, err
+	}
+	broadcast_func := func() (bool, error) {
+		sent, err := w.Broadcaster.Broadcast(context.Background(), tx)
+		if err != nil && (sent || isNonceError(err) || isReplacementUnderpriced(err)) {
+			// Broadcasting the same transaction again would not change the outcome
+			return sent, retry.PermanentError{Inner: err}
+		}
+		return sent, err
+	}
+	sent, err := retry.RetryWithData(broadcast_func, retry.SendToChainRetryParams())
+	if !sent {
+		return nil, err
+	}
+	return tx, err
+}
+
+// ErrNonceTaken is returned by RebroadcastTransaction when the nonce of the transaction was used by another one
+var ErrNonceTaken = errors.New("nonce taken by another transaction")
+
+// RebroadcastTransaction sends an already signed transaction again, through the Broadcaster if set, otherwise through Client.
+// Endpoints that still have it count as accepting it. Returns ErrNonceTaken if another transaction of the sender
+// was included with its nonce, so it can't be mined anymore
+func (w *AvsWriter) RebroadcastTransaction(ctx context.Context, tx *types.Transaction) error {
+	var err error
+	if w.Broadcaster != nil {
+		_, err = w.Broadcaster.Broadcast(ctx, tx)
+	} else {
+		err = w.Client.SendTransaction(ctx, tx)
+		if err != nil && !isAlreadyKnown(err) {
+			err = w.ClientFallback.SendTransaction(ctx, tx)
+		}
+		if err != nil && isAlreadyKnown(err) {
+			err = nil
+		}
+	}
+	if isNonceTooLow(err) {
+		return fmt.Errorf("%w: %v", ErrNonceTaken, err)
+	}
+	return err
+}
+
+func (w *AvsWriter) broadcastIncluded(receipt *types.Receipt) {
+	if w.Broadcaster == nil {
+		return
+	}
+	if endpoints := w.Broadcaster.Included(receipt.TxHash); len(endpoints) > 0 {
+		w.logger.Info("Respond to task transaction included", "txHash", receipt.TxHash.String(), "acceptedBy", endpoints)
+	}
+}
+
+// EstimateRespondToTaskGas simulates the RespondToTask transaction, without broadcasting it, and returns its gas.
+// Reverts are returned as a *RevertError
+func (w *AvsWriter) EstimateRespondToTaskGas(batchMerkleRoot [32]byte, senderAddress [20]byte, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature) (uint64, error) {
+	txOpts := *w.Signer.GetTxOpts()
+	txOpts.NoSend = true
+	simTx, err := w.RespondToTaskV2Retryable(&txOpts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature, retry.NetworkRetryParams())
+	if err != nil {
+		return 0, err
+	}
+	return simTx.Gas(), nil
+}
+
+// Calculates the transaction cost from the receipt, adds it to the GasPolicy spend budget and updates the total amount paid by the aggregator metric
 // Then, it compares that tx cost with the batcher respondToTaskFeeLimit.
 // If the tx cost was higher, it means the aggregator has paid the difference for the batcher (txCost - respondToTaskFeeLimit) and so metrics are updated accordingly.
 func (w *AvsWriter) updateAggregatorGasCostMetrics(receipt *types.Receipt, batchIdentifierHash [32]byte) {
+	txCost := new(big.Int).Mul(big.NewInt(int64(receipt.GasUsed)), receipt.EffectiveGasPrice)
+	if w.GasPolicy != nil {
+		w.GasPolicy.RecordSpend(txCost, time.Now())
+		w.metrics.SetGasSpendInWindow(utils.WeiToEth(w.GasPolicy.Spent(time.Now())))
+	}
+
 	batchState, err := w.BatchesStateRetryable(&bind.CallOpts{}, batchIdentifierHash, retry.NetworkRetryParams())
 	if err != nil {
 		return
 	}
 	respondToTaskFeeLimit := batchState.RespondToTaskFeeLimit
 
-	txCost := new(big.Int).Mul(big.NewInt(int64(receipt.GasUsed)), receipt.EffectiveGasPrice)
-
 	txCostInEth := utils.WeiToEth(txCost)
 	w.metrics.AddAggregatorGasCostPaidTotal(txCostInEth)
 
@@ -234,7 +512,11 @@ func (w *AvsWriter) checkAggAndBatcherHaveEnoughBalance(tx *types.Transaction, t
 	w.logger.Info("Checking if aggregator and batcher have enough balance for the transaction")
 	aggregatorAddress := txOpts.From
 	txGasAsBigInt := new(big.Int).SetUint64(tx.Gas())
+	// The fee cap is the most the transaction can cost per gas
 	txGasPrice := txOpts.GasPrice
+	if txGasPrice == nil {
+		txGasPrice = txOpts.GasFeeCap
+	}
 	txCost := new(big.Int).Mul(txGasAsBigInt, txGasPrice)
 	w.logger.Info("Transaction cost", "cost", txCost)
 
@@ -246,10 +528,17 @@ func (w *AvsWriter) checkAggAndBatcherHaveEnoughBalance(tx *types.Transaction, t
 	}
 	respondToTaskFeeLimit := batchState.RespondToTaskFeeLimit
 	w.logger.Info("Checking balance against Batch RespondToTaskFeeLimit", "RespondToTaskFeeLimit", respondToTaskFeeLimit)
-	// Note: we compare both Aggregator funds and Batcher balance in Aligned against respondToTaskFeeLimit
-	// Batcher will pay up to respondToTaskFeeLimit, for this he needs that amount of funds in Aligned
-	// Aggregator will pay any extra cost, for this he needs at least respondToTaskFeeLimit in his balance
-	return w.compareBalances(respondToTaskFeeLimit, aggregatorAddress, senderAddress)
+	// Note: Batcher will pay up to respondToTaskFeeLimit, for this he needs that amount of funds in Aligned
+	// Aggregator sends the transaction and pays any extra cost, for this he needs the highest of respondToTaskFeeLimit
+	// and the transaction cost at its gas price or fee cap, otherwise the node rejects it
+	aggregatorAmount := respondToTaskFeeLimit
+	if txCost.Cmp(aggregatorAmount) > 0 {
+		aggregatorAmount = txCost
+	}
+	if err := w.compareAggregatorBalance(aggregatorAmount, aggregatorAddress); err != nil {
+		return err
+	}
+	return w.compareBatcherBalance(respondToTaskFeeLimit, senderAddress)
 }
 
 func (w *AvsWriter) compareBalances(amount *big.Int, aggregatorAddress common.Address, senderAddress [20]byte) error {
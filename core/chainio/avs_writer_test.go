@@ -0,0 +1,211 @@
+package chainio
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	rpccalls "github.com/Layr-Labs/eigensdk-go/metrics/collectors/rpc_calls"
+	"github.com/Layr-Labs/eigensdk-go/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+// fakeChainService is a local stand-in of the eth namespace of a node with the service manager deployed.
+// Transactions are accepted into the mempool and only mined when the test says so
+type fakeChainService struct {
+	mutex        sync.Mutex
+	pendingNonce uint64
+	accepted     []*types.Transaction
+	receipts     map[common.Hash]*types.Receipt
+	// Called with every raw transaction, an error rejects it
+	onSend func(tx *types.Transaction) error
+}
+
+type fakeCallArgs struct {
+	Input hexutil.Bytes `json:"input"`
+	Data  hexutil.Bytes `json:"data"`
+}
+
+func (s *fakeChainService) GetBlockByNumber(number string, full bool) (*types.Header, error) {
+	// No base fee, so the contract bindings build legacy transactions
+	return &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}, nil
+}
+
+func (s *fakeChainService) GetTransactionCount(address common.Address, block string) (hexutil.Uint64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return hexutil.Uint64(s.pendingNonce), nil
+}
+
+func (s *fakeChainService) GasPrice() (*hexutil.Big, error) {
+	return (*hexutil.Big)(big.NewInt(1_000_000_000)), nil
+}
+
+func (s *fakeChainService) GetCode(address common.Address, block string) (hexutil.Bytes, error) {
+	return hexutil.Bytes{0x01}, nil
+}
+
+func (s *fakeChainService) EstimateGas(args fakeCallArgs) (hexutil.Uint64, error) {
+	return 300_000, nil
+}
+
+func (s *fakeChainService) GetBalance(address common.Address, block string) (*hexutil.Big, error) {
+	return (*hexutil.Big)(new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)), nil
+}
+
+func (s *fakeChainService) Call(args fakeCallArgs, block string) (hexutil.Bytes, error) {
+	input := args.Input
+	if len(input) == 0 {
+		input = args.Data
+	}
+	method, err := serviceManagerAbi.MethodById(input)
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "batchesState":
+		return method.Outputs.Pack(uint32(1), false, big.NewInt(1_000_000_000_000_000))
+	case "batchersBalances":
+		return method.Outputs.Pack(new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil))
+	}
+	return nil, errors.New("unexpected call to " + method.Name)
+}
+
+func (s *fakeChainService) SendRawTransaction(rawTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return common.Hash{}, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.onSend != nil {
+		if err := s.onSend(tx); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	s.accepted = append(s.accepted, tx)
+	return tx.Hash(), nil
+}
+
+func (s *fakeChainService) GetTransactionReceipt(txHash common.Hash) (*types.Receipt, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.receipts[txHash], nil
+}
+
+// mine includes the transaction in a block. The mutex must be held
+func (s *fakeChainService) mine(tx *types.Transaction) {
+	s.receipts[tx.Hash()] = &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		TxHash:            tx.Hash(),
+		GasUsed:           tx.Gas(),
+		EffectiveGasPrice: tx.GasPrice(),
+		BlockNumber:       big.NewInt(2),
+		Logs:              []*types.Log{},
+	}
+	s.pendingNonce = tx.Nonce() + 1
+}
+
+func newFakeChainClient(t *testing.T, service *fakeChainService) eth.InstrumentedClient {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatalf("Could not register fake chain: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(server.Stop)
+	client, err := eth.NewInstrumentedClient(httpServer.URL, rpccalls.NewCollector("test", prometheus.NewRegistry()))
+	if err != nil {
+		t.Fatalf("Could not dial fake chain: %v", err)
+	}
+	return *client
+}
+
+func newTestAvsWriter(t *testing.T, service *fakeChainService) *AvsWriter {
+	logger := logging.NewTextSLogger(io.Discard, nil)
+	client := newFakeChainClient(t, service)
+	bindings, err := NewAvsServiceBindings(common.Address{0x01}, common.Address{0x02}, client, client, logger)
+	if err != nil {
+		t.Fatalf("Could not create bindings: %v", err)
+	}
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Could not generate key: %v", err)
+	}
+	privateKeySigner, err := signer.NewPrivateKeySigner(privateKey, big.NewInt(31337))
+	if err != nil {
+		t.Fatalf("Could not create signer: %v", err)
+	}
+	writer := &AvsWriter{
+		AvsContractBindings: bindings,
+		logger:              logger,
+		Signer:              privateKeySigner,
+		Client:              client,
+		ClientFallback:      client,
+		metrics:             metrics.NewMetrics("", prometheus.NewRegistry(), logger),
+	}
+	address := privateKeySigner.GetTxOpts().From
+	writer.Wallets = NewWalletPool([]*SenderWallet{{
+		Signer:       privateKeySigner,
+		Address:      address,
+		NonceManager: NewNonceManager(address, &writer.Client, &writer.ClientFallback),
+	}})
+	return writer
+}
+
+// The bump of a pending response is rejected as underpriced, and the first transaction is mined meanwhile.
+// The response must end with the receipt of the first transaction, on the same nonce, leaving no gap
+func TestSendAggregatedResponseOldTransactionMinedAfterUnderpricedBump(t *testing.T) {
+	service := &fakeChainService{pendingNonce: 5, receipts: make(map[common.Hash]*types.Receipt)}
+	service.onSend = func(tx *types.Transaction) error {
+		if len(service.accepted) == 0 {
+			return nil
+		}
+		service.mine(service.accepted[0])
+		return errors.New("replacement transaction underpriced")
+	}
+	writer := newTestAvsWriter(t, service)
+
+	receipt, err := writer.SendAggregatedResponse([32]byte{0x01}, [32]byte{0x02}, [20]byte{0x03},
+		servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature{
+			ApkG2: servicemanager.BN254G2Point{X: [2]*big.Int{big.NewInt(0), big.NewInt(0)}, Y: [2]*big.Int{big.NewInt(0), big.NewInt(0)}},
+			Sigma: servicemanager.BN254G1Point{X: big.NewInt(0), Y: big.NewInt(0)},
+		},
+		10, 2, 100, 10*time.Millisecond, nil, writer.metrics, func(*big.Int) {}, func(*types.Transaction) {})
+	if err != nil {
+		t.Fatalf("Expected the response to be sent, got %v", err)
+	}
+
+	service.mutex.Lock()
+	accepted := service.accepted
+	service.mutex.Unlock()
+	if len(accepted) != 1 {
+		t.Fatalf("Expected only the first transaction to be accepted, got %d", len(accepted))
+	}
+	if receipt == nil || receipt.TxHash != accepted[0].Hash() {
+		t.Fatalf("Expected the receipt of the first transaction")
+	}
+	if accepted[0].Nonce() != 5 {
+		t.Errorf("Expected the first transaction to use the pending nonce 5, got %d", accepted[0].Nonce())
+	}
+	nonceManager := writer.Wallets.Wallets()[0].NonceManager
+	if nonceManager.InFlight() != 0 {
+		t.Errorf("Expected the nonce to be given back, got %d in flight", nonceManager.InFlight())
+	}
+	if nonce := acquireNonce(t, nonceManager); nonce != 6 {
+		t.Errorf("Expected nonce 6 with no gap, got %d", nonce)
+	}
+}
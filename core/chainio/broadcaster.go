@@ -0,0 +1,364 @@
+package chainio
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+// JSON-RPC methods the private relays are called with, set in the private_relay_method config
+const (
+	// eth_sendPrivateTransaction, the transaction is included by the relay within maxBlockNumber
+	PrivateRelayMethodPrivateTransaction = "private_transaction"
+	// eth_sendBundle, a bundle with only the transaction, sent once for each block until the sender bumps it
+	PrivateRelayMethodBundle = "bundle"
+)
+
+// Results of sending a transaction to a broadcast endpoint, used as metric labels
+const (
+	BroadcastResultAccepted = "accepted"
+	BroadcastResultRejected = "rejected"
+)
+
+// DefaultPrivateRelayAuthHeader is the header private relay requests are signed in when `private_relay_auth_header` is not set
+const DefaultPrivateRelayAuthHeader = "X-Flashbots-Signature"
+
+const (
+	broadcastTimeout = 10 * time.Second
+	// Private transactions not included within these blocks are dropped by the relay, and bumped by the sender
+	privateTransactionMaxBlocks = 25
+	// Sent transactions whose inclusion was not reported after this time are no longer tracked
+	broadcastTrackingPeriod = time.Hour
+	// Used to turn the bundle target period into the number of blocks bundles target
+	blockTime = 12 * time.Second
+)
+
+type transactionSender interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+type blockNumberReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// broadcastEndpoint is a destination signed transactions are sent to
+type broadcastEndpoint struct {
+	name string
+	send func(ctx context.Context, tx *types.Transaction) error
+	// Set on the aggregator RPCs, which check the nonce and fees of the transaction against the chain state
+	primary bool
+}
+
+// TxBroadcaster sends each signed transaction to every endpoint in parallel: the aggregator RPCs, extra public RPCs
+// and private relays. A transaction is sent if any endpoint accepts it, so one RPC silently dropping it does not
+// delay the response until the bump timeout.
+// The endpoints that accepted each transaction are tracked in the order they answered, so its inclusion is
+// credited to the first one, the one it reached the network from first
+type TxBroadcaster struct {
+	endpoints []broadcastEndpoint
+	// Endpoints that accepted each sent transaction, first to answer first, until it is included or stops being tracked
+	accepted map[common.Hash][]string
+	sentAt   map[common.Hash]time.Time
+	metrics  *metrics.Metrics
+	mutex    sync.Mutex
+}
+
+func newTxBroadcaster(endpoints []broadcastEndpoint, metrics *metrics.Metrics) *TxBroadcaster {
+	return &TxBroadcaster{
+		endpoints: endpoints,
+		accepted:  make(map[common.Hash][]string),
+		sentAt:    make(map[common.Hash]time.Time),
+		metrics:   metrics,
+	}
+}
+
+// TxBroadcasterConfig holds the endpoints of the TxBroadcaster, besides the aggregator RPCs
+type TxBroadcasterConfig struct {
+	// Public RPCs the raw transaction is sent to with eth_sendRawTransaction
+	RpcUrls []string
+	// Private relays the raw transaction is sent to with PrivateRelayMethod
+	PrivateRelayUrls   []string
+	PrivateRelayMethod string
+	// Skip the aggregator RPCs and the public RPCs, so the transaction never reaches the public mempool
+	PrivateRelayOnly bool
+	// Bundles target every block within this period, the time the sender waits before bumping the transaction.
+	// If 0, bundles only target the next block
+	BundleTargetPeriod time.Duration
+	// Key the private relay requests are signed with, the identity relays authenticate and rate limit the sender by.
+	// It only signs, it must not hold funds. If nil, requests are not signed
+	PrivateRelaySigningKey *ecdsa.PrivateKey
+	// Header the signature is sent in, DefaultPrivateRelayAuthHeader if empty
+	PrivateRelayAuthHeader string
+}
+
+// NewTxBroadcaster dials the configured endpoints. Unless PrivateRelayOnly is set, transactions are also sent
+// through the client and fallback client of the AvsWriter
+func (w *AvsWriter) NewTxBroadcaster(config TxBroadcasterConfig) (*TxBroadcaster, error) {
+	var endpoints []broadcastEndpoint
+	if !config.PrivateRelayOnly {
+		endpoints = append(endpoints,
+			clientBroadcastEndpoint("rpc", &w.Client),
+			clientBroadcastEndpoint("rpc_fallback", &w.ClientFallback))
+		for _, rawUrl := range config.RpcUrls {
+			client, err := rpc.Dial(rawUrl)
+			if err != nil {
+				return nil, fmt.Errorf("error dialing broadcast rpc %s: %w", endpointName(rawUrl), err)
+			}
+			endpoints = append(endpoints, rawTransactionEndpoint(endpointName(rawUrl), client))
+		}
+	}
+	for _, rawUrl := range config.PrivateRelayUrls {
+		client, err := dialPrivateRelay(rawUrl, config.PrivateRelaySigningKey, config.PrivateRelayAuthHeader)
+		if err != nil {
+			return nil, fmt.Errorf("error dialing private relay %s: %w", endpointName(rawUrl), err)
+		}
+		switch config.PrivateRelayMethod {
+		case "", PrivateRelayMethodPrivateTransaction:
+			endpoints = append(endpoints, privateTransactionEndpoint(endpointName(rawUrl), client, &w.Client))
+		case PrivateRelayMethodBundle:
+			endpoints = append(endpoints, bundleEndpoint(endpointName(rawUrl), client, &w.Client, bundleTargetBlocks(config.BundleTargetPeriod)))
+		default:
+			return nil, fmt.Errorf("unknown private relay method %q, expected %s or %s", config.PrivateRelayMethod,
+				PrivateRelayMethodPrivateTransaction, PrivateRelayMethodBundle)
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("no broadcast endpoint configured")
+	}
+	return newTxBroadcaster(endpoints, w.metrics), nil
+}
+
+// dialPrivateRelay dials the relay, signing every request if a signing key is set
+func dialPrivateRelay(rawUrl string, signingKey *ecdsa.PrivateKey, authHeader string) (*rpc.Client, error) {
+	if signingKey == nil {
+		return rpc.Dial(rawUrl)
+	}
+	if !strings.HasPrefix(rawUrl, "http://") && !strings.HasPrefix(rawUrl, "https://") {
+		return nil, errors.New("signed requests are only supported over http")
+	}
+	if authHeader == "" {
+		authHeader = DefaultPrivateRelayAuthHeader
+	}
+	httpClient := &http.Client{Transport: &relaySigningTransport{key: signingKey, header: authHeader, next: http.DefaultTransport}}
+	return rpc.DialOptions(context.Background(), rawUrl, rpc.WithHTTPClient(httpClient))
+}
+
+// relaySigningTransport signs the body of every request as Flashbots authenticates searchers:
+// `<header>: <address>:<signature>`, the signature being an EIP-191 personal sign of the hex keccak hash of the body
+type relaySigningTransport struct {
+	key    *ecdsa.PrivateKey
+	header string
+	next   http.RoundTripper
+}
+
+func (t *relaySigningTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	signature, err := relaySignature(t.key, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// A RoundTripper must not modify the request it was given
+	signed := request.Clone(request.Context())
+	signed.Body = io.NopCloser(bytes.NewReader(body))
+	signed.Header.Set(t.header, signature)
+	return t.next.RoundTrip(signed)
+}
+
+func relaySignature(key *ecdsa.PrivateKey, body []byte) (string, error) {
+	bodyHash := crypto.Keccak256Hash(body).Hex()
+	signature, err := crypto.Sign(accounts.TextHash([]byte(bodyHash)), key)
+	if err != nil {
+		return "", err
+	}
+	return crypto.PubkeyToAddress(key.PublicKey).Hex() + ":" + hexutil.Encode(signature), nil
+}
+
+// endpointName returns the host of the url, so api keys in its path or query are not logged nor exported as labels
+func endpointName(rawUrl string) string {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil || parsed.Host == "" {
+		return "invalid_url"
+	}
+	return parsed.Host
+}
+
+func clientBroadcastEndpoint(name string, client transactionSender) broadcastEndpoint {
+	return broadcastEndpoint{name: name, send: client.SendTransaction, primary: true}
+}
+
+func rawTransactionEndpoint(name string, client *rpc.Client) broadcastEndpoint {
+	return broadcastEndpoint{name: name, send: func(ctx context.Context, tx *types.Transaction) error {
+		rawTx, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		return client.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(rawTx))
+	}}
+}
+
+func privateTransactionEndpoint(name string, client *rpc.Client, blockReader blockNumberReader) broadcastEndpoint {
+	return broadcastEndpoint{name: name, send: func(ctx context.Context, tx *types.Transaction) error {
+		rawTx, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		blockNumber, err := blockReader.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		params := map[string]interface{}{
+			"tx":             hexutil.Encode(rawTx),
+			"maxBlockNumber": hexutil.EncodeUint64(blockNumber + privateTransactionMaxBlocks),
+		}
+		return client.CallContext(ctx, nil, "eth_sendPrivateTransaction", params)
+	}}
+}
+
+// bundleTargetBlocks returns the number of blocks bundles target to cover the period, rounded up,
+// plus one so a bundle is still valid in the block the transaction is bumped at
+func bundleTargetBlocks(period time.Duration) uint64 {
+	if period <= 0 {
+		return 1
+	}
+	return uint64((period+blockTime-1)/blockTime) + 1
+}
+
+// bundleEndpoint sends a bundle with the transaction for each of the next targetBlocks blocks,
+// as a bundle is only valid for the block it targets. It fails only if every bundle was rejected
+func bundleEndpoint(name string, client *rpc.Client, blockReader blockNumberReader, targetBlocks uint64) broadcastEndpoint {
+	return broadcastEndpoint{name: name, send: func(ctx context.Context, tx *types.Transaction) error {
+		rawTx, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		blockNumber, err := blockReader.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for block := blockNumber + 1; block <= blockNumber+targetBlocks; block++ {
+			params := map[string]interface{}{
+				"txs":         []string{hexutil.Encode(rawTx)},
+				"blockNumber": hexutil.EncodeUint64(block),
+			}
+			if err := client.CallContext(ctx, nil, "eth_sendBundle", params); err != nil {
+				errs = append(errs, fmt.Errorf("block %d: %w", block, err))
+			}
+		}
+		if uint64(len(errs)) == targetBlocks {
+			return errors.Join(errs...)
+		}
+		return nil
+	}}
+}
+
+// isAlreadyKnown reports whether the endpoint rejected the transaction because it already has it
+func isAlreadyKnown(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "already known") || strings.Contains(message, "known transaction")
+}
+
+// Broadcast sends the signed transaction to every endpoint in parallel, and returns once all of them answered.
+// sent reports whether any endpoint accepted the transaction, so it may be mined.
+// It fails if every endpoint rejected the transaction, with the errors of all of them, or if an aggregator RPC
+// rejected it for its nonce or as an underpriced replacement, even if other endpoints accepted it:
+// the relays don't check them, and the chain would reject the transaction the same way
+func (b *TxBroadcaster) Broadcast(ctx context.Context, tx *types.Transaction) (sent bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, broadcastTimeout)
+	defer cancel()
+
+	errs := make([]error, len(b.endpoints))
+	var accepted []string
+	var acceptedMutex sync.Mutex
+	var wg sync.WaitGroup
+	for i, endpoint := range b.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint broadcastEndpoint) {
+			defer wg.Done()
+			err := endpoint.send(ctx, tx)
+			if err != nil && !isAlreadyKnown(err) {
+				errs[i] = fmt.Errorf("%s: %w", endpoint.name, err)
+				return
+			}
+			acceptedMutex.Lock()
+			accepted = append(accepted, endpoint.name)
+			acceptedMutex.Unlock()
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	var primaryErrs []error
+	for i, endpoint := range b.endpoints {
+		result := BroadcastResultAccepted
+		if errs[i] != nil {
+			result = BroadcastResultRejected
+			if endpoint.primary && (isNonceError(errs[i]) || isReplacementUnderpriced(errs[i])) {
+				primaryErrs = append(primaryErrs, errs[i])
+			}
+		}
+		if b.metrics != nil {
+			b.metrics.IncBroadcastResults(endpoint.name, result)
+		}
+	}
+	if len(accepted) == 0 {
+		return false, errors.Join(errs...)
+	}
+
+	b.mutex.Lock()
+	b.pruneTracked(time.Now())
+	b.accepted[tx.Hash()] = accepted
+	b.sentAt[tx.Hash()] = time.Now()
+	b.mutex.Unlock()
+	return true, errors.Join(primaryErrs...)
+}
+
+// Included credits the inclusion of the transaction to the endpoint that accepted it first, and stops tracking it.
+// Returns the endpoints that accepted it, the credited one first
+func (b *TxBroadcaster) Included(txHash common.Hash) []string {
+	b.mutex.Lock()
+	accepted := b.accepted[txHash]
+	delete(b.accepted, txHash)
+	delete(b.sentAt, txHash)
+	b.mutex.Unlock()
+
+	if b.metrics != nil && len(accepted) > 0 {
+		b.metrics.IncBroadcastInclusions(accepted[0])
+	}
+	return accepted
+}
+
+// pruneTracked stops tracking the transactions sent before the tracking period, such as the replaced ones.
+// The mutex must be held
+func (b *TxBroadcaster) pruneTracked(now time.Time) {
+	for txHash, sentAt := range b.sentAt {
+		if now.Sub(sentAt) > broadcastTrackingPeriod {
+			delete(b.accepted, txHash)
+			delete(b.sentAt, txHash)
+		}
+	}
+}
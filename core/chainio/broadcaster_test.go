@@ -0,0 +1,256 @@
+package chainio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fakeRelayService is a local stand-in of the eth namespace of an RPC or a private relay
+type fakeRelayService struct {
+	err error
+
+	mutex   sync.Mutex
+	rawTxs  []hexutil.Bytes
+	bundles []fakeBundle
+	private []fakePrivateTransaction
+}
+
+type fakeBundle struct {
+	Txs         []hexutil.Bytes `json:"txs"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+}
+
+type fakePrivateTransaction struct {
+	Tx             hexutil.Bytes  `json:"tx"`
+	MaxBlockNumber hexutil.Uint64 `json:"maxBlockNumber"`
+}
+
+func (s *fakeRelayService) SendRawTransaction(rawTx hexutil.Bytes) (common.Hash, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.err != nil {
+		return common.Hash{}, s.err
+	}
+	s.rawTxs = append(s.rawTxs, rawTx)
+	return common.Hash{}, nil
+}
+
+func (s *fakeRelayService) SendBundle(bundle fakeBundle) (map[string]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.bundles = append(s.bundles, bundle)
+	return map[string]string{"bundleHash": "0x01"}, nil
+}
+
+func (s *fakeRelayService) SendPrivateTransaction(private fakePrivateTransaction) (common.Hash, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.private = append(s.private, private)
+	return common.Hash{}, nil
+}
+
+func newFakeRelay(t *testing.T, service *fakeRelayService) *rpc.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatalf("Could not register fake relay: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(server.Stop)
+	client, err := rpc.Dial(httpServer.URL)
+	if err != nil {
+		t.Fatalf("Could not dial fake relay: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+type fakeBlockNumberReader struct{}
+
+func (fakeBlockNumberReader) BlockNumber(ctx context.Context) (uint64, error) {
+	return 100, nil
+}
+
+type fakeTransactionSender struct{ err error }
+
+func (s fakeTransactionSender) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return s.err
+}
+
+func testTransaction(nonce uint64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1), Gas: 21000})
+}
+
+func TestTxBroadcasterSendsToEveryEndpoint(t *testing.T) {
+	public, private, bundle := &fakeRelayService{}, &fakeRelayService{}, &fakeRelayService{}
+	broadcaster := newTxBroadcaster([]broadcastEndpoint{
+		clientBroadcastEndpoint("rpc", fakeTransactionSender{err: errors.New("connection refused")}),
+		rawTransactionEndpoint("public", newFakeRelay(t, public)),
+		privateTransactionEndpoint("private", newFakeRelay(t, private), fakeBlockNumberReader{}),
+		bundleEndpoint("bundle", newFakeRelay(t, bundle), fakeBlockNumberReader{}, bundleTargetBlocks(72*time.Second)),
+	}, nil)
+
+	tx := testTransaction(1)
+	if sent, err := broadcaster.Broadcast(context.Background(), tx); !sent || err != nil {
+		t.Fatalf("Expected the transaction to be sent, got %v", err)
+	}
+	rawTx, _ := tx.MarshalBinary()
+	if len(public.rawTxs) != 1 || hexutil.Encode(public.rawTxs[0]) != hexutil.Encode(rawTx) {
+		t.Errorf("Expected the raw transaction on the public endpoint")
+	}
+	if len(private.private) != 1 || private.private[0].MaxBlockNumber != 100+privateTransactionMaxBlocks {
+		t.Errorf("Expected a private transaction with a max block number, got %+v", private.private)
+	}
+	// 72s are 6 blocks, the bundle targets them and the block of the bump
+	if len(bundle.bundles) != 7 {
+		t.Fatalf("Expected a bundle for each of the next 7 blocks, got %+v", bundle.bundles)
+	}
+	for i, sent := range bundle.bundles {
+		if sent.BlockNumber != hexutil.Uint64(101+i) || len(sent.Txs) != 1 {
+			t.Errorf("Expected a bundle targeting block %d, got %+v", 101+i, sent)
+		}
+	}
+
+	// The endpoints answer in any order, the first one is credited
+	included := broadcaster.Included(tx.Hash())
+	sort.Strings(included)
+	if strings.Join(included, ",") != "bundle,private,public" {
+		t.Errorf("Expected the accepting endpoints, got %v", included)
+	}
+	if included := broadcaster.Included(tx.Hash()); len(included) != 0 {
+		t.Errorf("Expected the transaction to stop being tracked, got %v", included)
+	}
+}
+
+func TestTxBroadcasterFailsOnlyIfEveryEndpointRejects(t *testing.T) {
+	known := &fakeRelayService{err: errors.New("already known")}
+	broadcaster := newTxBroadcaster([]broadcastEndpoint{
+		clientBroadcastEndpoint("rpc", fakeTransactionSender{err: errors.New("connection refused")}),
+		rawTransactionEndpoint("public", newFakeRelay(t, known)),
+	}, nil)
+	// An endpoint that already has the transaction accepted it
+	if sent, err := broadcaster.Broadcast(context.Background(), testTransaction(1)); !sent || err != nil {
+		t.Fatalf("Expected already known to count as accepted, got %v", err)
+	}
+
+	known.err = errors.New("insufficient funds")
+	sent, err := broadcaster.Broadcast(context.Background(), testTransaction(2))
+	if sent || err == nil {
+		t.Fatalf("Expected an error when every endpoint rejects the transaction")
+	}
+	if !strings.Contains(err.Error(), "rpc: connection refused") || !strings.Contains(err.Error(), "public: insufficient funds") {
+		t.Errorf("Expected the errors of every endpoint, got %v", err)
+	}
+}
+
+func TestTxBroadcasterSurfacesAggregatorRpcNonceErrors(t *testing.T) {
+	relay := &fakeRelayService{}
+	broadcaster := newTxBroadcaster([]broadcastEndpoint{
+		clientBroadcastEndpoint("rpc", fakeTransactionSender{err: errors.New("replacement transaction underpriced")}),
+		rawTransactionEndpoint("relay", newFakeRelay(t, relay)),
+	}, nil)
+	// The relay doesn't check the fees against the pending transaction, the aggregator RPC does
+	tx := testTransaction(1)
+	sent, err := broadcaster.Broadcast(context.Background(), tx)
+	if !sent || !isReplacementUnderpriced(err) {
+		t.Fatalf("Expected the transaction sent along with the underpriced error, got sent %v and %v", sent, err)
+	}
+	if included := broadcaster.Included(tx.Hash()); len(included) != 1 || included[0] != "relay" {
+		t.Errorf("Expected the relay to be tracked, got %v", included)
+	}
+}
+
+func TestPrivateRelayRequestsAreSigned(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Could not generate key: %v", err)
+	}
+	relay := &fakeRelayService{}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", relay); err != nil {
+		t.Fatalf("Could not register fake relay: %v", err)
+	}
+	t.Cleanup(server.Stop)
+
+	// The relay recovers the signer of the body from the header, as Flashbots does
+	var signers []common.Address
+	var signersMutex sync.Mutex
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		address, signature, found := strings.Cut(r.Header.Get("X-Relay-Signature"), ":")
+		decoded, err := hexutil.Decode(signature)
+		if !found || err != nil {
+			http.Error(w, "missing signature", http.StatusUnauthorized)
+			return
+		}
+		pubkey, err := crypto.SigToPub(accounts.TextHash([]byte(crypto.Keccak256Hash(body).Hex())), decoded)
+		if err != nil || crypto.PubkeyToAddress(*pubkey) != common.HexToAddress(address) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		signersMutex.Lock()
+		signers = append(signers, crypto.PubkeyToAddress(*pubkey))
+		signersMutex.Unlock()
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(httpServer.Close)
+
+	client, err := dialPrivateRelay(httpServer.URL, key, "X-Relay-Signature")
+	if err != nil {
+		t.Fatalf("Could not dial fake relay: %v", err)
+	}
+	t.Cleanup(client.Close)
+	broadcaster := newTxBroadcaster([]broadcastEndpoint{
+		privateTransactionEndpoint("private", client, fakeBlockNumberReader{}),
+		bundleEndpoint("bundle", client, fakeBlockNumberReader{}, 2),
+	}, nil)
+
+	if sent, err := broadcaster.Broadcast(context.Background(), testTransaction(1)); !sent || err != nil {
+		t.Fatalf("Expected the transaction to be sent, got %v", err)
+	}
+	if len(relay.private) != 1 || len(relay.bundles) != 2 {
+		t.Fatalf("Expected a private transaction and two bundles, got %d and %d", len(relay.private), len(relay.bundles))
+	}
+	if len(signers) != 3 {
+		t.Errorf("Expected the three requests to be signed, got %d", len(signers))
+	}
+	for _, signer := range signers {
+		if signer != crypto.PubkeyToAddress(key.PublicKey) {
+			t.Errorf("Expected every request signed by the relay signing key, got %s", signer)
+		}
+	}
+
+	// Unsigned requests are rejected by the relay
+	unsigned, err := dialPrivateRelay(httpServer.URL, nil, "")
+	if err != nil {
+		t.Fatalf("Could not dial fake relay: %v", err)
+	}
+	t.Cleanup(unsigned.Close)
+	if err := privateTransactionEndpoint("private", unsigned, fakeBlockNumberReader{}).send(context.Background(), testTransaction(2)); err == nil {
+		t.Errorf("Expected the unsigned request to be rejected")
+	}
+}
+
+func TestEndpointNameHidesUrlSecrets(t *testing.T) {
+	if name := endpointName("https://rpc.example.org/v2/secret-key?token=abc"); name != "rpc.example.org" {
+		t.Errorf("Expected only the host, got %s", name)
+	}
+}
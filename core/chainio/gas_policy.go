@@ -0,0 +1,209 @@
+package chainio
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// ErrGasPolicyDeferred is returned by SendAggregatedResponse when the gas spend policy defers the response
+var ErrGasPolicyDeferred = errors.New("response deferred by the gas spend policy")
+
+// Actions taken when a gas spend limit is hit, set in the gas_policy_action config
+const (
+	// Do not send the response, it is re-attempted later
+	GasPolicyActionDefer = "defer"
+	// Send the response, logging an error
+	GasPolicyActionAlert = "alert"
+	// Send the response
+	GasPolicyActionSend = "send"
+)
+
+// Limits of the gas spend policy, used as metric labels
+const (
+	GasPolicyLimitNone           = "none"
+	GasPolicyLimitMaxGasPrice    = "max_gas_price"
+	GasPolicyLimitMaxOverpayment = "max_overpayment"
+	GasPolicyLimitSpendBudget    = "spend_budget"
+)
+
+// GasPolicyConfig holds the limits of the gas spend policy. Nil limits are not checked
+type GasPolicyConfig struct {
+	MaxGasPrice *big.Int
+	// Most the aggregator pays over the RespondToTaskFeeLimit of a batch
+	MaxOverpaymentPerBatch *big.Int
+	// Most the aggregator spends in SpendBudgetWindow
+	SpendBudget       *big.Int
+	SpendBudgetWindow time.Duration
+	// Action taken when a limit is hit
+	Action string
+}
+
+// GasPolicyDecision is the outcome of evaluating a transaction against the gas spend policy
+type GasPolicyDecision struct {
+	Action string
+	// Limit hit, GasPolicyLimitNone if the transaction is within every limit
+	Limit string
+	// Most the transaction can cost and how much of it the batcher does not cover, nil if the fee limit is unknown
+	Cost        *big.Int
+	Overpayment *big.Int
+}
+
+type gasSpend struct {
+	at     time.Time
+	amount *big.Int
+}
+
+// GasPolicy decides whether the respond to task transactions are sent, given an absolute max gas price,
+// a max overpayment per batch relative to the batcher fee limit, and a spend budget over a rolling window
+type GasPolicy struct {
+	config GasPolicyConfig
+	// Spends within the budget window, oldest first
+	spends []gasSpend
+	// Batches whose responses are sent even if a limit is hit, as they were deferred for too long
+	forced map[[32]byte]struct{}
+	mutex  sync.Mutex
+}
+
+func NewGasPolicy(config GasPolicyConfig) *GasPolicy {
+	return &GasPolicy{config: config, forced: make(map[[32]byte]struct{})}
+}
+
+// Force makes the next response of the batch be sent even if a limit is hit, downgrading a deferral to an alert.
+// It lasts until SendAggregatedResponse returns for the batch
+func (p *GasPolicy) Force(batchIdentifierHash [32]byte) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.forced[batchIdentifierHash] = struct{}{}
+}
+
+func (p *GasPolicy) release(batchIdentifierHash [32]byte) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.forced, batchIdentifierHash)
+}
+
+func (p *GasPolicy) isForced(batchIdentifierHash [32]byte) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	_, ok := p.forced[batchIdentifierHash]
+	return ok
+}
+
+// Evaluate checks a transaction using `gas` at `gasPrice` (the fee cap for EIP-1559 transactions) against the limits.
+// Callers evaluating a transaction before it is built must use the same basis, see GasPolicyPrice
+// feeLimit is the RespondToTaskFeeLimit of the batch, nil if unknown
+func (p *GasPolicy) Evaluate(gasPrice *big.Int, gas uint64, feeLimit *big.Int, now time.Time) GasPolicyDecision {
+	cost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gas))
+	decision := GasPolicyDecision{Action: GasPolicyActionSend, Limit: GasPolicyLimitNone, Cost: cost}
+	if feeLimit != nil {
+		decision.Overpayment = new(big.Int).Sub(cost, feeLimit)
+		if decision.Overpayment.Sign() < 0 {
+			decision.Overpayment.SetInt64(0)
+		}
+	}
+
+	switch {
+	case p.config.MaxGasPrice != nil && gasPrice.Cmp(p.config.MaxGasPrice) > 0:
+		decision.Limit = GasPolicyLimitMaxGasPrice
+	case p.config.MaxOverpaymentPerBatch != nil && decision.Overpayment != nil && decision.Overpayment.Cmp(p.config.MaxOverpaymentPerBatch) > 0:
+		decision.Limit = GasPolicyLimitMaxOverpayment
+	case p.config.SpendBudget != nil && new(big.Int).Add(p.Spent(now), cost).Cmp(p.config.SpendBudget) > 0:
+		decision.Limit = GasPolicyLimitSpendBudget
+	default:
+		return decision
+	}
+	decision.Action = p.config.Action
+	return decision
+}
+
+// RecordSpend adds the cost of a mined transaction to the spend budget
+func (p *GasPolicy) RecordSpend(amount *big.Int, at time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.spends = append(p.spends, gasSpend{at: at, amount: amount})
+}
+
+// Spent returns the amount spent within the budget window
+func (p *GasPolicy) Spent(now time.Time) *big.Int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	windowStart := now.Add(-p.config.SpendBudgetWindow)
+	expired := 0
+	for expired < len(p.spends) && !p.spends[expired].at.After(windowStart) {
+		expired++
+	}
+	p.spends = p.spends[expired:]
+
+	spent := new(big.Int)
+	for _, spend := range p.spends {
+		spent.Add(spent, spend.amount)
+	}
+	return spent
+}
+
+// GasPolicyPrice returns the price per gas the gas spend policy evaluates the first respond to task transaction at,
+// as SendAggregatedResponse sets it: the bumped gas price in legacy mode, the bumped fee cap in dynamic mode
+func (w *AvsWriter) GasPolicyPrice(gasBumpPercentage uint, gasBumpIncrementalPercentage uint, gasBumpPercentageLimit uint, dynamicFeeParams *utils.DynamicFeeParams) (*big.Int, error) {
+	if dynamicFeeParams != nil {
+		fees, err := utils.GetDynamicFeesRetryable(w.Client, w.ClientFallback, *dynamicFeeParams, retry.NetworkRetryParams())
+		if err != nil {
+			return nil, err
+		}
+		return utils.CalculateDynamicFeesBumpBasedOnRetry(*fees, nil, gasBumpPercentage, gasBumpIncrementalPercentage, gasBumpPercentageLimit, 0).GasFeeCap, nil
+	}
+	gasPrice, err := utils.GetGasPriceRetryable(w.Client, w.ClientFallback, retry.NetworkRetryParams())
+	if err != nil {
+		return nil, err
+	}
+	return utils.CalculateGasPriceBumpBasedOnRetry(gasPrice, gasBumpPercentage, gasBumpIncrementalPercentage, gasBumpPercentageLimit, 0), nil
+}
+
+// applyGasPolicy evaluates the transaction about to be sent, logging the decision and exporting it as a metric.
+// Returns ErrGasPolicyDeferred if the response must not be sent.
+// A deferral is downgraded to an alert once a transaction was sent for the batch, as it may still be mined,
+// and when the batch was forced after being deferred for too long
+func (w *AvsWriter) applyGasPolicy(tx *types.Transaction, txOpts bind.TransactOpts, feeLimit *big.Int, txAlreadySent bool, batchIdentifierHash [32]byte, batchMerkleRootHashString string) error {
+	if w.GasPolicy == nil {
+		return nil
+	}
+	gasPrice := txOpts.GasPrice
+	if gasPrice == nil {
+		gasPrice = txOpts.GasFeeCap
+	}
+
+	decision := w.GasPolicy.Evaluate(gasPrice, tx.Gas(), feeLimit, time.Now())
+	if decision.Action == GasPolicyActionDefer && (txAlreadySent || w.GasPolicy.isForced(batchIdentifierHash)) {
+		decision.Action = GasPolicyActionAlert
+	}
+	if w.metrics != nil {
+		w.metrics.IncGasPolicyDecisions(decision.Action, decision.Limit)
+	}
+
+	if decision.Limit == GasPolicyLimitNone {
+		return nil
+	}
+	logArgs := []interface{}{"limit", decision.Limit, "action", decision.Action, "gasPrice", gasPrice,
+		"costInEth", utils.WeiToEth(decision.Cost), "merkle root", batchMerkleRootHashString}
+	if decision.Overpayment != nil {
+		logArgs = append(logArgs, "overpaymentInEth", utils.WeiToEth(decision.Overpayment))
+	}
+	switch decision.Action {
+	case GasPolicyActionDefer:
+		w.logger.Warn("Gas spend limit hit, deferring the response", logArgs...)
+		return fmt.Errorf("%w: %s limit hit", ErrGasPolicyDeferred, decision.Limit)
+	case GasPolicyActionAlert:
+		w.logger.Error("Gas spend limit hit, sending the response anyway", logArgs...)
+	default:
+		w.logger.Info("Gas spend limit hit, sending the response anyway", logArgs...)
+	}
+	return nil
+}
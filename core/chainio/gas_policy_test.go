@@ -0,0 +1,78 @@
+package chainio
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestGasPolicyEvaluate(t *testing.T) {
+	policy := NewGasPolicy(GasPolicyConfig{
+		MaxGasPrice:            big.NewInt(100),
+		MaxOverpaymentPerBatch: big.NewInt(500),
+		SpendBudget:            big.NewInt(10_000),
+		SpendBudgetWindow:      time.Hour,
+		Action:                 GasPolicyActionDefer,
+	})
+	now := time.Now()
+
+	decision := policy.Evaluate(big.NewInt(50), 100, big.NewInt(4_600), now)
+	if decision.Action != GasPolicyActionSend || decision.Limit != GasPolicyLimitNone {
+		t.Errorf("Expected to send within the limits, got %+v", decision)
+	}
+	if decision.Cost.Int64() != 5_000 || decision.Overpayment.Int64() != 400 {
+		t.Errorf("Expected a cost of 5000 and an overpayment of 400, got %v and %v", decision.Cost, decision.Overpayment)
+	}
+
+	if decision := policy.Evaluate(big.NewInt(101), 1, nil, now); decision.Limit != GasPolicyLimitMaxGasPrice || decision.Action != GasPolicyActionDefer {
+		t.Errorf("Expected the max gas price to defer, got %+v", decision)
+	}
+	if decision := policy.Evaluate(big.NewInt(50), 100, big.NewInt(4_000), now); decision.Limit != GasPolicyLimitMaxOverpayment {
+		t.Errorf("Expected the max overpayment to be hit, got %+v", decision)
+	}
+	// Without fee limit the overpayment is unknown
+	if decision := policy.Evaluate(big.NewInt(50), 100, nil, now); decision.Limit != GasPolicyLimitNone || decision.Overpayment != nil {
+		t.Errorf("Expected no overpayment check without fee limit, got %+v", decision)
+	}
+
+	policy.RecordSpend(big.NewInt(6_000), now.Add(-2*time.Hour))
+	policy.RecordSpend(big.NewInt(6_000), now.Add(-time.Minute))
+	if decision := policy.Evaluate(big.NewInt(50), 100, nil, now); decision.Limit != GasPolicyLimitSpendBudget {
+		t.Errorf("Expected the spend budget to be hit, got %+v", decision)
+	}
+	if spent := policy.Spent(now); spent.Int64() != 6_000 {
+		t.Errorf("Expected only the spend within the window, got %v", spent)
+	}
+	if decision := policy.Evaluate(big.NewInt(50), 100, nil, now.Add(time.Hour)); decision.Limit != GasPolicyLimitNone {
+		t.Errorf("Expected the budget to be available once the spends leave the window, got %+v", decision)
+	}
+}
+
+func TestApplyGasPolicyForcedBatch(t *testing.T) {
+	policy := NewGasPolicy(GasPolicyConfig{MaxGasPrice: big.NewInt(100), Action: GasPolicyActionDefer})
+	writer := &AvsWriter{logger: logging.NewTextSLogger(io.Discard, nil), GasPolicy: policy}
+	tx := types.NewTx(&types.LegacyTx{Gas: 1})
+	txOpts := bind.TransactOpts{GasPrice: big.NewInt(200)}
+	batchIdentifierHash := [32]byte{1}
+
+	if err := writer.applyGasPolicy(tx, txOpts, nil, false, batchIdentifierHash, ""); !errors.Is(err, ErrGasPolicyDeferred) {
+		t.Fatalf("Expected the response to be deferred, got %v", err)
+	}
+	policy.Force(batchIdentifierHash)
+	if err := writer.applyGasPolicy(tx, txOpts, nil, false, batchIdentifierHash, ""); err != nil {
+		t.Fatalf("Expected the forced response to be sent, got %v", err)
+	}
+	if err := writer.applyGasPolicy(tx, txOpts, nil, false, [32]byte{2}, ""); !errors.Is(err, ErrGasPolicyDeferred) {
+		t.Errorf("Expected other batches to still be deferred, got %v", err)
+	}
+	policy.release(batchIdentifierHash)
+	if err := writer.applyGasPolicy(tx, txOpts, nil, false, batchIdentifierHash, ""); !errors.Is(err, ErrGasPolicyDeferred) {
+		t.Errorf("Expected the response to be deferred once released, got %v", err)
+	}
+}
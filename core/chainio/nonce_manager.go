@@ -0,0 +1,195 @@
+package chainio
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type pendingNonceReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out the nonces of the aggregator transactions, so several RespondToTask transactions
+// can be in flight at once, each with its own nonce.
+// Nonces are taken from the pending nonce of the node the first time, and after each Resync.
+// Nonces released without being used are handed out again first, so a dropped transaction does not leave
+// a gap that blocks every transaction after it
+type NonceManager struct {
+	address        common.Address
+	client         pendingNonceReader
+	fallbackClient pendingNonceReader
+
+	// Next nonce to hand out, if no released one is left
+	next   uint64
+	synced bool
+	// Nonces handed out whose transaction was not mined yet
+	inFlight map[uint64]struct{}
+	// Nonces handed out and released without being used, lowest first
+	released []uint64
+	mutex    sync.Mutex
+}
+
+func NewNonceManager(address common.Address, client pendingNonceReader, fallbackClient pendingNonceReader) *NonceManager {
+	return &NonceManager{
+		address:        address,
+		client:         client,
+		fallbackClient: fallbackClient,
+		inFlight:       make(map[uint64]struct{}),
+	}
+}
+
+// Acquire returns a nonce no other in-flight transaction uses. It must be given back with Done, Release or Abandon
+func (m *NonceManager) Acquire() (uint64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.synced {
+		if err := m.sync(); err != nil {
+			return 0, err
+		}
+	}
+
+	var nonce uint64
+	if len(m.released) > 0 {
+		nonce = m.released[0]
+		m.released = m.released[1:]
+	} else {
+		nonce = m.next
+		m.next++
+	}
+	m.inFlight[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Done marks the nonce as used by a mined transaction
+func (m *NonceManager) Done(nonce uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.inFlight, nonce)
+}
+
+// Release gives back a nonce whose transaction was not mined, so it is handed out again
+func (m *NonceManager) Release(nonce uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.inFlight[nonce]; !ok {
+		return
+	}
+	delete(m.inFlight, nonce)
+	m.addReleased(nonce)
+}
+
+// Abandon gives back a nonce whose transaction was broadcast but not mined. The transaction may still be mined,
+// so the nonce is not handed out again. Nonces are synced with the node on the next Acquire instead,
+// which releases the nonce if the node dropped the transaction
+func (m *NonceManager) Abandon(nonce uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.inFlight, nonce)
+	m.synced = false
+}
+
+// Resync takes the pending nonce from the node again on the next Acquire.
+// To be called when a transaction fails because of its nonce, e.g. when the wallet was used by another process
+func (m *NonceManager) Resync() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.synced = false
+}
+
+// InFlight returns the number of nonces handed out that were not given back yet
+func (m *NonceManager) InFlight() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.inFlight)
+}
+
+// HasLaterNonces reports whether a nonce after the given one was handed out, so its transaction is stuck
+// until the given nonce is used
+func (m *NonceManager) HasLaterNonces(nonce uint64) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.next > nonce+1
+}
+
+// sync sets the next nonce from the pending nonce of the node. Nonces below it were used, so they are no longer
+// released. Nonces between it and the in-flight ones were dropped by the node, so they are released to fill the gap.
+// The mutex must be held
+func (m *NonceManager) sync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pendingNonce, err := m.client.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		pendingNonce, err = m.fallbackClient.PendingNonceAt(ctx, m.address)
+		if err != nil {
+			return err
+		}
+	}
+
+	next := pendingNonce
+	for nonce := range m.inFlight {
+		if nonce >= next {
+			next = nonce + 1
+		}
+	}
+
+	released := m.released[:0]
+	for _, nonce := range m.released {
+		if nonce >= pendingNonce && nonce < next {
+			released = append(released, nonce)
+		}
+	}
+	m.released = released
+	for nonce := pendingNonce; nonce < next && nonce < m.next; nonce++ {
+		if _, inFlight := m.inFlight[nonce]; !inFlight && !m.isReleased(nonce) {
+			m.addReleased(nonce)
+		}
+	}
+
+	m.next = next
+	m.synced = true
+	return nil
+}
+
+// The mutex must be held
+func (m *NonceManager) addReleased(nonce uint64) {
+	index := sort.Search(len(m.released), func(i int) bool { return m.released[i] >= nonce })
+	if index < len(m.released) && m.released[index] == nonce {
+		return
+	}
+	m.released = append(m.released, 0)
+	copy(m.released[index+1:], m.released[index:])
+	m.released[index] = nonce
+}
+
+// The mutex must be held
+func (m *NonceManager) isReleased(nonce uint64) bool {
+	index := sort.Search(len(m.released), func(i int) bool { return m.released[i] >= nonce })
+	return index < len(m.released) && m.released[index] == nonce
+}
+
+// isNonceError reports whether the node rejected a transaction because of its nonce
+func isNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "nonce too low") ||
+		strings.Contains(message, "nonce too high")
+}
+
+// isNonceTooLow reports whether the node rejected a transaction because another one of the sender was included with its nonce
+func isNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+// isReplacementUnderpriced reports whether the node rejected a transaction because another one with the same nonce
+// is pending with fees the new one doesn't bump enough. The nonce is still unused, so it's sent again with higher fees
+func isReplacementUnderpriced(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "replacement transaction underpriced")
+}
@@ -0,0 +1,130 @@
+package chainio
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type fakeNonceReader struct {
+	pendingNonce uint64
+	err          error
+}
+
+func (r *fakeNonceReader) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return r.pendingNonce, r.err
+}
+
+func acquireNonce(t *testing.T, manager *NonceManager) uint64 {
+	nonce, err := manager.Acquire()
+	if err != nil {
+		t.Fatalf("Could not acquire nonce: %v", err)
+	}
+	return nonce
+}
+
+func TestNonceManagerHandsOutDistinctNonces(t *testing.T) {
+	reader := &fakeNonceReader{pendingNonce: 5}
+	manager := NewNonceManager(common.Address{}, reader, reader)
+
+	first, second, third := acquireNonce(t, manager), acquireNonce(t, manager), acquireNonce(t, manager)
+	if first != 5 || second != 6 || third != 7 {
+		t.Fatalf("Expected nonces 5, 6 and 7, got %d, %d and %d", first, second, third)
+	}
+	if manager.InFlight() != 3 {
+		t.Errorf("Expected 3 nonces in flight, got %d", manager.InFlight())
+	}
+
+	if !manager.HasLaterNonces(second) || manager.HasLaterNonces(third) {
+		t.Errorf("Expected only the nonces before %d to have later ones", third)
+	}
+
+	// The transaction of the second nonce was dropped, its nonce is handed out again before the next one
+	manager.Done(first)
+	manager.Release(second)
+	if nonce := acquireNonce(t, manager); nonce != second {
+		t.Errorf("Expected the released nonce %d, got %d", second, nonce)
+	}
+	if nonce := acquireNonce(t, manager); nonce != 8 {
+		t.Errorf("Expected nonce 8, got %d", nonce)
+	}
+}
+
+func TestNonceManagerResync(t *testing.T) {
+	reader := &fakeNonceReader{pendingNonce: 0}
+	manager := NewNonceManager(common.Address{}, reader, reader)
+	for i := 0; i < 4; i++ {
+		acquireNonce(t, manager)
+	}
+	// 0 was mined, 1 and 2 were dropped by the node and released, 3 is still in flight
+	manager.Done(0)
+	manager.Release(1)
+	manager.Release(2)
+
+	// Another process used nonce 1
+	reader.pendingNonce = 2
+	manager.Resync()
+	if nonce := acquireNonce(t, manager); nonce != 2 {
+		t.Errorf("Expected the gap nonce 2, got %d", nonce)
+	}
+	if nonce := acquireNonce(t, manager); nonce != 4 {
+		t.Errorf("Expected nonce 4 after the in-flight one, got %d", nonce)
+	}
+
+	// Nonces ahead of the node that are not in flight are handed out again to fill the gap
+	manager.Done(3)
+	manager.Done(4)
+	manager.Release(2)
+	acquireNonce(t, manager)
+	reader.pendingNonce = 10
+	manager.Resync()
+	if nonce := acquireNonce(t, manager); nonce != 10 {
+		t.Errorf("Expected the pending nonce of the node, got %d", nonce)
+	}
+
+	reader.err = errors.New("connection refused")
+	manager.Resync()
+	if _, err := manager.Acquire(); err == nil {
+		t.Errorf("Expected an error when the pending nonce can't be fetched")
+	}
+}
+
+// When the retries run out after a transaction was broadcast, its nonce is abandoned rather than released
+func TestNonceManagerAbandonBroadcastNonce(t *testing.T) {
+	reader := &fakeNonceReader{pendingNonce: 5}
+	manager := NewNonceManager(common.Address{}, reader, reader)
+	nonce := acquireNonce(t, manager)
+
+	// The transaction is still in the mempool of the node, its nonce must not be handed out again
+	reader.pendingNonce = 6
+	manager.Abandon(nonce)
+	if manager.InFlight() != 0 {
+		t.Errorf("Expected no nonce in flight, got %d", manager.InFlight())
+	}
+	if next := acquireNonce(t, manager); next != 6 {
+		t.Errorf("Expected nonce 6 while the abandoned transaction is pending, got %d", next)
+	}
+
+	// The node dropped the transaction of 6, so the nonce is handed out again to fill the gap
+	reader.pendingNonce = 6
+	manager.Abandon(6)
+	if next := acquireNonce(t, manager); next != 6 {
+		t.Errorf("Expected the dropped nonce 6, got %d", next)
+	}
+}
+
+func TestIsNonceError(t *testing.T) {
+	if !isNonceError(errors.New("nonce too low: address 0x01, tx: 1 state: 2")) ||
+		!isNonceError(errors.New("nonce too high")) {
+		t.Errorf("Expected nonce errors")
+	}
+	// The nonce of an underpriced replacement is still unused, it's sent again with higher fees instead
+	if isNonceError(errors.New("replacement transaction underpriced")) || isNonceError(errors.New("execution reverted")) || isNonceError(nil) {
+		t.Errorf("Unexpected nonce error")
+	}
+	if !isReplacementUnderpriced(errors.New("replacement transaction underpriced")) || isReplacementUnderpriced(nil) {
+		t.Errorf("Expected only the underpriced replacement error")
+	}
+}
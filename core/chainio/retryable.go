@@ -2,6 +2,7 @@ package chainio
 
 import (
 	"context"
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
@@ -18,9 +19,11 @@ import (
 /*
 RespondToTaskV2Retryable
 Send a transaction to the AVS contract to respond to a task.
-- All errors are considered Transient Errors
+- Reverts are decoded into a *RevertError. The ones that will revert again, such as BatchAlreadyResponded or an invalid signature, are Permanent Errors
+- Nonce errors and underpriced replacements are Permanent Errors, as the same transaction would be rejected again
+- All other errors are considered Transient Errors
 - Retry times (3 retries): 12 sec (1 Blocks), 24 sec (2 Blocks), 48 sec (4 Blocks)
-- NOTE: Contract call reverts are not considered `PermanentError`'s as block reorg's may lead to contract call revert in which case the aggregator should retry.
+- NOTE: BatchDoesNotExist and unknown reverts are not considered `PermanentError`'s as block reorg's may lead to contract call revert in which case the aggregator should retry.
 */
 func (w *AvsWriter) RespondToTaskV2Retryable(opts *bind.TransactOpts, batchMerkleRoot [32]byte, senderAddress common.Address, nonSignerStakesAndSignature servicemanager.IBLSSignatureCheckerNonSignerStakesAndSignature, config *retry.RetryParams) (*types.Transaction, error) {
 	respondToTaskV2_func := func() (*types.Transaction, error) {
@@ -30,12 +33,81 @@ func (w *AvsWriter) RespondToTaskV2Retryable(opts *bind.TransactOpts, batchMerkl
 			// If error try with fallback
 			tx, err = w.AvsContractBindings.ServiceManagerFallback.RespondToTaskV2(opts, batchMerkleRoot, senderAddress, nonSignerStakesAndSignature)
 		}
+		if isNonceError(err) || isReplacementUnderpriced(err) {
+			// The same transaction would be rejected again, the caller has to change its nonce or fees
+			return nil, retry.PermanentError{Inner: err}
+		}
+		if revertErr := DecodeRevertError(err); revertErr != nil {
+			if w.metrics != nil {
+				w.metrics.IncRespondToTaskReverts(revertErr.Label())
+			}
+			if revertErr.Permanent() {
+				return nil, retry.PermanentError{Inner: revertErr}
+			}
+			return nil, revertErr
+		}
 
 		return tx, err
 	}
 	return retry.RetryWithData(respondToTaskV2_func, config)
 }
 
+/*
+TransactionReceiptRetryable
+Get the receipt of a transaction.
+- If neither connection finds the transaction, `ethereum.NotFound` is returned as a Permanent Error
+- All other errors are considered Transient Errors
+- Retry times (3 retries): 1 sec, 2 sec, 4 sec
+*/
+func (w *AvsWriter) TransactionReceiptRetryable(ctx context.Context, txHash common.Hash, config *retry.RetryParams) (*types.Receipt, error) {
+	receipt_func := func() (*types.Receipt, error) {
+		// Try with main connection
+		receipt, err := w.Client.TransactionReceipt(ctx, txHash)
+		if err != nil {
+			// If error try with fallback connection
+			var fallbackErr error
+			receipt, fallbackErr = w.ClientFallback.TransactionReceipt(ctx, txHash)
+			if fallbackErr == nil {
+				return receipt, nil
+			}
+			if errors.Is(err, ethereum.NotFound) && errors.Is(fallbackErr, ethereum.NotFound) {
+				return nil, retry.PermanentError{Inner: ethereum.NotFound}
+			}
+			err = fallbackErr
+		}
+		return receipt, err
+	}
+	return retry.RetryWithData(receipt_func, config)
+}
+
+/*
+TransactionByHashRetryable
+Get a transaction by its hash.
+- If neither connection finds the transaction, `ethereum.NotFound` is returned as a Permanent Error
+- All other errors are considered Transient Errors
+- Retry times (3 retries): 1 sec, 2 sec, 4 sec
+*/
+func (w *AvsWriter) TransactionByHashRetryable(ctx context.Context, txHash common.Hash, config *retry.RetryParams) (*types.Transaction, error) {
+	transaction_func := func() (*types.Transaction, error) {
+		// Try with main connection
+		tx, _, err := w.Client.TransactionByHash(ctx, txHash)
+		if err != nil {
+			// If error try with fallback connection
+			var fallbackErr error
+			tx, _, fallbackErr = w.ClientFallback.TransactionByHash(ctx, txHash)
+			if fallbackErr == nil {
+				return tx, nil
+			}
+			if errors.Is(err, ethereum.NotFound) && errors.Is(fallbackErr, ethereum.NotFound) {
+				return nil, retry.PermanentError{Inner: ethereum.NotFound}
+			}
+			err = fallbackErr
+		}
+		return tx, err
+	}
+	return retry.RetryWithData(transaction_func, config)
+}
+
 /*
 BatchesStateRetryable
 Get the state of a batch from the AVS contract.
@@ -104,6 +176,40 @@ func (w *AvsWriter) BalanceAtRetryable(ctx context.Context, aggregatorAddress co
 	return retry.RetryWithData(balanceAt_func, config)
 }
 
+// |---AVS_READER---|
+
+/*
+GetQuorumCountRetryable
+Get the number of quorums created in the registry coordinator.
+- All errors are considered Transient Errors
+- Retry times (3 retries): 1 sec, 2 sec, 4 sec.
+*/
+func (r *AvsReader) GetQuorumCountRetryable(opts *bind.CallOpts, config *retry.RetryParams) (uint8, error) {
+	quorumCount_func := func() (uint8, error) {
+		return r.ChainReader.GetQuorumCount(opts)
+	}
+	return retry.RetryWithData(quorumCount_func, config)
+}
+
+/*
+QuorumThresholdPercentageRetryable
+Get the stake percentage respondToTaskV2 requires of the quorums it checks, from the service manager.
+- All errors are considered Transient Errors
+- Retry times (3 retries): 1 sec, 2 sec, 4 sec.
+*/
+func (r *AvsReader) QuorumThresholdPercentageRetryable(opts *bind.CallOpts, config *retry.RetryParams) (uint8, error) {
+	quorumThreshold_func := func() (uint8, error) {
+		// Try with main connection
+		threshold, err := r.AvsContractBindings.ServiceManager.QUORUMTHRESHOLDPERCENTAGE(opts)
+		if err != nil {
+			// If error try with fallback connection
+			threshold, err = r.AvsContractBindings.ServiceManagerFallback.QUORUMTHRESHOLDPERCENTAGE(opts)
+		}
+		return threshold, err
+	}
+	return retry.RetryWithData(quorumThreshold_func, config)
+}
+
 // |---AVS_SUBSCRIBER---|
 
 /*
@@ -151,6 +257,19 @@ func (s *AvsSubscriber) FilterBatchV3Retryable(opts *bind.FilterOpts, batchMerkl
 	return retry.RetryWithData(filterNewBatchV2_func, config)
 }
 
+/*
+FilterBatchVerifiedRetryable
+Get BatchVerified logs from the AVS contract.
+- All errors are considered Transient Errors
+- Retry times (3 retries): 1 sec, 2 sec, 4 sec.
+*/
+func (s *AvsSubscriber) FilterBatchVerifiedRetryable(opts *bind.FilterOpts, batchMerkleRoot [][32]byte, config *retry.RetryParams) (*servicemanager.ContractAlignedLayerServiceManagerBatchVerifiedIterator, error) {
+	filterBatchVerified_func := func() (*servicemanager.ContractAlignedLayerServiceManagerBatchVerifiedIterator, error) {
+		return s.AvsContractBindings.ServiceManager.FilterBatchVerified(opts, batchMerkleRoot)
+	}
+	return retry.RetryWithData(filterBatchVerified_func, config)
+}
+
 /*
 BatchesStateRetryable
 Get the state of a batch from the AVS contract.
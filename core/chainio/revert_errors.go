@@ -0,0 +1,182 @@
+package chainio
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/rpc"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+// Reverts of the AlignedLayerServiceManager. Match them with errors.Is on the errors returned by the AvsWriter
+var (
+	ErrBatchAlreadyResponded  = errors.New("batch already responded")
+	ErrBatchAlreadySubmitted  = errors.New("batch already submitted")
+	ErrBatchDoesNotExist      = errors.New("batch does not exist")
+	ErrInsufficientFunds      = errors.New("insufficient batcher balance")
+	ErrInvalidQuorumThreshold = errors.New("quorum threshold not reached")
+	ErrSenderIsNotAggregator  = errors.New("sender is not the aggregator")
+	ErrInvalidDepositAmount   = errors.New("invalid deposit amount")
+	ErrInvalidAddress         = errors.New("invalid address")
+	ErrInvalidSignature       = errors.New("invalid aggregated signature")
+	ErrReverted               = errors.New("execution reverted")
+)
+
+// revertKinds maps the custom errors of the contract ABI to their error and metric label
+var revertKinds = map[string]struct {
+	err   error
+	label string
+}{
+	"BatchAlreadyResponded":  {ErrBatchAlreadyResponded, "batch_already_responded"},
+	"BatchAlreadySubmitted":  {ErrBatchAlreadySubmitted, "batch_already_submitted"},
+	"BatchDoesNotExist":      {ErrBatchDoesNotExist, "batch_does_not_exist"},
+	"InsufficientFunds":      {ErrInsufficientFunds, "insufficient_funds"},
+	"InvalidQuorumThreshold": {ErrInvalidQuorumThreshold, "invalid_quorum_threshold"},
+	"SenderIsNotAggregator":  {ErrSenderIsNotAggregator, "sender_is_not_aggregator"},
+	"InvalidDepositAmount":   {ErrInvalidDepositAmount, "invalid_deposit_amount"},
+	"InvalidAddress":         {ErrInvalidAddress, "invalid_address"},
+}
+
+// Revert reasons of the BLSSignatureChecker meaning the aggregated signature does not verify
+var invalidSignatureReasons = []string{
+	"signature is invalid",
+	"pairing precompile call failed",
+}
+
+// RevertError is a revert of an AlignedLayerServiceManager call, decoded from its revert data
+type RevertError struct {
+	// Name of the custom error, or Error for require messages
+	Name string
+	// Arguments of the custom error, or the require message
+	Args []interface{}
+	kind error
+	// The error returned by the client
+	Err error
+}
+
+func (e *RevertError) Error() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		switch value := arg.(type) {
+		case [32]byte:
+			args[i] = "0x" + hex.EncodeToString(value[:])
+		default:
+			args[i] = fmt.Sprint(value)
+		}
+	}
+	return fmt.Sprintf("execution reverted: %s(%s)", e.Name, strings.Join(args, ", "))
+}
+
+func (e *RevertError) Is(target error) bool {
+	return target == e.kind || target == ErrReverted
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// Label identifies the revert in metrics
+func (e *RevertError) Label() string {
+	if kind, ok := revertKinds[e.Name]; ok {
+		return kind.label
+	}
+	if e.kind == ErrInvalidSignature {
+		return "invalid_signature"
+	}
+	return "unknown"
+}
+
+// Permanent reports whether sending the same response again will revert again.
+// BatchDoesNotExist is not, as the batch may be missing because of a reorg of its creation block
+func (e *RevertError) Permanent() bool {
+	switch e.kind {
+	case ErrBatchAlreadyResponded, ErrInsufficientFunds, ErrInvalidQuorumThreshold, ErrSenderIsNotAggregator, ErrInvalidSignature:
+		return true
+	default:
+		return false
+	}
+}
+
+var serviceManagerAbi *abi.ABI
+
+func init() {
+	parsed, err := servicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse AlignedLayerServiceManager ABI: %v", err))
+	}
+	serviceManagerAbi = parsed
+}
+
+// revertData returns the revert data attached to the error by the rpc client, if any
+func revertData(err error) []byte {
+	var dataError rpc.DataError
+	if !errors.As(err, &dataError) {
+		return nil
+	}
+	data, ok := dataError.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	decoded, decodeErr := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	if decodeErr != nil {
+		return nil
+	}
+	return decoded
+}
+
+// DecodeRevertError turns the revert of an AlignedLayerServiceManager call into a *RevertError.
+// The revert data is taken from the rpc error, falling back to the custom error selector in the message for
+// clients that only return it there. Returns nil if err is not a revert
+func DecodeRevertError(err error) *RevertError {
+	if err == nil {
+		return nil
+	}
+	var revertError *RevertError
+	if errors.As(err, &revertError) {
+		return revertError
+	}
+
+	data := revertData(err)
+	if len(data) < 4 {
+		message := strings.ToLower(err.Error())
+		for _, abiError := range serviceManagerAbi.Errors {
+			if strings.Contains(message, hex.EncodeToString(abiError.ID[:4])) {
+				return &RevertError{Name: abiError.Name, kind: revertKinds[abiError.Name].err, Err: err}
+			}
+		}
+		if !strings.Contains(message, "revert") {
+			return nil
+		}
+		return &RevertError{Name: "Error", Args: []interface{}{err.Error()}, kind: revertReasonKind(message), Err: err}
+	}
+
+	for _, abiError := range serviceManagerAbi.Errors {
+		if string(abiError.ID[:4]) != string(data[:4]) {
+			continue
+		}
+		args, unpackErr := abiError.Inputs.Unpack(data[4:])
+		if unpackErr != nil {
+			args = nil
+		}
+		return &RevertError{Name: abiError.Name, Args: args, kind: revertKinds[abiError.Name].err, Err: err}
+	}
+
+	// Require messages and panics
+	reason, unpackErr := abi.UnpackRevert(data)
+	if unpackErr != nil {
+		reason = "0x" + hex.EncodeToString(data)
+	}
+	return &RevertError{Name: "Error", Args: []interface{}{reason}, kind: revertReasonKind(strings.ToLower(reason)), Err: err}
+}
+
+func revertReasonKind(reason string) error {
+	for _, invalidSignatureReason := range invalidSignatureReasons {
+		if strings.Contains(reason, invalidSignatureReason) {
+			return ErrInvalidSignature
+		}
+	}
+	return nil
+}
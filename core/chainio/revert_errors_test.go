@@ -0,0 +1,105 @@
+package chainio
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	retry "github.com/yetanotherco/aligned_layer/core"
+)
+
+// Error returned by the rpc client when a call reverts, with the revert data
+type revertDataError struct {
+	data string
+}
+
+func (e revertDataError) Error() string          { return "execution reverted" }
+func (e revertDataError) ErrorCode() int         { return 3 }
+func (e revertDataError) ErrorData() interface{} { return e.data }
+
+func packRevert(t *testing.T, name string, args ...interface{}) error {
+	abiError, ok := serviceManagerAbi.Errors[name]
+	if !ok {
+		t.Fatalf("Unknown error %s", name)
+	}
+	packed, err := abiError.Inputs.Pack(args...)
+	if err != nil {
+		t.Fatalf("Could not pack %s: %v", name, err)
+	}
+	data := append(abiError.ID[:4:4], packed...)
+	return fmt.Errorf("respond to task: %w", revertDataError{data: "0x" + hex.EncodeToString(data)})
+}
+
+func packRevertReason(t *testing.T, reason string) error {
+	stringType, _ := abi.NewType("string", "", nil)
+	packed, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	if err != nil {
+		t.Fatalf("Could not pack reason: %v", err)
+	}
+	data := append([]byte{0x08, 0xc3, 0x79, 0xa0}, packed...)
+	return revertDataError{data: "0x" + hex.EncodeToString(data)}
+}
+
+func TestDecodeRevertErrorCustomErrors(t *testing.T) {
+	batcher := common.HexToAddress("0x7969c5eD335650692Bc04293B07F5BF2e7A673C0")
+	cases := []struct {
+		err       error
+		kind      error
+		label     string
+		permanent bool
+	}{
+		{packRevert(t, "BatchAlreadyResponded", [32]byte{1}), ErrBatchAlreadyResponded, "batch_already_responded", true},
+		{packRevert(t, "BatchDoesNotExist", [32]byte{1}), ErrBatchDoesNotExist, "batch_does_not_exist", false},
+		{packRevert(t, "InsufficientFunds", batcher, big.NewInt(10), big.NewInt(1)), ErrInsufficientFunds, "insufficient_funds", true},
+		{packRevert(t, "InvalidQuorumThreshold", big.NewInt(1), big.NewInt(2)), ErrInvalidQuorumThreshold, "invalid_quorum_threshold", true},
+		{packRevertReason(t, "BLSSignatureChecker.checkSignatures: signature is invalid"), ErrInvalidSignature, "invalid_signature", true},
+		{packRevertReason(t, "some other reason"), nil, "unknown", false},
+	}
+
+	for _, c := range cases {
+		revertErr := DecodeRevertError(c.err)
+		if revertErr == nil {
+			t.Fatalf("Expected %v to be decoded", c.err)
+		}
+		if c.kind != nil && !errors.Is(revertErr, c.kind) {
+			t.Errorf("Expected %v to be %v", revertErr, c.kind)
+		}
+		if !errors.Is(revertErr, ErrReverted) {
+			t.Errorf("Expected %v to be a revert", revertErr)
+		}
+		if revertErr.Label() != c.label {
+			t.Errorf("Expected label %s, got %s", c.label, revertErr.Label())
+		}
+		if revertErr.Permanent() != c.permanent {
+			t.Errorf("Expected %v to be permanent: %v", revertErr, c.permanent)
+		}
+	}
+
+	revertErr := DecodeRevertError(packRevert(t, "InsufficientFunds", batcher, big.NewInt(10), big.NewInt(1)))
+	if len(revertErr.Args) != 3 || revertErr.Args[0] != batcher || revertErr.Args[1].(*big.Int).Int64() != 10 {
+		t.Errorf("Unexpected arguments %v", revertErr.Args)
+	}
+}
+
+func TestDecodeRevertErrorWithoutData(t *testing.T) {
+	if DecodeRevertError(errors.New("connection refused")) != nil {
+		t.Errorf("Errors that are not reverts should not be decoded")
+	}
+
+	// Some clients only include the revert data in the message
+	selector := serviceManagerAbi.Errors["BatchAlreadyResponded"].ID
+	err := fmt.Errorf("execution reverted: custom error 0x%s", hex.EncodeToString(selector[:4]))
+	if !errors.Is(DecodeRevertError(err), ErrBatchAlreadyResponded) {
+		t.Errorf("Expected the selector in the message to be decoded")
+	}
+
+	// The retry layer unwraps permanent errors, the revert must still be matched
+	wrapped := fmt.Errorf("send: %w", retry.PermanentError{Inner: DecodeRevertError(err)})
+	if !errors.Is(wrapped, ErrBatchAlreadyResponded) || DecodeRevertError(wrapped) == nil {
+		t.Errorf("Expected the wrapped revert to be matched")
+	}
+}
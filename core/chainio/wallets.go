@@ -0,0 +1,159 @@
+package chainio
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/signer"
+	"github.com/ethereum/go-ethereum/common"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// Time a wallet rejected by the service manager is skipped, unless no other wallet is left
+const walletDisallowPeriod = 10 * time.Minute
+
+// SenderWallet is one of the accounts the aggregator sends transactions from, with its own nonces
+type SenderWallet struct {
+	Signer       signer.Signer
+	Address      common.Address
+	NonceManager *NonceManager
+
+	// Last fetched balance, nil if it was never fetched
+	balance         *big.Int
+	lowBalance      bool
+	disallowedUntil time.Time
+}
+
+// WalletPool distributes the transactions across the sender wallets: each one is sent from the wallet with the
+// fewest transactions in flight, skipping the wallets with low balance or rejected by the service manager
+// while another one is available
+type WalletPool struct {
+	wallets []*SenderWallet
+	// Wallets with a balance below it are skipped. Nil to never skip them
+	minBalance *big.Int
+	// Round robin start, so wallets with the same load take turns
+	next  int
+	mutex sync.Mutex
+}
+
+func NewWalletPool(wallets []*SenderWallet) *WalletPool {
+	return &WalletPool{wallets: wallets}
+}
+
+// SetMinBalance sets the balance under which wallets are skipped and reported as low balance
+func (p *WalletPool) SetMinBalance(minBalance *big.Int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.minBalance = minBalance
+}
+
+func (p *WalletPool) Wallets() []*SenderWallet {
+	return p.wallets
+}
+
+// Pick returns the wallet to send the next transaction from
+func (p *WalletPool) Pick() (*SenderWallet, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if len(p.wallets) == 0 {
+		return nil, fmt.Errorf("no sender wallet configured")
+	}
+
+	now := time.Now()
+	var picked *SenderWallet
+	pickedScore := 0
+	for i := range p.wallets {
+		wallet := p.wallets[(p.next+i)%len(p.wallets)]
+		// Lower is better: allowed wallets first, then the ones with enough balance, then the least loaded
+		score := wallet.NonceManager.InFlight()
+		if wallet.lowBalance {
+			score += 1 << 20
+		}
+		if now.Before(wallet.disallowedUntil) {
+			score += 1 << 30
+		}
+		if picked == nil || score < pickedScore {
+			picked = wallet
+			pickedScore = score
+		}
+	}
+	p.next = (p.next + 1) % len(p.wallets)
+	return picked, nil
+}
+
+// Disallow skips the wallet for a while, as the service manager rejected it as sender
+func (p *WalletPool) Disallow(wallet *SenderWallet) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	wallet.disallowedUntil = time.Now().Add(walletDisallowPeriod)
+}
+
+// AvailableWallets returns the number of wallets not rejected by the service manager
+func (p *WalletPool) AvailableWallets() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	available := 0
+	for _, wallet := range p.wallets {
+		if !time.Now().Before(wallet.disallowedUntil) {
+			available++
+		}
+	}
+	return available
+}
+
+// setBalance updates the balance of the wallet and reports whether it is below the min balance
+func (p *WalletPool) setBalance(wallet *SenderWallet, balance *big.Int) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	wallet.balance = balance
+	wallet.lowBalance = p.minBalance != nil && balance.Cmp(p.minBalance) < 0
+	return wallet.lowBalance
+}
+
+// Balance returns the last fetched balance of the wallet, nil if it was never fetched
+func (p *WalletPool) Balance(wallet *SenderWallet) *big.Int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return wallet.balance
+}
+
+// CheckWallets checks that the service manager accepts responses from every wallet, as the alignedAggregator
+// or one of the aggregatorSenders, and that no wallet is configured twice, as each wallet keeps its own nonces.
+// aggregatorSenders holds whether each wallet is allowed by the aggregatorSenders allowlist
+func (p *WalletPool) CheckWallets(alignedAggregator common.Address, aggregatorSenders map[common.Address]bool) error {
+	seen := make(map[common.Address]struct{}, len(p.wallets))
+	for _, wallet := range p.wallets {
+		if _, ok := seen[wallet.Address]; ok {
+			return fmt.Errorf("wallet %s is configured more than once", wallet.Address.Hex())
+		}
+		seen[wallet.Address] = struct{}{}
+		if wallet.Address != alignedAggregator && !aggregatorSenders[wallet.Address] {
+			return fmt.Errorf("wallet %s is neither the aggregator %s nor an aggregator sender of the service manager, its responses would be rejected",
+				wallet.Address.Hex(), alignedAggregator.Hex())
+		}
+	}
+	return nil
+}
+
+// RefreshWalletBalances fetches the balance of every wallet, updating the low balance ones and the balance metrics
+func (w *AvsWriter) RefreshWalletBalances(ctx context.Context) {
+	for _, wallet := range w.Wallets.Wallets() {
+		balance, err := w.BalanceAtRetryable(ctx, wallet.Address, nil, retry.NetworkRetryParams())
+		if err != nil {
+			w.logger.Warn("Failed to get wallet balance", "wallet", wallet.Address.Hex(), "err", err)
+			continue
+		}
+		lowBalance := w.Wallets.setBalance(wallet, balance)
+		if w.metrics != nil {
+			w.metrics.SetWalletBalance(wallet.Address.Hex(), utils.WeiToEth(balance), lowBalance)
+		}
+		if lowBalance {
+			w.logger.Warn("Aggregator wallet balance is low, it is only used if no other wallet is available",
+				"wallet", wallet.Address.Hex(), "balance", balance)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package chainio
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func newTestWallet(address byte) *SenderWallet {
+	reader := &fakeNonceReader{}
+	return &SenderWallet{
+		Address:      common.Address{address},
+		NonceManager: NewNonceManager(common.Address{address}, reader, reader),
+	}
+}
+
+func pickWallet(t *testing.T, pool *WalletPool) *SenderWallet {
+	wallet, err := pool.Pick()
+	if err != nil {
+		t.Fatalf("Could not pick wallet: %v", err)
+	}
+	return wallet
+}
+
+func TestWalletPoolPicksLeastLoaded(t *testing.T) {
+	first, second := newTestWallet(1), newTestWallet(2)
+	pool := NewWalletPool([]*SenderWallet{first, second})
+
+	acquireNonce(t, first.NonceManager)
+	if wallet := pickWallet(t, pool); wallet != second {
+		t.Errorf("Expected the wallet without transactions in flight, got %s", wallet.Address.Hex())
+	}
+
+	// Same load, wallets take turns
+	acquireNonce(t, second.NonceManager)
+	if pickWallet(t, pool) == pickWallet(t, pool) {
+		t.Errorf("Expected wallets with the same load to take turns")
+	}
+}
+
+func TestWalletPoolSkipsLowBalanceAndDisallowed(t *testing.T) {
+	first, second := newTestWallet(1), newTestWallet(2)
+	pool := NewWalletPool([]*SenderWallet{first, second})
+	pool.SetMinBalance(big.NewInt(100))
+
+	if !pool.setBalance(first, big.NewInt(99)) {
+		t.Fatalf("Expected low balance")
+	}
+	if pool.setBalance(second, big.NewInt(100)) {
+		t.Fatalf("Unexpected low balance")
+	}
+	// The low balance wallet is skipped even when it is less loaded
+	acquireNonce(t, second.NonceManager)
+	for i := 0; i < 2; i++ {
+		if wallet := pickWallet(t, pool); wallet != second {
+			t.Errorf("Expected the wallet with enough balance, got %s", wallet.Address.Hex())
+		}
+	}
+
+	// A disallowed wallet is only used if every other one is disallowed too
+	pool.Disallow(second)
+	if pool.AvailableWallets() != 1 {
+		t.Errorf("Expected 1 available wallet, got %d", pool.AvailableWallets())
+	}
+	if wallet := pickWallet(t, pool); wallet != first {
+		t.Errorf("Expected the allowed wallet, got %s", wallet.Address.Hex())
+	}
+	pool.Disallow(first)
+	if wallet := pickWallet(t, pool); wallet != second {
+		t.Errorf("Expected the disallowed wallet with enough balance, got %s", wallet.Address.Hex())
+	}
+
+	if _, err := NewWalletPool(nil).Pick(); err == nil {
+		t.Errorf("Expected an error without wallets")
+	}
+}
+
+func TestWalletPoolChecksWallets(t *testing.T) {
+	alignedAggregator := common.Address{1}
+	aggregatorSenders := map[common.Address]bool{{2}: true, {3}: false}
+	if err := NewWalletPool([]*SenderWallet{newTestWallet(1), newTestWallet(2)}).CheckWallets(alignedAggregator, aggregatorSenders); err != nil {
+		t.Errorf("Expected the aggregator and an aggregator sender to be accepted, got %v", err)
+	}
+	if err := NewWalletPool([]*SenderWallet{newTestWallet(1), newTestWallet(3)}).CheckWallets(alignedAggregator, aggregatorSenders); err == nil {
+		t.Errorf("Expected a wallet that is neither the aggregator nor an aggregator sender to be rejected")
+	}
+	if err := NewWalletPool([]*SenderWallet{newTestWallet(1), newTestWallet(1)}).CheckWallets(alignedAggregator, aggregatorSenders); err == nil {
+		t.Errorf("Expected a wallet configured twice to be rejected")
+	}
+}
@@ -15,39 +15,175 @@ type AggregatorConfig struct {
 	EcdsaConfig *EcdsaConfig
 	BlsConfig   *BlsConfig
 	Aggregator  struct {
-		ServerIpPortAddress           string
-		BlsPublicKeyCompendiumAddress common.Address
-		AvsServiceManagerAddress      common.Address
-		EnableMetrics                 bool
-		MetricsIpPortAddress          string
-		TelemetryIpPortAddress        string
-		GarbageCollectorPeriod        time.Duration
-		GarbageCollectorTasksAge      uint64
-		GarbageCollectorTasksInterval uint64
-		BlsServiceTaskTimeout         time.Duration
-		GasBaseBumpPercentage         uint
-		GasBumpIncrementalPercentage  uint
-		GasBumpPercentageLimit        uint
-		TimeToWaitBeforeBump          time.Duration
+		ServerIpPortAddress            string
+		BlsPublicKeyCompendiumAddress  common.Address
+		AvsServiceManagerAddress       common.Address
+		EnableMetrics                  bool
+		MetricsIpPortAddress           string
+		TelemetryIpPortAddress         string
+		GarbageCollectorPeriod         time.Duration
+		GarbageCollectorTasksAge       uint64
+		BlsServiceTaskTimeout          time.Duration
+		GasBaseBumpPercentage          uint
+		GasBumpIncrementalPercentage   uint
+		GasBumpPercentageLimit         uint
+		TimeToWaitBeforeBump           time.Duration
+		TaskStorePath                  string
+		TaskRecoveryBlocks             uint64
+		QuorumThresholdPercentage      uint8
+		QuorumRefreshPeriod            time.Duration
+		AdminApiIpPortAddress          string
+		AdminApiAuthToken              string
+		AdminApiWriteToken             string
+		DeadLetterStorePath            string
+		DeadLetterRetryPeriod          time.Duration
+		HaEnabled                      bool
+		HaInstanceId                   string
+		HaLockBackend                  string
+		HaLockFilePath                 string
+		HaLeaseDuration                time.Duration
+		RpcIpRateLimit                 float64
+		RpcIpRateBurst                 int
+		RpcOperatorRateLimit           float64
+		RpcOperatorRateBurst           int
+		RpcMaxConcurrentCalls          int
+		RpcMaxRequestBodySize          int64
+		RpcReadTimeout                 time.Duration
+		RpcWriteTimeout                time.Duration
+		RpcBypassOperatorIds           []string
+		RpcBypassIps                   []string
+		PendingResponseTtlBlocks       uint64
+		MaxPendingResponses            int
+		MaxTrackedTasks                int
+		OtelCollectorAddress           string
+		TelemetryQueueSize             int
+		TelemetryFlushSize             int
+		TelemetryFlushInterval         time.Duration
+		TelemetryRequestTimeout        time.Duration
+		TelemetrySinks                 []string
+		TelemetryFilePath              string
+		TelemetryFileMaxSize           int64
+		TelemetryFileMaxBackups        int
+		RespondToTaskConfirmations     uint64
+		ConfirmationCheckInterval      time.Duration
+		UnconfirmedResponseStorePath   string
+		GasFeeMode                     string
+		FeeHistoryBlocks               uint64
+		PriorityFeePercentile          float64
+		MaxInFlightResponses           int
+		WalletMinBalance               float64
+		WalletBalanceCheckPeriod       time.Duration
+		MaxGasPriceGwei                float64
+		MaxOverpaymentPerBatch         float64
+		GasSpendBudget                 float64
+		GasSpendBudgetWindow           time.Duration
+		GasPolicyAction                string
+		GasPolicyMaxWait               time.Duration
+		GasPolicyDeferralStorePath     string
+		ResponseDeferralEnabled        bool
+		ResponseDeferralMaxWait        time.Duration
+		ResponseDeferralCheckInterval  time.Duration
+		CostLedgerPath                 string
+		BroadcastRpcUrls               []string
+		PrivateRelayUrls               []string
+		PrivateRelayMethod             string
+		PrivateRelayOnly               bool
+		RelaySigningKeyStorePath       string
+		RelaySigningKeyStorePassword   string
+		PrivateRelayAuthHeader         string
+		RespondToTaskGasEstimate       uint64
+		MinFeasibilityScore            float64
+		InfeasibleTaskPolicy           string
+		AttestationCertificatesDir     string
+		MaxPendingResponsesPerOperator int
+		ResponseDeferralStorePath      string
 	}
 }
 
 type AggregatorConfigFromYaml struct {
 	Aggregator struct {
-		ServerIpPortAddress           string         `yaml:"server_ip_port_address"`
-		BlsPublicKeyCompendiumAddress common.Address `yaml:"bls_public_key_compendium_address"`
-		AvsServiceManagerAddress      common.Address `yaml:"avs_service_manager_address"`
-		EnableMetrics                 bool           `yaml:"enable_metrics"`
-		MetricsIpPortAddress          string         `yaml:"metrics_ip_port_address"`
-		TelemetryIpPortAddress        string         `yaml:"telemetry_ip_port_address"`
-		GarbageCollectorPeriod        time.Duration  `yaml:"garbage_collector_period"`
-		GarbageCollectorTasksAge      uint64         `yaml:"garbage_collector_tasks_age"`
-		GarbageCollectorTasksInterval uint64         `yaml:"garbage_collector_tasks_interval"`
-		BlsServiceTaskTimeout         time.Duration  `yaml:"bls_service_task_timeout"`
-		GasBaseBumpPercentage         uint           `yaml:"gas_base_bump_percentage"`
-		GasBumpIncrementalPercentage  uint           `yaml:"gas_bump_incremental_percentage"`
-		GasBumpPercentageLimit        uint           `yaml:"gas_bump_percentage_limit"`
-		TimeToWaitBeforeBump          time.Duration  `yaml:"time_to_wait_before_bump"`
+		ServerIpPortAddress            string         `yaml:"server_ip_port_address"`
+		BlsPublicKeyCompendiumAddress  common.Address `yaml:"bls_public_key_compendium_address"`
+		AvsServiceManagerAddress       common.Address `yaml:"avs_service_manager_address"`
+		EnableMetrics                  bool           `yaml:"enable_metrics"`
+		MetricsIpPortAddress           string         `yaml:"metrics_ip_port_address"`
+		TelemetryIpPortAddress         string         `yaml:"telemetry_ip_port_address"`
+		GarbageCollectorPeriod         time.Duration  `yaml:"garbage_collector_period"`
+		GarbageCollectorTasksAge       uint64         `yaml:"garbage_collector_tasks_age"`
+		BlsServiceTaskTimeout          time.Duration  `yaml:"bls_service_task_timeout"`
+		GasBaseBumpPercentage          uint           `yaml:"gas_base_bump_percentage"`
+		GasBumpIncrementalPercentage   uint           `yaml:"gas_bump_incremental_percentage"`
+		GasBumpPercentageLimit         uint           `yaml:"gas_bump_percentage_limit"`
+		TimeToWaitBeforeBump           time.Duration  `yaml:"time_to_wait_before_bump"`
+		TaskStorePath                  string         `yaml:"task_store_path"`
+		TaskRecoveryBlocks             uint64         `yaml:"task_recovery_blocks"`
+		QuorumThresholdPercentage      uint8          `yaml:"quorum_threshold_percentage"`
+		QuorumRefreshPeriod            time.Duration  `yaml:"quorum_refresh_period"`
+		AdminApiIpPortAddress          string         `yaml:"admin_api_ip_port_address"`
+		AdminApiAuthToken              string         `yaml:"admin_api_auth_token"`
+		AdminApiWriteToken             string         `yaml:"admin_api_write_token"`
+		DeadLetterStorePath            string         `yaml:"dead_letter_store_path"`
+		DeadLetterRetryPeriod          time.Duration  `yaml:"dead_letter_retry_period"`
+		HaEnabled                      bool           `yaml:"ha_enabled"`
+		HaInstanceId                   string         `yaml:"ha_instance_id"`
+		HaLockBackend                  string         `yaml:"ha_lock_backend"`
+		HaLockFilePath                 string         `yaml:"ha_lock_file_path"`
+		HaLeaseDuration                time.Duration  `yaml:"ha_lease_duration"`
+		RpcIpRateLimit                 float64        `yaml:"rpc_ip_rate_limit"`
+		RpcIpRateBurst                 int            `yaml:"rpc_ip_rate_burst"`
+		RpcOperatorRateLimit           float64        `yaml:"rpc_operator_rate_limit"`
+		RpcOperatorRateBurst           int            `yaml:"rpc_operator_rate_burst"`
+		RpcMaxConcurrentCalls          int            `yaml:"rpc_max_concurrent_calls"`
+		RpcMaxRequestBodySize          int64          `yaml:"rpc_max_request_body_size"`
+		RpcReadTimeout                 time.Duration  `yaml:"rpc_read_timeout"`
+		RpcWriteTimeout                time.Duration  `yaml:"rpc_write_timeout"`
+		RpcBypassOperatorIds           []string       `yaml:"rpc_bypass_operator_ids"`
+		RpcBypassIps                   []string       `yaml:"rpc_bypass_ips"`
+		PendingResponseTtlBlocks       uint64         `yaml:"pending_response_ttl_blocks"`
+		MaxPendingResponses            int            `yaml:"max_pending_responses"`
+		MaxTrackedTasks                int            `yaml:"max_tracked_tasks"`
+		OtelCollectorAddress           string         `yaml:"otel_collector_address"`
+		TelemetryQueueSize             int            `yaml:"telemetry_queue_size"`
+		TelemetryFlushSize             int            `yaml:"telemetry_flush_size"`
+		TelemetryFlushInterval         time.Duration  `yaml:"telemetry_flush_interval"`
+		TelemetryRequestTimeout        time.Duration  `yaml:"telemetry_request_timeout"`
+		TelemetrySinks                 []string       `yaml:"telemetry_sinks"`
+		TelemetryFilePath              string         `yaml:"telemetry_file_path"`
+		TelemetryFileMaxSize           int64          `yaml:"telemetry_file_max_size"`
+		TelemetryFileMaxBackups        int            `yaml:"telemetry_file_max_backups"`
+		RespondToTaskConfirmations     uint64         `yaml:"respond_to_task_confirmations"`
+		ConfirmationCheckInterval      time.Duration  `yaml:"confirmation_check_interval"`
+		UnconfirmedResponseStorePath   string         `yaml:"unconfirmed_response_store_path"`
+		GasFeeMode                     string         `yaml:"gas_fee_mode"`
+		FeeHistoryBlocks               uint64         `yaml:"fee_history_blocks"`
+		PriorityFeePercentile          float64        `yaml:"priority_fee_percentile"`
+		MaxInFlightResponses           int            `yaml:"max_in_flight_responses"`
+		WalletMinBalance               float64        `yaml:"wallet_min_balance"`
+		WalletBalanceCheckPeriod       time.Duration  `yaml:"wallet_balance_check_period"`
+		MaxGasPriceGwei                float64        `yaml:"max_gas_price_gwei"`
+		MaxOverpaymentPerBatch         float64        `yaml:"max_overpayment_per_batch"`
+		GasSpendBudget                 float64        `yaml:"gas_spend_budget"`
+		GasSpendBudgetWindow           time.Duration  `yaml:"gas_spend_budget_window"`
+		GasPolicyAction                string         `yaml:"gas_policy_action"`
+		GasPolicyMaxWait               time.Duration  `yaml:"gas_policy_max_wait"`
+		GasPolicyDeferralStorePath     string         `yaml:"gas_policy_deferral_store_path"`
+		ResponseDeferralEnabled        bool           `yaml:"response_deferral_enabled"`
+		ResponseDeferralMaxWait        time.Duration  `yaml:"response_deferral_max_wait"`
+		ResponseDeferralCheckInterval  time.Duration  `yaml:"response_deferral_check_interval"`
+		CostLedgerPath                 string         `yaml:"cost_ledger_path"`
+		BroadcastRpcUrls               []string       `yaml:"broadcast_rpc_urls"`
+		PrivateRelayUrls               []string       `yaml:"private_relay_urls"`
+		PrivateRelayMethod             string         `yaml:"private_relay_method"`
+		PrivateRelayOnly               bool           `yaml:"private_relay_only"`
+		RelaySigningKeyStorePath       string         `yaml:"private_relay_signing_key_store_path"`
+		RelaySigningKeyStorePassword   string         `yaml:"private_relay_signing_key_store_password"`
+		PrivateRelayAuthHeader         string         `yaml:"private_relay_auth_header"`
+		RespondToTaskGasEstimate       uint64         `yaml:"respond_to_task_gas_estimate"`
+		MinFeasibilityScore            float64        `yaml:"min_fee_feasibility_score"`
+		InfeasibleTaskPolicy           string         `yaml:"infeasible_task_policy"`
+		AttestationCertificatesDir     string         `yaml:"attestation_certificates_dir"`
+		MaxPendingResponsesPerOperator int            `yaml:"max_pending_responses_per_operator"`
+		ResponseDeferralStorePath      string         `yaml:"response_deferral_store_path"`
 	} `yaml:"aggregator"`
 }
 
@@ -83,20 +219,88 @@ func NewAggregatorConfig(configFilePath string) *AggregatorConfig {
 		EcdsaConfig: ecdsaConfig,
 		BlsConfig:   blsConfig,
 		Aggregator: struct {
-			ServerIpPortAddress           string
-			BlsPublicKeyCompendiumAddress common.Address
-			AvsServiceManagerAddress      common.Address
-			EnableMetrics                 bool
-			MetricsIpPortAddress          string
-			TelemetryIpPortAddress        string
-			GarbageCollectorPeriod        time.Duration
-			GarbageCollectorTasksAge      uint64
-			GarbageCollectorTasksInterval uint64
-			BlsServiceTaskTimeout         time.Duration
-			GasBaseBumpPercentage         uint
-			GasBumpIncrementalPercentage  uint
-			GasBumpPercentageLimit        uint
-			TimeToWaitBeforeBump          time.Duration
+			ServerIpPortAddress            string
+			BlsPublicKeyCompendiumAddress  common.Address
+			AvsServiceManagerAddress       common.Address
+			EnableMetrics                  bool
+			MetricsIpPortAddress           string
+			TelemetryIpPortAddress         string
+			GarbageCollectorPeriod         time.Duration
+			GarbageCollectorTasksAge       uint64
+			BlsServiceTaskTimeout          time.Duration
+			GasBaseBumpPercentage          uint
+			GasBumpIncrementalPercentage   uint
+			GasBumpPercentageLimit         uint
+			TimeToWaitBeforeBump           time.Duration
+			TaskStorePath                  string
+			TaskRecoveryBlocks             uint64
+			QuorumThresholdPercentage      uint8
+			QuorumRefreshPeriod            time.Duration
+			AdminApiIpPortAddress          string
+			AdminApiAuthToken              string
+			AdminApiWriteToken             string
+			DeadLetterStorePath            string
+			DeadLetterRetryPeriod          time.Duration
+			HaEnabled                      bool
+			HaInstanceId                   string
+			HaLockBackend                  string
+			HaLockFilePath                 string
+			HaLeaseDuration                time.Duration
+			RpcIpRateLimit                 float64
+			RpcIpRateBurst                 int
+			RpcOperatorRateLimit           float64
+			RpcOperatorRateBurst           int
+			RpcMaxConcurrentCalls          int
+			RpcMaxRequestBodySize          int64
+			RpcReadTimeout                 time.Duration
+			RpcWriteTimeout                time.Duration
+			RpcBypassOperatorIds           []string
+			RpcBypassIps                   []string
+			PendingResponseTtlBlocks       uint64
+			MaxPendingResponses            int
+			MaxTrackedTasks                int
+			OtelCollectorAddress           string
+			TelemetryQueueSize             int
+			TelemetryFlushSize             int
+			TelemetryFlushInterval         time.Duration
+			TelemetryRequestTimeout        time.Duration
+			TelemetrySinks                 []string
+			TelemetryFilePath              string
+			TelemetryFileMaxSize           int64
+			TelemetryFileMaxBackups        int
+			RespondToTaskConfirmations     uint64
+			ConfirmationCheckInterval      time.Duration
+			UnconfirmedResponseStorePath   string
+			GasFeeMode                     string
+			FeeHistoryBlocks               uint64
+			PriorityFeePercentile          float64
+			MaxInFlightResponses           int
+			WalletMinBalance               float64
+			WalletBalanceCheckPeriod       time.Duration
+			MaxGasPriceGwei                float64
+			MaxOverpaymentPerBatch         float64
+			GasSpendBudget                 float64
+			GasSpendBudgetWindow           time.Duration
+			GasPolicyAction                string
+			GasPolicyMaxWait               time.Duration
+			GasPolicyDeferralStorePath     string
+			ResponseDeferralEnabled        bool
+			ResponseDeferralMaxWait        time.Duration
+			ResponseDeferralCheckInterval  time.Duration
+			CostLedgerPath                 string
+			BroadcastRpcUrls               []string
+			PrivateRelayUrls               []string
+			PrivateRelayMethod             string
+			PrivateRelayOnly               bool
+			RelaySigningKeyStorePath       string
+			RelaySigningKeyStorePassword   string
+			PrivateRelayAuthHeader         string
+			RespondToTaskGasEstimate       uint64
+			MinFeasibilityScore            float64
+			InfeasibleTaskPolicy           string
+			AttestationCertificatesDir     string
+			MaxPendingResponsesPerOperator int
+			ResponseDeferralStorePath      string
 		}(aggregatorConfigFromYaml.Aggregator),
 	}
 }
@@ -15,12 +15,18 @@ import (
 type EcdsaConfig struct {
 	PrivateKey *ecdsa.PrivateKey
 	Signer     signer.Signer
+	// Keys of extra accounts transactions can be sent from, only used by the aggregator
+	AdditionalPrivateKeys []*ecdsa.PrivateKey
 }
 
 type EcdsaConfigFromYaml struct {
 	Ecdsa struct {
-		PrivateKeyStorePath     string `yaml:"private_key_store_path"`
-		PrivateKeyStorePassword string `yaml:"private_key_store_password"`
+		PrivateKeyStorePath        string `yaml:"private_key_store_path"`
+		PrivateKeyStorePassword    string `yaml:"private_key_store_password"`
+		AdditionalPrivateKeyStores []struct {
+			Path     string `yaml:"path"`
+			Password string `yaml:"password"`
+		} `yaml:"additional_private_key_stores"`
 	} `yaml:"ecdsa"`
 }
 
@@ -49,8 +55,18 @@ func NewEcdsaConfig(ecdsaConfigFilePath string, chainId *big.Int) *EcdsaConfig {
 		log.Fatal("Error creating private key signer: ", err)
 	}
 
+	var additionalPrivateKeys []*ecdsa.PrivateKey
+	for _, keyStore := range ecdsaConfigFromYaml.Ecdsa.AdditionalPrivateKeyStores {
+		additionalKeyPair, err := ecdsa2.ReadKey(keyStore.Path, keyStore.Password)
+		if err != nil {
+			log.Fatal("Error reading additional ecdsa private key from file: ", err)
+		}
+		additionalPrivateKeys = append(additionalPrivateKeys, additionalKeyPair)
+	}
+
 	return &EcdsaConfig{
-		PrivateKey: ecdsaKeyPair,
-		Signer:     privateKeySigner,
+		PrivateKey:            ecdsaKeyPair,
+		Signer:                privateKeySigner,
+		AdditionalPrivateKeys: additionalPrivateKeys,
 	}
 }
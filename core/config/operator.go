@@ -27,6 +27,8 @@ type OperatorConfig struct {
 		MetricsIpPortAddress          string
 		MaxBatchSize                  int64
 		LastProcessedBatchFilePath    string
+		RegisterQuorumNumbers         []uint8
+		StandbyAggregatorAddresses    []string
 	}
 }
 
@@ -44,6 +46,8 @@ type OperatorConfigFromYaml struct {
 		MetricsIpPortAddress          string         `yaml:"metrics_ip_port_address"`
 		MaxBatchSize                  int64          `yaml:"max_batch_size"`
 		LastProcessedBatchFilePath    string         `yaml:"last_processed_batch_filepath"`
+		RegisterQuorumNumbers         []uint8        `yaml:"register_quorum_numbers"`
+		StandbyAggregatorAddresses    []string       `yaml:"standby_aggregator_rpc_server_ip_port_addresses"`
 	} `yaml:"operator"`
 	BlsConfigFromYaml   BlsConfigFromYaml   `yaml:"bls"`
 }
@@ -87,6 +91,8 @@ func NewOperatorConfig(configFilePath string) *OperatorConfig {
 			MetricsIpPortAddress          string
 			MaxBatchSize                  int64
 			LastProcessedBatchFilePath    string
+			RegisterQuorumNumbers         []uint8
+			StandbyAggregatorAddresses    []string
 		}(operatorConfigFromYaml.Operator),
 	}
 }
@@ -44,6 +44,10 @@ const (
 	RespondToTaskV2MaxInterval           = time.Millisecond * 500 // Maximum interval for an individual retry.
 	RespondToTaskV2MaxElapsedTime        = 0                      //	Maximum time all retries may take. `0` corresponds to no limit on the time of the retries.
 	RespondToTaskV2NumRetries     uint64 = 0                      // Total number of retries attempted. If 0, retries indefinitely until maxElapsedTime is reached.
+
+	// Retry Params for the telemetry messages, kept short as telemetry must not fall behind
+	TelemetryInitialInterval = 500 * time.Millisecond // Initial delay for retry interval.
+	TelemetryMaxInterval     = 5 * time.Second        // Maximum interval for an individual retry.
 )
 
 type RetryParams struct {
@@ -88,6 +92,17 @@ func RespondToTaskV2() *RetryParams {
 	}
 }
 
+func TelemetryRetryParams() *RetryParams {
+	return &RetryParams{
+		InitialInterval:     TelemetryInitialInterval,
+		MaxInterval:         TelemetryMaxInterval,
+		MaxElapsedTime:      NetworkMaxElapsedTime,
+		RandomizationFactor: NetworkRandomizationFactor,
+		Multiplier:          NetworkMultiplier,
+		NumRetries:          NetworkNumRetries,
+	}
+}
+
 // WaitForTxRetryParams returns the retry parameters for waiting for a transaction to be included in a block.
 // maxElapsedTime is received as parameter to allow for a custom timeout
 // These parameters are used for the bumping fees logic.
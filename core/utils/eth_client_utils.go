@@ -3,9 +3,11 @@ package utils
 import (
 	"context"
 	"math/big"
+	"sort"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
 	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	retry "github.com/yetanotherco/aligned_layer/core"
@@ -60,6 +62,12 @@ func WeiToEth(wei *big.Int) float64 {
 	return eth
 }
 
+func EthToWei(eth float64) *big.Int {
+	weiFloat := new(big.Float).Mul(big.NewFloat(eth), big.NewFloat(1e18))
+	wei, _ := weiFloat.Int(nil)
+	return wei
+}
+
 // Simple algorithm to calculate the gasPrice bump based on:
 // the currentGasPrice, a base bump percentage, a retry percentage, and the retry count.
 // Formula: currentGasPrice + (currentGasPrice * (baseBumpPercentage + retryCount * incrementalRetryPercentage) / 100)
@@ -86,6 +94,21 @@ func CalculateGasPriceBumpBasedOnRetry(currentGasPrice *big.Int, baseBumpPercent
 	return bumpedGasPrice
 }
 
+// Least a fee is raised by to replace a transaction, so fees of 0 or a few wei are bumped too
+var minimumReplacementIncrement = big.NewInt(1_000_000_000) // 1 gwei
+
+// CalculateReplacementFee returns the lowest fee a transaction has to pay to replace a pending one that paid
+// previousFee: nodes require 10% more, and at least 1 gwei more is added so low fees still move
+func CalculateReplacementFee(previousFee *big.Int) *big.Int {
+	minimumFee := new(big.Int).Mul(previousFee, big.NewInt(110))
+	minimumFee.Div(minimumFee, big.NewInt(100))
+	incrementedFee := new(big.Int).Add(previousFee, minimumReplacementIncrement)
+	if incrementedFee.Cmp(minimumFee) > 0 {
+		return incrementedFee
+	}
+	return minimumFee
+}
+
 /*
 GetGasPriceRetryable
 Get the gas price from the client with retry logic.
@@ -106,3 +129,123 @@ func GetGasPriceRetryable(client eth.InstrumentedClient, fallbackClient eth.Inst
 	}
 	return retry.RetryWithData(respondToTaskV2_func, config)
 }
+
+// DynamicFees are the fees of an EIP-1559 transaction
+type DynamicFees struct {
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+	// Base fee of the next block, only set by CalculateDynamicFees
+	BaseFee *big.Int
+}
+
+// EffectiveGasPrice returns the price per gas a transaction with these fees pays in the next block:
+// the base fee plus the priority fee, capped by the fee cap
+func (f DynamicFees) EffectiveGasPrice() *big.Int {
+	gasPrice := new(big.Int)
+	if f.BaseFee != nil {
+		gasPrice.Set(f.BaseFee)
+	}
+	if f.GasTipCap != nil {
+		gasPrice.Add(gasPrice, f.GasTipCap)
+	}
+	if f.GasFeeCap != nil && gasPrice.Cmp(f.GasFeeCap) > 0 {
+		gasPrice.Set(f.GasFeeCap)
+	}
+	return gasPrice
+}
+
+// DynamicFeeParams sets how the fees of EIP-1559 transactions are estimated:
+// the priority fee is the median of the RewardPercentile of the priority fees paid in the last FeeHistoryBlocks blocks
+type DynamicFeeParams struct {
+	FeeHistoryBlocks uint64
+	RewardPercentile float64
+}
+
+// Calculates the fees from the fee history, which must include a single reward percentile.
+// The fee cap is twice the base fee of the next block plus the priority fee, so the transaction stays includable
+// for several blocks of base fee increases. Returns a nil priority fee if no block in the history has rewards
+func CalculateDynamicFees(feeHistory *ethereum.FeeHistory) DynamicFees {
+	nextBaseFee := big.NewInt(0)
+	if len(feeHistory.BaseFee) > 0 {
+		nextBaseFee = feeHistory.BaseFee[len(feeHistory.BaseFee)-1]
+	}
+
+	var rewards []*big.Int
+	for _, blockRewards := range feeHistory.Reward {
+		if len(blockRewards) > 0 && blockRewards[0] != nil {
+			rewards = append(rewards, blockRewards[0])
+		}
+	}
+	if len(rewards) == 0 {
+		return DynamicFees{GasFeeCap: new(big.Int).Mul(nextBaseFee, big.NewInt(2)), BaseFee: nextBaseFee}
+	}
+	sort.Slice(rewards, func(i, j int) bool {
+		return rewards[i].Cmp(rewards[j]) < 0
+	})
+	gasTipCap := new(big.Int).Set(rewards[len(rewards)/2])
+
+	gasFeeCap := new(big.Int).Mul(nextBaseFee, big.NewInt(2))
+	gasFeeCap.Add(gasFeeCap, gasTipCap)
+	return DynamicFees{GasFeeCap: gasFeeCap, GasTipCap: gasTipCap, BaseFee: nextBaseFee}
+}
+
+// Bumps the fees based on the retry like CalculateGasPriceBumpBasedOnRetry. To replace a previous transaction,
+// both the fee cap and the priority fee have to be raised at least to CalculateReplacementFee of the previous ones,
+// so each of them is raised to that minimum if the bump falls short
+func CalculateDynamicFeesBumpBasedOnRetry(currentFees DynamicFees, previousFees *DynamicFees, baseBumpPercentage uint, retryAttemptPercentage uint, bumpPercentageLimit uint, retryCount int) DynamicFees {
+	bumpedFees := DynamicFees{
+		GasFeeCap: CalculateGasPriceBumpBasedOnRetry(currentFees.GasFeeCap, baseBumpPercentage, retryAttemptPercentage, bumpPercentageLimit, retryCount),
+		GasTipCap: CalculateGasPriceBumpBasedOnRetry(currentFees.GasTipCap, baseBumpPercentage, retryAttemptPercentage, bumpPercentageLimit, retryCount),
+		BaseFee:   currentFees.BaseFee,
+	}
+	if previousFees == nil {
+		return bumpedFees
+	}
+
+	minimumGasFeeCap := CalculateReplacementFee(previousFees.GasFeeCap)
+	if bumpedFees.GasFeeCap.Cmp(minimumGasFeeCap) < 0 {
+		bumpedFees.GasFeeCap = minimumGasFeeCap
+	}
+	minimumGasTipCap := CalculateReplacementFee(previousFees.GasTipCap)
+	if bumpedFees.GasTipCap.Cmp(minimumGasTipCap) < 0 {
+		bumpedFees.GasTipCap = minimumGasTipCap
+	}
+	// The priority fee can't be higher than the fee cap
+	if bumpedFees.GasTipCap.Cmp(bumpedFees.GasFeeCap) > 0 {
+		bumpedFees.GasFeeCap = new(big.Int).Set(bumpedFees.GasTipCap)
+	}
+	return bumpedFees
+}
+
+/*
+GetDynamicFeesRetryable
+Get the EIP-1559 fees from the fee history of the client with retry logic.
+If the fee history has no rewards, the priority fee suggested by the client is used.
+- All errors are considered Transient Errors
+- Retry times: 1 sec, 2 sec, 4 sec
+*/
+func GetDynamicFeesRetryable(client eth.InstrumentedClient, fallbackClient eth.InstrumentedClient, params DynamicFeeParams, config *retry.RetryParams) (*DynamicFees, error) {
+	dynamicFees_func := func() (*DynamicFees, error) {
+		feeHistory, err := client.FeeHistory(context.Background(), params.FeeHistoryBlocks, nil, []float64{params.RewardPercentile})
+		if err != nil {
+			feeHistory, err = fallbackClient.FeeHistory(context.Background(), params.FeeHistoryBlocks, nil, []float64{params.RewardPercentile})
+			if err != nil {
+				return nil, err
+			}
+		}
+		fees := CalculateDynamicFees(feeHistory)
+		if fees.GasTipCap == nil {
+			gasTipCap, err := client.SuggestGasTipCap(context.Background())
+			if err != nil {
+				gasTipCap, err = fallbackClient.SuggestGasTipCap(context.Background())
+				if err != nil {
+					return nil, err
+				}
+			}
+			fees.GasTipCap = gasTipCap
+			fees.GasFeeCap = new(big.Int).Add(fees.GasFeeCap, gasTipCap)
+		}
+		return &fees, nil
+	}
+	return retry.RetryWithData(dynamicFees_func, config)
+}
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/yetanotherco/aligned_layer/core/utils"
 )
 
@@ -64,3 +65,78 @@ func TestCalculateGasPriceBumpBasedOnRetryPercentageLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestCalculateDynamicFees(t *testing.T) {
+	feeHistory := &ethereum.FeeHistory{
+		Reward: [][]*big.Int{
+			{big.NewInt(3000000000)},
+			{big.NewInt(1000000000)},
+			{},
+			{big.NewInt(2000000000)},
+		},
+		// The last base fee is the one of the next block
+		BaseFee: []*big.Int{
+			big.NewInt(10000000000),
+			big.NewInt(11000000000),
+			big.NewInt(12000000000),
+			big.NewInt(13000000000),
+			big.NewInt(14000000000),
+		},
+	}
+
+	fees := utils.CalculateDynamicFees(feeHistory)
+	if fees.GasTipCap.Cmp(big.NewInt(2000000000)) != 0 {
+		t.Errorf("Priority fee should be the median reward, expected 2000000000, got %v", fees.GasTipCap)
+	}
+	if fees.GasFeeCap.Cmp(big.NewInt(30000000000)) != 0 {
+		t.Errorf("Fee cap should be twice the next base fee plus the priority fee, expected 30000000000, got %v", fees.GasFeeCap)
+	}
+	if gasPrice := fees.EffectiveGasPrice(); gasPrice.Cmp(big.NewInt(16000000000)) != 0 {
+		t.Errorf("Effective gas price should be the next base fee plus the priority fee, expected 16000000000, got %v", gasPrice)
+	}
+
+	feeHistory.Reward = [][]*big.Int{{}, {}}
+	if fees := utils.CalculateDynamicFees(feeHistory); fees.GasTipCap != nil {
+		t.Errorf("Priority fee should not be set without rewards, got %v", fees.GasTipCap)
+	}
+}
+
+func TestCalculateDynamicFeesBumpBasedOnRetry(t *testing.T) {
+	fees := utils.DynamicFees{GasFeeCap: big.NewInt(30000000000), GasTipCap: big.NewInt(2000000000)}
+
+	bumpedFees := utils.CalculateDynamicFeesBumpBasedOnRetry(fees, nil, 20, 5, 100, 1)
+	if bumpedFees.GasFeeCap.Cmp(big.NewInt(37500000000)) != 0 || bumpedFees.GasTipCap.Cmp(big.NewInt(2500000000)) != 0 {
+		t.Errorf("Unexpected bumped fees, got fee cap %v and priority fee %v", bumpedFees.GasFeeCap, bumpedFees.GasTipCap)
+	}
+
+	// The base fee dropped, but the replacement must pay at least 10% more than the previous transaction on both fees
+	previousFees := utils.DynamicFees{GasFeeCap: big.NewInt(50000000000), GasTipCap: big.NewInt(1000000000)}
+	replacementFees := utils.CalculateDynamicFeesBumpBasedOnRetry(fees, &previousFees, 20, 5, 100, 1)
+	if replacementFees.GasFeeCap.Cmp(big.NewInt(55000000000)) != 0 {
+		t.Errorf("Fee cap should be 10%% over the previous one, expected 55000000000, got %v", replacementFees.GasFeeCap)
+	}
+	if replacementFees.GasTipCap.Cmp(big.NewInt(2500000000)) != 0 {
+		t.Errorf("Priority fee should keep its bump, expected 2500000000, got %v", replacementFees.GasTipCap)
+	}
+}
+
+func TestCalculateDynamicFeesBumpBasedOnRetryZeroPriorityFee(t *testing.T) {
+	// 10% of a priority fee of 0 is still 0, the replacement adds the minimum increment instead
+	fees := utils.DynamicFees{GasFeeCap: big.NewInt(30000000000), GasTipCap: big.NewInt(0)}
+	replacementFees := utils.CalculateDynamicFeesBumpBasedOnRetry(fees, &fees, 20, 5, 100, 1)
+	if replacementFees.GasTipCap.Cmp(big.NewInt(1000000000)) != 0 {
+		t.Errorf("Priority fee should be bumped by 1 gwei, got %v", replacementFees.GasTipCap)
+	}
+	if replacementFees.GasFeeCap.Cmp(big.NewInt(37500000000)) != 0 {
+		t.Errorf("Fee cap should keep its bump, expected 37500000000, got %v", replacementFees.GasFeeCap)
+	}
+}
+
+func TestCalculateReplacementFee(t *testing.T) {
+	if fee := utils.CalculateReplacementFee(big.NewInt(50000000000)); fee.Cmp(big.NewInt(55000000000)) != 0 {
+		t.Errorf("Expected 10%% more, got %v", fee)
+	}
+	if fee := utils.CalculateReplacementFee(big.NewInt(7)); fee.Cmp(big.NewInt(1000000007)) != 0 {
+		t.Errorf("Expected 1 gwei more, got %v", fee)
+	}
+}
@@ -24,6 +24,45 @@ type Metrics struct {
 	aggregatorGasCostPaidTotal             prometheus.Counter
 	aggregatorRespondToTaskLatency         prometheus.Gauge
 	aggregatorTaskQuorumReachedLatency     prometheus.Gauge
+	aggregatorDeadLetters                  prometheus.Counter
+	aggregatorDeadLettersExpired           prometheus.Counter
+	aggregatorDeadLettersPending           prometheus.Gauge
+	aggregatorDeadLetterReplays            *prometheus.CounterVec
+	aggregatorIsLeader                     prometheus.Gauge
+	aggregatorLeaderTakeovers              prometheus.Counter
+	aggregatorRejectedOperatorSignatures   *prometheus.CounterVec
+	aggregatorRpcLimits                    *prometheus.GaugeVec
+	aggregatorRpcRejectedCalls             *prometheus.CounterVec
+	aggregatorRpcInflightCalls             prometheus.Gauge
+	aggregatorOperatorTasksSigned          *prometheus.CounterVec
+	aggregatorOperatorTasksMissed          *prometheus.CounterVec
+	aggregatorOperatorInvalidSignatures    *prometheus.CounterVec
+	aggregatorOperatorResponseLatency      *prometheus.HistogramVec
+	aggregatorOperatorLastSeen             *prometheus.GaugeVec
+	aggregatorPendingOperatorResponses     *prometheus.CounterVec
+	aggregatorPendingOperatorResponsesSize prometheus.Gauge
+	aggregatorEvictedTasks                 *prometheus.CounterVec
+	aggregatorTrackedTasks                 prometheus.Gauge
+	aggregatorTelemetryMessages            *prometheus.CounterVec
+	aggregatorTelemetryQueueSize           prometheus.Gauge
+	aggregatorRespondToTaskReverts         *prometheus.CounterVec
+	aggregatorUnconfirmedResponses         prometheus.Gauge
+	aggregatorConfirmationEvents           *prometheus.CounterVec
+	aggregatorNonceResyncs                 prometheus.Counter
+	aggregatorInFlightResponses            prometheus.Gauge
+	aggregatorWalletBalance                *prometheus.GaugeVec
+	aggregatorWalletLowBalance             *prometheus.GaugeVec
+	aggregatorWalletTransactions           *prometheus.CounterVec
+	aggregatorGasPolicyDecisions           *prometheus.CounterVec
+	aggregatorGasSpendInWindow             prometheus.Gauge
+	aggregatorDeferredResponses            prometheus.Gauge
+	aggregatorDeferredResponsesSubmitted   *prometheus.CounterVec
+	aggregatorBroadcastResults             *prometheus.CounterVec
+	aggregatorBroadcastInclusions          *prometheus.CounterVec
+	aggregatorInfeasibleTasks              *prometheus.CounterVec
+	aggregatorReceivedTasksByVersion       *prometheus.CounterVec
+	aggregatedResponsesByVersion           *prometheus.CounterVec
+	aggregatorAttestationCertificates      *prometheus.CounterVec
 }
 
 const alignedNamespace = "aligned"
@@ -77,6 +116,202 @@ func NewMetrics(ipPortAddress string, reg prometheus.Registerer, logger logging.
 			Name:      "aggregator_task_quorum_reached_latency",
 			Help:      "Time it takes for a task to reach quorum",
 		}),
+		aggregatorDeadLetters: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_dead_letters_count",
+			Help:      "Number of aggregated responses that failed to be sent and were stored as dead letters",
+		}),
+		aggregatorDeadLettersExpired: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_dead_letters_expired_count",
+			Help:      "Number of dead letters removed because their batch was already responded",
+		}),
+		aggregatorDeadLettersPending: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_dead_letters_pending",
+			Help:      "Number of dead letters waiting to be re-attempted",
+		}),
+		aggregatorDeadLetterReplays: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_dead_letter_replays_count",
+			Help:      "Number of dead letter re-attempts, by result",
+		}, []string{"result"}),
+		aggregatorIsLeader: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_is_leader",
+			Help:      "1 if this aggregator instance is the leader submitting responses onchain, 0 if it is a standby",
+		}),
+		aggregatorLeaderTakeovers: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_leader_takeovers_count",
+			Help:      "Number of times this aggregator instance was elected as leader",
+		}),
+		aggregatorRejectedOperatorSignatures: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_rejected_operator_signatures_count",
+			Help:      "Number of operator signatures rejected before aggregation, by reason",
+		}, []string{"reason"}),
+		aggregatorRpcLimits: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_rpc_limit",
+			Help:      "Configured limits of the aggregator operators rpc server, 0 means disabled",
+		}, []string{"limit"}),
+		aggregatorRpcRejectedCalls: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_rpc_rejected_calls_count",
+			Help:      "Number of aggregator rpc calls rejected by the rpc limits, by reason",
+		}, []string{"reason"}),
+		aggregatorRpcInflightCalls: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_rpc_inflight_calls",
+			Help:      "Number of aggregator rpc calls being processed",
+		}),
+		aggregatorOperatorTasksSigned: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_operator_tasks_signed_count",
+			Help:      "Number of tasks each operator signed",
+		}, []string{"operator_id"}),
+		aggregatorOperatorTasksMissed: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_operator_tasks_missed_count",
+			Help:      "Number of tasks each operator did not sign before quorum was reached or the task expired",
+		}, []string{"operator_id"}),
+		aggregatorOperatorInvalidSignatures: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_operator_invalid_signatures_count",
+			Help:      "Number of invalid signatures sent by each registered operator",
+		}, []string{"operator_id"}),
+		aggregatorOperatorResponseLatency: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_operator_response_latency_seconds",
+			Help:      "Time between the task creation and each operator signature being accepted",
+			Buckets:   []float64{1, 2, 5, 10, 20, 30, 60, 120, 300},
+		}, []string{"operator_id"}),
+		aggregatorOperatorLastSeen: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_operator_last_seen_timestamp_seconds",
+			Help:      "Unix time of the last signature accepted from each operator",
+		}, []string{"operator_id"}),
+		aggregatorPendingOperatorResponses: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_pending_operator_responses_count",
+			Help:      "Number of operator responses received before their task, by what happened to them: buffered, replayed, expired or dropped",
+		}, []string{"event"}),
+		aggregatorPendingOperatorResponsesSize: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_pending_operator_responses",
+			Help:      "Number of operator responses waiting for their task to be added",
+		}),
+		aggregatorEvictedTasks: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_evicted_tasks_count",
+			Help:      "Number of tasks evicted from memory, by reason",
+		}, []string{"reason"}),
+		aggregatorTrackedTasks: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_tracked_tasks",
+			Help:      "Number of tasks kept in memory by the aggregator",
+		}),
+		aggregatorTelemetryMessages: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_telemetry_messages_count",
+			Help:      "Number of telemetry messages sent, failed after retries or dropped because the queue was full",
+		}, []string{"result"}),
+		aggregatorTelemetryQueueSize: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_telemetry_queue_size",
+			Help:      "Number of telemetry messages waiting to be sent",
+		}),
+		aggregatorRespondToTaskReverts: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_respond_to_task_reverts_count",
+			Help:      "Number of respondToTaskV2 calls reverted by the Aligned Service Manager, by revert reason",
+		}, []string{"reason"}),
+		aggregatorUnconfirmedResponses: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_unconfirmed_responses",
+			Help:      "Number of respond to task transactions waiting for confirmations",
+		}),
+		aggregatorConfirmationEvents: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_respond_to_task_confirmation_events_count",
+			Help:      "Number of respond to task transactions confirmed, moved to another block, reorged out, broadcast again or re-submitted",
+		}, []string{"event"}),
+		aggregatorNonceResyncs: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_nonce_resyncs_count",
+			Help:      "Number of times the aggregator nonces were synced again with the node after a nonce error",
+		}),
+		aggregatorInFlightResponses: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_in_flight_responses",
+			Help:      "Number of aggregated responses being sent concurrently",
+		}),
+		aggregatorWalletBalance: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_wallet_balance",
+			Help:      "Balance in ETH of each aggregator wallet",
+		}, []string{"wallet"}),
+		aggregatorWalletLowBalance: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_wallet_low_balance",
+			Help:      "1 if the balance of the aggregator wallet is below wallet_min_balance, 0 otherwise",
+		}, []string{"wallet"}),
+		aggregatorWalletTransactions: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_wallet_transactions_count",
+			Help:      "Number of respond to task transactions sent from each aggregator wallet",
+		}, []string{"wallet"}),
+		aggregatorGasPolicyDecisions: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_gas_policy_decisions_count",
+			Help:      "Number of respond to task transactions evaluated by the gas spend policy, by action taken and limit hit",
+		}, []string{"action", "limit"}),
+		aggregatorGasSpendInWindow: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_gas_spend_in_window",
+			Help:      "ETH spent by the aggregator in respond to task transactions within the gas spend budget window",
+		}),
+		aggregatorDeferredResponses: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_deferred_responses",
+			Help:      "Number of aggregated responses deferred until the gas price drops",
+		}),
+		aggregatorDeferredResponsesSubmitted: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_deferred_responses_submitted_count",
+			Help:      "Number of deferred responses submitted, by reason: the gas price dropped or the max wait passed",
+		}, []string{"reason"}),
+		aggregatorBroadcastResults: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_broadcast_results_count",
+			Help:      "Number of respond to task transactions sent to each broadcast endpoint, by result: accepted or rejected",
+		}, []string{"endpoint", "result"}),
+		aggregatorBroadcastInclusions: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_broadcast_inclusions_count",
+			Help:      "Number of included respond to task transactions credited to each broadcast endpoint, the first one to accept them",
+		}, []string{"endpoint"}),
+		aggregatorInfeasibleTasks: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_infeasible_tasks_count",
+			Help:      "Number of tasks flagged as infeasible when created, by reason: batcher_balance or fee_limit",
+		}, []string{"reason"}),
+		aggregatorReceivedTasksByVersion: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_received_tasks_by_version_count",
+			Help:      "Number of tasks received by the aggregator, by version of the NewBatch event: v2 or v3",
+		}, []string{"version"}),
+		aggregatedResponsesByVersion: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregated_responses_by_version_count",
+			Help:      "Number of aggregated responses sent, by version of the NewBatch event of the task: v2 or v3",
+		}, []string{"version"}),
+		aggregatorAttestationCertificates: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_attestation_certificates_count",
+			Help:      "Number of attestation certificates of the tasks that reached quorum, by result: written or failed",
+		}, []string{"result"}),
 	}
 }
 
@@ -111,12 +346,14 @@ func (m *Metrics) Start(ctx context.Context, reg prometheus.Gatherer) <-chan err
 	return errC
 }
 
-func (m *Metrics) IncAggregatorReceivedTasks() {
+func (m *Metrics) IncAggregatorReceivedTasks(version string) {
 	m.numAggregatorReceivedTasks.Inc()
+	m.aggregatorReceivedTasksByVersion.WithLabelValues(version).Inc()
 }
 
-func (m *Metrics) IncAggregatedResponses() {
+func (m *Metrics) IncAggregatedResponses(version string) {
 	m.numAggregatedResponses.Inc()
+	m.aggregatedResponsesByVersion.WithLabelValues(version).Inc()
 }
 
 func (m *Metrics) IncOperatorTaskResponses() {
@@ -146,3 +383,160 @@ func (m *Metrics) ObserveLatencyForRespondToTask(elapsed time.Duration) {
 func (m *Metrics) ObserveTaskQuorumReached(elapsed time.Duration) {
 	m.aggregatorTaskQuorumReachedLatency.Set(elapsed.Seconds())
 }
+
+func (m *Metrics) IncDeadLetters() {
+	m.aggregatorDeadLetters.Inc()
+}
+
+func (m *Metrics) IncDeadLettersExpired() {
+	m.aggregatorDeadLettersExpired.Inc()
+}
+
+func (m *Metrics) SetPendingDeadLetters(count int) {
+	m.aggregatorDeadLettersPending.Set(float64(count))
+}
+
+func (m *Metrics) IncDeadLetterReplays(result string) {
+	m.aggregatorDeadLetterReplays.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) SetIsLeader(isLeader bool) {
+	if isLeader {
+		m.aggregatorIsLeader.Set(1)
+	} else {
+		m.aggregatorIsLeader.Set(0)
+	}
+}
+
+func (m *Metrics) IncLeaderTakeovers() {
+	m.aggregatorLeaderTakeovers.Inc()
+}
+
+func (m *Metrics) IncRejectedOperatorSignatures(reason string) {
+	m.aggregatorRejectedOperatorSignatures.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) SetRpcLimit(limit string, value float64) {
+	m.aggregatorRpcLimits.WithLabelValues(limit).Set(value)
+}
+
+func (m *Metrics) IncRpcRejectedCalls(reason string) {
+	m.aggregatorRpcRejectedCalls.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) IncRpcInflightCalls() {
+	m.aggregatorRpcInflightCalls.Inc()
+}
+
+func (m *Metrics) DecRpcInflightCalls() {
+	m.aggregatorRpcInflightCalls.Dec()
+}
+
+func (m *Metrics) IncOperatorTasksSigned(operatorId string) {
+	m.aggregatorOperatorTasksSigned.WithLabelValues(operatorId).Inc()
+}
+
+func (m *Metrics) IncOperatorTasksMissed(operatorId string) {
+	m.aggregatorOperatorTasksMissed.WithLabelValues(operatorId).Inc()
+}
+
+func (m *Metrics) IncOperatorInvalidSignatures(operatorId string) {
+	m.aggregatorOperatorInvalidSignatures.WithLabelValues(operatorId).Inc()
+}
+
+func (m *Metrics) ObserveOperatorResponseLatency(operatorId string, latency time.Duration) {
+	m.aggregatorOperatorResponseLatency.WithLabelValues(operatorId).Observe(latency.Seconds())
+}
+
+func (m *Metrics) SetOperatorLastSeen(operatorId string, lastSeen time.Time) {
+	m.aggregatorOperatorLastSeen.WithLabelValues(operatorId).Set(float64(lastSeen.Unix()))
+}
+
+func (m *Metrics) AddPendingOperatorResponses(event string, count int) {
+	m.aggregatorPendingOperatorResponses.WithLabelValues(event).Add(float64(count))
+}
+
+func (m *Metrics) SetPendingOperatorResponses(count int) {
+	m.aggregatorPendingOperatorResponsesSize.Set(float64(count))
+}
+
+func (m *Metrics) AddEvictedTasks(reason string, count int) {
+	m.aggregatorEvictedTasks.WithLabelValues(reason).Add(float64(count))
+}
+
+func (m *Metrics) SetTrackedTasks(count int) {
+	m.aggregatorTrackedTasks.Set(float64(count))
+}
+
+func (m *Metrics) AddTelemetryMessages(result string, count int) {
+	m.aggregatorTelemetryMessages.WithLabelValues(result).Add(float64(count))
+}
+
+func (m *Metrics) SetTelemetryQueueSize(size int) {
+	m.aggregatorTelemetryQueueSize.Set(float64(size))
+}
+
+func (m *Metrics) IncRespondToTaskReverts(reason string) {
+	m.aggregatorRespondToTaskReverts.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) SetUnconfirmedResponses(count int) {
+	m.aggregatorUnconfirmedResponses.Set(float64(count))
+}
+
+func (m *Metrics) IncConfirmationEvents(event string) {
+	m.aggregatorConfirmationEvents.WithLabelValues(event).Inc()
+}
+
+func (m *Metrics) IncNonceResyncs() {
+	m.aggregatorNonceResyncs.Inc()
+}
+
+func (m *Metrics) SetInFlightResponses(count int) {
+	m.aggregatorInFlightResponses.Set(float64(count))
+}
+
+func (m *Metrics) SetWalletBalance(wallet string, balance float64, lowBalance bool) {
+	m.aggregatorWalletBalance.WithLabelValues(wallet).Set(balance)
+	if lowBalance {
+		m.aggregatorWalletLowBalance.WithLabelValues(wallet).Set(1)
+	} else {
+		m.aggregatorWalletLowBalance.WithLabelValues(wallet).Set(0)
+	}
+}
+
+func (m *Metrics) IncWalletTransactions(wallet string) {
+	m.aggregatorWalletTransactions.WithLabelValues(wallet).Inc()
+}
+
+func (m *Metrics) IncGasPolicyDecisions(action string, limit string) {
+	m.aggregatorGasPolicyDecisions.WithLabelValues(action, limit).Inc()
+}
+
+func (m *Metrics) SetGasSpendInWindow(value float64) {
+	m.aggregatorGasSpendInWindow.Set(value)
+}
+
+func (m *Metrics) SetDeferredResponses(count int) {
+	m.aggregatorDeferredResponses.Set(float64(count))
+}
+
+func (m *Metrics) IncDeferredResponsesSubmitted(reason string) {
+	m.aggregatorDeferredResponsesSubmitted.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) IncBroadcastResults(endpoint string, result string) {
+	m.aggregatorBroadcastResults.WithLabelValues(endpoint, result).Inc()
+}
+
+func (m *Metrics) IncBroadcastInclusions(endpoint string) {
+	m.aggregatorBroadcastInclusions.WithLabelValues(endpoint).Inc()
+}
+
+func (m *Metrics) IncInfeasibleTasks(reason string) {
+	m.aggregatorInfeasibleTasks.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) IncAttestationCertificates(result string) {
+	m.aggregatorAttestationCertificates.WithLabelValues(result).Inc()
+}
@@ -52,7 +52,8 @@ type Operator struct {
 	NewTaskCreatedChanV2      chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2
 	NewTaskCreatedChanV3      chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3
 	Logger                    logging.Logger
-	aggRpcClient              AggregatorRpcClient
+	aggRpcClient              *AggregatorRpcClient
+	standbyAggRpcClients      []*AggregatorRpcClient
 	metricsReg                *prometheus.Registry
 	metrics                   *metrics.Metrics
 	lastProcessedBatch        OperatorLastProcessedBatch
@@ -96,6 +97,11 @@ func NewOperatorFromConfig(configuration config.OperatorConfig) (*Operator, erro
 		return nil, fmt.Errorf("could not create RPC client: %s. Is aggregator running?", err)
 	}
 
+	standbyRpcClients := make([]*AggregatorRpcClient, 0, len(configuration.Operator.StandbyAggregatorAddresses))
+	for _, standbyAddress := range configuration.Operator.StandbyAggregatorAddresses {
+		standbyRpcClients = append(standbyRpcClients, NewStandbyAggregatorRpcClient(standbyAddress, logger))
+	}
+
 	operatorId := eigentypes.OperatorIdFromKeyPair(configuration.BlsConfig.KeyPair)
 	address := configuration.Operator.Address
 	lastProcessedBatchLogFile := configuration.Operator.LastProcessedBatchFilePath
@@ -116,7 +122,8 @@ func NewOperatorFromConfig(configuration config.OperatorConfig) (*Operator, erro
 		Address:                   address,
 		NewTaskCreatedChanV2:      newTaskCreatedChanV2,
 		NewTaskCreatedChanV3:      newTaskCreatedChanV3,
-		aggRpcClient:              *rpcClient,
+		aggRpcClient:              rpcClient,
+		standbyAggRpcClients:      standbyRpcClients,
 		OperatorId:                operatorId,
 		metricsReg:                reg,
 		metrics:                   operatorMetrics,
@@ -332,8 +339,17 @@ func (o *Operator) handleNewBatchLogV2(newBatchLog *servicemanager.ContractAlign
 		hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
 	)
 
-	o.aggRpcClient.SendSignedTaskResponseToAggregator(&signedTaskResponse)
+	o.sendSignedTaskResponse(&signedTaskResponse)
+}
+
+// sendSignedTaskResponse sends the response to the standby aggregators in the background and to the aggregator
+func (o *Operator) sendSignedTaskResponse(signedTaskResponse *types.SignedTaskResponse) {
+	for _, standbyRpcClient := range o.standbyAggRpcClients {
+		go standbyRpcClient.SendSignedTaskResponseToAggregator(signedTaskResponse)
+	}
+	o.aggRpcClient.SendSignedTaskResponseToAggregator(signedTaskResponse)
 }
+
 func (o *Operator) ProcessNewBatchLogV2(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2) error {
 
 	o.Logger.Info("Received new batch with proofs to verify",
@@ -413,7 +429,7 @@ func (o *Operator) handleNewBatchLogV3(newBatchLog *servicemanager.ContractAlign
 		hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
 	)
 
-	o.aggRpcClient.SendSignedTaskResponseToAggregator(&signedTaskResponse)
+	o.sendSignedTaskResponse(&signedTaskResponse)
 }
 func (o *Operator) ProcessNewBatchLogV3(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3) error {
 
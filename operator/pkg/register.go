@@ -26,7 +26,14 @@ func RegisterOperator(
 
 	socket := "Not Needed"
 
+	// Operators register to quorum 0 unless other quorums are set in the config
 	quorumNumbers := types.QuorumNums{0}
+	if len(configuration.Operator.RegisterQuorumNumbers) > 0 {
+		quorumNumbers = make(types.QuorumNums, 0, len(configuration.Operator.RegisterQuorumNumbers))
+		for _, quorumNumber := range configuration.Operator.RegisterQuorumNumbers {
+			quorumNumbers = append(quorumNumbers, types.QuorumNum(quorumNumber))
+		}
+	}
 
 	_, err = writer.RegisterOperator(ctx, ecdsaConfig.PrivateKey,
 		configuration.BlsConfig.KeyPair,
@@ -3,15 +3,18 @@ package operator
 import (
 	"errors"
 	"net/rpc"
+	"sync"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/yetanotherco/aligned_layer/core/types"
 )
 
-// AggregatorRpcClient is the client to communicate with the aggregator via RPC
+// AggregatorRpcClient is the client to communicate with the aggregator via RPC.
+// Responses of different batches are sent concurrently, so the connection is guarded by rpcClientMutex
 type AggregatorRpcClient struct {
 	rpcClient            *rpc.Client
+	rpcClientMutex       sync.Mutex
 	aggregatorIpPortAddr string
 	logger               logging.Logger
 }
@@ -34,22 +37,35 @@ func NewAggregatorRpcClient(aggregatorIpPortAddr string, logger logging.Logger)
 	}, nil
 }
 
+// NewStandbyAggregatorRpcClient creates a client for a standby aggregator.
+// Standbys may be down when the operator starts, so the connection is established on the first send
+func NewStandbyAggregatorRpcClient(aggregatorIpPortAddr string, logger logging.Logger) *AggregatorRpcClient {
+	return &AggregatorRpcClient{
+		aggregatorIpPortAddr: aggregatorIpPortAddr,
+		logger:               logger,
+	}
+}
+
 // SendSignedTaskResponseToAggregator is the method called by operators via RPC to send
 // their signed task response.
 func (c *AggregatorRpcClient) SendSignedTaskResponseToAggregator(signedTaskResponse *types.SignedTaskResponse) {
 	var reply uint8
 	for retries := 0; retries < MaxRetries; retries++ {
-		err := c.rpcClient.Call("Aggregator.ProcessOperatorSignedTaskResponseV2", signedTaskResponse, &reply)
+		client, err := c.connect()
+		if err != nil {
+			c.logger.Error("Could not connect to aggregator", "address", c.aggregatorIpPortAddr, "err", err)
+			time.Sleep(RetryInterval)
+			continue
+		}
+		err = client.Call("Aggregator.ProcessOperatorSignedTaskResponseV2", signedTaskResponse, &reply)
 		if err != nil {
 			c.logger.Error("Received error from aggregator", "err", err)
 			if errors.Is(err, rpc.ErrShutdown) {
 				c.logger.Error("Aggregator is shutdown. Reconnecting...")
-				client, err := rpc.DialHTTP("tcp", c.aggregatorIpPortAddr)
-				if err != nil {
+				if err := c.reconnect(client); err != nil {
 					c.logger.Error("Could not reconnect to aggregator", "err", err)
 					time.Sleep(RetryInterval)
 				} else {
-					c.rpcClient = client
 					c.logger.Info("Reconnected to aggregator")
 				}
 			} else {
@@ -62,3 +78,35 @@ func (c *AggregatorRpcClient) SendSignedTaskResponseToAggregator(signedTaskRespo
 		}
 	}
 }
+
+// connect returns the current connection, dialing it if there is none yet
+func (c *AggregatorRpcClient) connect() (*rpc.Client, error) {
+	c.rpcClientMutex.Lock()
+	defer c.rpcClientMutex.Unlock()
+	if c.rpcClient == nil {
+		client, err := rpc.DialHTTP("tcp", c.aggregatorIpPortAddr)
+		if err != nil {
+			return nil, err
+		}
+		c.rpcClient = client
+	}
+	return c.rpcClient, nil
+}
+
+// reconnect replaces a shut down connection. If another send already replaced it, the new one is kept,
+// so concurrent sends failing on the same connection dial only once
+func (c *AggregatorRpcClient) reconnect(shutdown *rpc.Client) error {
+	c.rpcClientMutex.Lock()
+	defer c.rpcClientMutex.Unlock()
+	if c.rpcClient != shutdown && c.rpcClient != nil {
+		return nil
+	}
+	shutdown.Close()
+	c.rpcClient = nil
+	client, err := rpc.DialHTTP("tcp", c.aggregatorIpPortAddr)
+	if err != nil {
+		return err
+	}
+	c.rpcClient = client
+	return nil
+}
@@ -160,4 +160,73 @@ defmodule TelemetryApiWeb.TraceController do
       |> render(:show_merkle, merkle_root: merkle_root)
     end
   end
+
+  @doc """
+  Apply a batch of aggregator events in order, each as if it was posted to its own endpoint.
+  The aggregator sends its queued events in one batch on each flush. Events that fail are reported
+  by their index in the batch, the others are applied anyway
+  Method: POST aggregatorEvents
+  """
+  def aggregator_events(conn, %{"events" => events}) when is_list(events) do
+    errors =
+      events
+      |> Enum.with_index()
+      |> Enum.flat_map(fn {event, index} ->
+        case apply_aggregator_event(event) do
+          :ok -> []
+          {:error, _status, message} -> [%{index: index, error: error_message(message)}]
+          {:error, message} -> [%{index: index, error: error_message(message)}]
+        end
+      end)
+
+    conn
+    |> put_status(:ok)
+    |> render(:show_events, processed: length(events), errors: errors)
+  end
+
+  def aggregator_events(_conn, _params) do
+    {:error, :bad_request, "Expected a list of events"}
+  end
+
+  defp apply_aggregator_event(%{"event" => "initTaskTrace", "data" => %{"merkle_root" => merkle_root}}),
+    do: Traces.create_task_trace(merkle_root)
+
+  defp apply_aggregator_event(%{
+         "event" => "operatorResponse",
+         "data" => %{"merkle_root" => merkle_root, "operator_id" => operator_id}
+       }),
+       do: Traces.register_operator_response(merkle_root, operator_id)
+
+  defp apply_aggregator_event(%{"event" => "quorumReached", "data" => %{"merkle_root" => merkle_root}}),
+    do: Traces.quorum_reached(merkle_root)
+
+  defp apply_aggregator_event(%{
+         "event" => "taskError",
+         "data" => %{"merkle_root" => merkle_root, "error" => error}
+       }),
+       do: Traces.task_error(merkle_root, error)
+
+  defp apply_aggregator_event(%{
+         "event" => "aggregatorTaskSetGasPrice",
+         "data" => %{"merkle_root" => merkle_root, "gas_price" => gas_price}
+       }),
+       do: Traces.aggregator_task_set_gas_price(merkle_root, gas_price)
+
+  defp apply_aggregator_event(%{
+         "event" => "aggregatorTaskSent",
+         "data" => %{
+           "merkle_root" => merkle_root,
+           "tx_hash" => tx_hash,
+           "effective_gas_price" => effective_gas_price
+         }
+       }),
+       do: Traces.aggregator_task_sent(merkle_root, tx_hash, effective_gas_price)
+
+  defp apply_aggregator_event(%{"event" => "finishTaskTrace", "data" => %{"merkle_root" => merkle_root}}),
+    do: Traces.finish_task_trace(merkle_root)
+
+  defp apply_aggregator_event(_event), do: {:error, :bad_request, "Unknown or malformed event"}
+
+  defp error_message(message) when is_binary(message), do: message
+  defp error_message(message), do: inspect(message)
 end
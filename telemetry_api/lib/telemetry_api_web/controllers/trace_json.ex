@@ -16,4 +16,14 @@ defmodule TelemetryApiWeb.TraceJSON do
       operator_id: operator_id
     }
   end
+
+  @doc """
+  Renders the result of a batch of aggregator events, with the index and error of each failed event
+  """
+  def show_events(%{processed: processed, errors: errors}) do
+    %{
+      processed: processed,
+      errors: errors
+    }
+  end
 end
@@ -18,6 +18,7 @@ defmodule TelemetryApiWeb.Router do
     post "/aggregatorTaskSetGasPrice", TraceController, :aggregator_task_set_gas_price
     post "/aggregatorTaskSent", TraceController, :aggregator_task_sent
     post "/finishTaskTrace", TraceController, :finish_task_trace
+    post "/aggregatorEvents", TraceController, :aggregator_events
 
     post "/initBatcherTaskTrace", TraceController, :create_batcher_task_trace
     post "/batcherTaskUploadedToS3", TraceController, :batcher_task_uploaded_to_s3